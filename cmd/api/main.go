@@ -81,12 +81,18 @@ func main() {
     storyStore := storage.NewStoryStore(db.DB(), redisClient, zapLogger)
     followStore := storage.NewFollowStore(db.DB(), redisClient, zapLogger)
     viewStore := storage.NewViewStore(db.DB(), redisClient, zapLogger)
+    viewCounter := storage.NewViewCounter(redisClient, zapLogger)
+    presenceStore := storage.NewPresenceStore(redisClient, zapLogger)
     reactionStore := storage.NewReactionStore(db.DB(), redisClient, zapLogger)
+    reactionTypeStore := storage.NewReactionTypeStore(db.DB(), redisClient, zapLogger)
+    stickerStore := storage.NewStickerStore(db.DB(), redisClient, zapLogger)
+    messageStore := storage.NewMessageStore(db.DB(), redisClient, zapLogger)
+    blockStore := storage.NewBlockStore(db.DB(), redisClient, zapLogger)
 
     zapLogger.Info("Storage layer initialized successfully")
 
     // Initialize auth service
-    authService := auth.NewService(cfg, userStore, zapLogger)
+    authService := auth.NewService(cfg, userStore, redisClient, zapLogger)
 
     // Initialize media service
     mediaService, err := media.NewService(cfg, zapLogger)
@@ -95,7 +101,18 @@ func main() {
     }
 
     // Initialize WebSocket hub
-    wsHub := realtime.NewHub(zapLogger)
+    topicAuthorizer := realtime.NewTopicAuthorizer(storyStore, userStore, followStore, blockStore)
+    replayBuffer := realtime.NewReplayBuffer(redisClient, cfg.Realtime, zapLogger)
+    typingAuthorizer := realtime.NewTypingAuthorizer(messageStore, blockStore)
+    wsHub := realtime.NewHub(followStore, topicAuthorizer, typingAuthorizer, replayBuffer, metricsCollector, cfg.Realtime, zapLogger)
+    if cfg.Realtime.ClusterEnabled {
+        cluster := realtime.NewCluster(wsHub, redisClient, cfg.Realtime, zapLogger)
+        cluster.Start()
+    }
+    presence := realtime.NewPresence(wsHub, presenceStore, followStore, userStore, cfg.Realtime, zapLogger)
+    presence.Start()
+    revalidator := realtime.NewRevalidator(wsHub, authService, cfg.Realtime, zapLogger)
+    revalidator.Start()
     go wsHub.Run()
 
     zapLogger.Info("WebSocket hub started")
@@ -129,8 +146,10 @@ func main() {
     router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
     // WebSocket endpoint
-    wsHandler := handlers.NewWebSocketHandler(wsHub, authService, zapLogger)
+    ticketStore := realtime.NewTicketStore(redisClient, zapLogger)
+    wsHandler := handlers.NewWebSocketHandler(wsHub, authService, ticketStore, cfg, zapLogger)
     router.GET("/ws", wsHandler.HandleWebSocket)
+    router.GET("/events", wsHandler.HandleEvents)
 
     // API routes
     apiGroup := router.Group(cfg.APIPrefix)
@@ -152,36 +171,84 @@ func main() {
     // Protected routes
     protected := apiGroup.Group("")
     protected.Use(auth.RequireAuth(authService))
+    protected.Use(middleware.Activity(presence))
 
     // User routes
-    userHandler := handlers.NewUserHandler(userStore, followStore, zapLogger)
+    userHandler := handlers.NewUserHandler(userStore, followStore, blockStore, wsHub, presence, zapLogger)
     userGroup := protected.Group("/users")
     {
         userGroup.GET("/me", userHandler.GetCurrentUser)
         userGroup.PUT("/me", userHandler.UpdateCurrentUser)
         userGroup.GET("/search", userHandler.SearchUsers)
+        userGroup.GET("/presence", userHandler.GetPresence)
         userGroup.GET("/:id", userHandler.GetUser)
-        userGroup.POST("/:id/follow", userHandler.FollowUser)
+        userGroup.POST("/:id/follow", middleware.Idempotency(redisClient), userHandler.FollowUser)
         userGroup.DELETE("/:id/follow", userHandler.UnfollowUser)
         userGroup.GET("/:id/followers", userHandler.GetFollowers)
         userGroup.GET("/:id/following", userHandler.GetFollowing)
+        userGroup.POST("/:id/block", userHandler.BlockUser)
+        userGroup.DELETE("/:id/block", userHandler.UnblockUser)
     }
 
     // Story routes
-    storyHandler := handlers.NewStoryHandler(storyStore, viewStore, reactionStore, wsHub, zapLogger)
+    storyHandler := handlers.NewStoryHandler(storyStore, viewStore, viewCounter, reactionStore, reactionTypeStore, userStore, followStore, blockStore, wsHub, cfg.Stories, zapLogger)
+    liveStories := realtime.NewLiveStories(wsHub, storyHandler, zapLogger)
+    liveStories.Start()
+    stickerHandler := handlers.NewStickerHandler(storyStore, stickerStore, wsHub, zapLogger)
+    messageHandler := handlers.NewMessageHandler(storyStore, messageStore, blockStore, wsHub, zapLogger)
     storyGroup := protected.Group("/stories")
     {
         storyGroup.GET("", storyHandler.GetStories)
-        storyGroup.POST("", storyHandler.CreateStory)
+        storyGroup.POST("", middleware.Idempotency(redisClient), storyHandler.CreateStory)
+        storyGroup.GET("/drafts", storyHandler.GetDrafts)
+        storyGroup.GET("/archive", storyHandler.GetArchive)
+        storyGroup.GET("/nearby", storyHandler.GetNearbyStories)
         storyGroup.GET("/:id", storyHandler.GetStory)
         storyGroup.PUT("/:id", storyHandler.UpdateStory)
         storyGroup.DELETE("/:id", storyHandler.DeleteStory)
+        storyGroup.GET("/:id/revisions", storyHandler.GetStoryRevisions)
+        storyGroup.PUT("/:id/draft", storyHandler.UpdateDraft)
+        storyGroup.POST("/:id/publish", storyHandler.PublishDraft)
+        storyGroup.POST("/:id/repost", storyHandler.RepostStory)
         storyGroup.POST("/:id/view", storyHandler.ViewStory)
         storyGroup.GET("/:id/views", storyHandler.GetStoryViews)
+        storyGroup.GET("/:id/seen-by", storyHandler.GetSeenBy)
+        storyGroup.POST("/:id/segments/:segment_id/view", storyHandler.ViewSegment)
+        storyGroup.GET("/:id/dropoff", storyHandler.GetSegmentDropOff)
+        storyGroup.POST("/:id/stickers/:sticker_id/respond", stickerHandler.Respond)
+        storyGroup.GET("/:id/stickers/:sticker_id/results", stickerHandler.GetResults)
         storyGroup.GET("/:id/reactions", storyHandler.GetStoryReactions)
-        storyGroup.POST("/:id/reactions", storyHandler.AddReaction)
+        storyGroup.POST("/:id/reactions", middleware.Idempotency(redisClient), storyHandler.AddReaction)
         storyGroup.PUT("/:id/reactions/:reaction_id", storyHandler.UpdateReaction)
         storyGroup.DELETE("/:id/reactions/:reaction_id", storyHandler.RemoveReaction)
+        storyGroup.POST("/:id/reply", messageHandler.ReplyToStory)
+        storyGroup.POST("/:id/reshare", storyHandler.ReshareStory)
+        storyGroup.GET("/:id/metrics", storyHandler.GetStoryMetrics)
+    }
+
+    // Realtime routes
+    protected.POST("/realtime/ticket", wsHandler.IssueTicket)
+
+    // Hashtag routes
+    protected.GET("/hashtags/:tag/stories", storyHandler.GetHashtagStories)
+
+    // Reaction catalogue routes
+    reactionTypeHandler := handlers.NewReactionTypeHandler(reactionTypeStore, cfg.Stories.ReactionsAllowAnyEmoji, zapLogger)
+    protected.GET("/reaction-types", reactionTypeHandler.GetReactionTypes)
+
+    adminGroup := protected.Group("/admin", auth.AdminOnly())
+    {
+        adminGroup.POST("/reaction-types", reactionTypeHandler.CreateReactionType)
+        adminGroup.DELETE("/reaction-types/:type", reactionTypeHandler.RetireReactionType)
+    }
+
+    // Conversation routes
+    conversationGroup := protected.Group("/conversations")
+    {
+        conversationGroup.GET("", messageHandler.GetConversations)
+        conversationGroup.GET("/:id/messages", messageHandler.GetMessages)
+        conversationGroup.POST("/:id/messages", messageHandler.SendMessage)
+        conversationGroup.POST("/:id/read", messageHandler.MarkRead)
     }
 
     // Media routes
@@ -61,6 +61,7 @@ func RequireAuth(authService *Service) gin.HandlerFunc {
         // Set user in Gin context as well for easier access
         c.Set("user", user)
         c.Set("user_id", user.ID)
+        c.Set("token", token)
 
         c.Next()
     })
@@ -226,6 +227,17 @@ func GetCurrentUser(c *gin.Context) (*models.User, bool) {
     return userModel, ok
 }
 
+// GetCurrentToken helper function to get the validated bearer token from Gin context
+func GetCurrentToken(c *gin.Context) (string, bool) {
+    token, exists := c.Get("token")
+    if !exists {
+        return "", false
+    }
+
+    tokenString, ok := token.(string)
+    return tokenString, ok
+}
+
 // GetCurrentUserID helper function to get user ID from Gin context
 func GetCurrentUserID(c *gin.Context) (string, bool) {
     userID, exists := c.Get("user_id")
@@ -4,7 +4,9 @@ import (
     "context"
     "crypto/rand"
     "encoding/hex"
+    "errors"
     "fmt"
+    "strconv"
     "time"
 
     "github.com/golang-jwt/jwt/v5"
@@ -16,19 +18,25 @@ import (
     "github.com/Abhiro0p/stories-backend/pkg/config"
 )
 
+// ErrUserLookupFailed means a token's user couldn't be loaded, so the token
+// couldn't be checked, as opposed to the token being invalid
+var ErrUserLookupFailed = errors.New("failed to look up token user")
+
 // Service handles authentication operations
 type Service struct {
-    config    *config.Config
-    userStore storage.UserStore
-    logger    *zap.Logger
+    config      *config.Config
+    userStore   storage.UserStore
+    redisClient *storage.RedisClient
+    logger      *zap.Logger
 }
 
-// NewService creates a new auth service
-func NewService(cfg *config.Config, userStore storage.UserStore, logger *zap.Logger) *Service {
+// NewService creates a new auth service. Revoked tokens are kept in Redis.
+func NewService(cfg *config.Config, userStore storage.UserStore, redisClient *storage.RedisClient, logger *zap.Logger) *Service {
     return &Service{
-        config:    cfg,
-        userStore: userStore,
-        logger:    logger.With(zap.String("component", "auth_service")),
+        config:      cfg,
+        userStore:   userStore,
+        redisClient: redisClient,
+        logger:      logger.With(zap.String("component", "auth_service")),
     }
 }
 
@@ -110,6 +118,12 @@ func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (*model
         s.logger.Warn("Invalid refresh token", zap.Error(err))
         return nil, fmt.Errorf("invalid refresh token")
     }
+
+    // Refresh tokens share their access token's ID, so logging out ends both
+    if s.isRevoked(ctx, claims.TokenID, claims.UserID, claims.IssuedAt) {
+        s.logger.Warn("Revoked refresh token", zap.String("user_id", claims.UserID.String()))
+        return nil, fmt.Errorf("token has been revoked")
+    }
     
     // Get user
     user, err := s.userStore.GetByID(ctx, claims.UserID)
@@ -153,7 +167,7 @@ func (s *Service) ValidateToken(tokenString string) (*models.User, error) {
     ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
     defer cancel()
     
-    if s.IsTokenBlacklisted(ctx, claims.TokenID) {
+    if s.isRevoked(ctx, claims.TokenID, claims.UserID, claims.IssuedAt) {
         return nil, fmt.Errorf("token has been revoked")
     }
     
@@ -163,7 +177,10 @@ func (s *Service) ValidateToken(tokenString string) (*models.User, error) {
     
     user, err := s.userStore.GetByID(userCtx, claims.UserID)
     if err != nil {
-        return nil, fmt.Errorf("user not found: %w", err)
+        if err == storage.ErrNotFound {
+            return nil, fmt.Errorf("user not found: %w", err)
+        }
+        return nil, fmt.Errorf("%w: %v", ErrUserLookupFailed, err)
     }
     
     // Check if user is active
@@ -187,9 +204,9 @@ func (s *Service) Logout(ctx context.Context, userID uuid.UUID, tokenID string)
     
     // Add token to blacklist if tokenID is provided
     if tokenID != "" {
-        blacklistKey := fmt.Sprintf("blacklist:token:%s", tokenID)
-        // Set with same TTL as token expiry
-        ttl := time.Duration(s.config.JWTExpiryHours) * time.Hour
+        blacklistKey := blacklistTokenKey(tokenID)
+        // Kept until the refresh token, which shares the ID, expires too
+        ttl := time.Duration(s.config.JWTRefreshExpiryDays) * 24 * time.Hour
         
         if err := s.addToBlacklist(timeoutCtx, blacklistKey, ttl); err != nil {
             s.logger.Error("Failed to blacklist token", 
@@ -353,21 +370,14 @@ func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, currentP
 
 // addToBlacklist adds a token to the blacklist
 func (s *Service) addToBlacklist(ctx context.Context, key string, ttl time.Duration) error {
-    // TODO: Implement actual blacklist storage using Redis
-    // For now, just simulate the operation with timeout handling
-    
-    select {
-    case <-ctx.Done():
-        return ctx.Err()
-    default:
-        // Simulate some work
-        time.Sleep(10 * time.Millisecond)
-        s.logger.Debug("Token added to blacklist", 
-            zap.String("key", key),
-            zap.Duration("ttl", ttl),
-        )
+    if err := s.redisClient.GetClient().Set(ctx, key, 1, ttl).Err(); err != nil {
+        return fmt.Errorf("failed to blacklist token: %w", err)
     }
-    
+
+    s.logger.Debug("Token added to blacklist", 
+        zap.String("key", key),
+        zap.Duration("ttl", ttl),
+    )
     return nil
 }
 
@@ -377,48 +387,79 @@ func (s *Service) IsTokenBlacklisted(ctx context.Context, tokenID string) bool {
         return false
     }
     
-    blacklistKey := fmt.Sprintf("blacklist:token:%s", tokenID)
-    
-    // TODO: Implement actual blacklist check using Redis
-    // This would typically check Redis with a timeout
-    
-    select {
-    case <-ctx.Done():
-        s.logger.Warn("Blacklist check timeout", zap.String("token_id", tokenID))
-        return false // Default to allowing access on timeout
-    default:
-        s.logger.Debug("Checking token blacklist", 
+    exists, err := s.redisClient.Exists(ctx, blacklistTokenKey(tokenID))
+    if err != nil {
+        s.logger.Warn("Failed to check token blacklist", 
             zap.String("token_id", tokenID),
-            zap.String("key", blacklistKey),
+            zap.Error(err),
         )
+        return false // Default to allowing access when Redis is unavailable
     }
     
-    return false // For now, no tokens are blacklisted
+    return exists
 }
 
 // RevokeAllUserTokens revokes all tokens for a user (useful for security incidents)
 func (s *Service) RevokeAllUserTokens(ctx context.Context, userID uuid.UUID) error {
     s.logger.Info("Revoking all tokens for user", zap.String("user_id", userID.String()))
     
-    // TODO: Implement user token revocation
-    // This would typically:
-    // 1. Find all active sessions for the user
-    // 2. Add all token IDs to blacklist
-    // 3. Update user's security timestamp to invalidate old tokens
-    
     timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
     defer cancel()
     
-    select {
-    case <-timeoutCtx.Done():
-        return timeoutCtx.Err()
-    default:
-        s.logger.Info("All tokens revoked for user", zap.String("user_id", userID.String()))
+    // Tokens issued up to now are revoked; the mark outlives every refresh token
+    ttl := time.Duration(s.config.JWTRefreshExpiryDays) * 24 * time.Hour
+    if err := s.redisClient.GetClient().Set(timeoutCtx, blacklistUserKey(userID), time.Now().Unix(), ttl).Err(); err != nil {
+        return fmt.Errorf("failed to revoke user tokens: %w", err)
     }
     
+    s.logger.Info("All tokens revoked for user", zap.String("user_id", userID.String()))
     return nil
 }
 
+// isRevoked checks if a token was logged out, or issued before all of its
+// user's tokens were revoked. Like IsTokenBlacklisted it allows the token when
+// Redis is unavailable.
+func (s *Service) isRevoked(ctx context.Context, tokenID string, userID uuid.UUID, issuedAt *jwt.NumericDate) bool {
+    keys := []string{blacklistUserKey(userID)}
+    if tokenID != "" {
+        keys = append(keys, blacklistTokenKey(tokenID))
+    }
+
+    values, err := s.redisClient.GetClient().MGet(ctx, keys...).Result()
+    if err != nil {
+        s.logger.Warn("Failed to check token blacklist", 
+            zap.String("user_id", userID.String()),
+            zap.String("token_id", tokenID),
+            zap.Error(err),
+        )
+        return false
+    }
+
+    if len(values) > 1 && values[1] != nil {
+        return true
+    }
+
+    if revokedAt, ok := values[0].(string); ok {
+        revokedUnix, err := strconv.ParseInt(revokedAt, 10, 64)
+        // Tokens issued in the same second as the revocation are revoked too
+        if err != nil || issuedAt == nil || issuedAt.Unix() <= revokedUnix {
+            return true
+        }
+    }
+
+    return false
+}
+
+// blacklistTokenKey marks a single logged out token
+func blacklistTokenKey(tokenID string) string {
+    return fmt.Sprintf("blacklist:token:%s", tokenID)
+}
+
+// blacklistUserKey holds when all of a user's tokens were last revoked
+func blacklistUserKey(userID uuid.UUID) string {
+    return fmt.Sprintf("blacklist:user:%s", userID.String())
+}
+
 // ValidateTokenWithContext validates token with custom context (useful for middleware)
 func (s *Service) ValidateTokenWithContext(ctx context.Context, tokenString string) (*models.User, error) {
     // Parse token
@@ -439,7 +480,7 @@ func (s *Service) ValidateTokenWithContext(ctx context.Context, tokenString stri
     }
     
     // Check if token is blacklisted with provided context
-    if s.IsTokenBlacklisted(ctx, claims.TokenID) {
+    if s.isRevoked(ctx, claims.TokenID, claims.UserID, claims.IssuedAt) {
         return nil, fmt.Errorf("token has been revoked")
     }
     
@@ -0,0 +1,113 @@
+package auth
+
+import (
+    "context"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/golang-jwt/jwt/v5"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// newTestService creates a service with an in-process Redis and no user store;
+// revoked tokens are rejected before their user is looked up
+func newTestService(t *testing.T) *Service {
+    t.Helper()
+
+    server := miniredis.RunT(t)
+    cfg := &config.Config{
+        RedisURL:             "redis://" + server.Addr(),
+        JWTSecret:            "test-secret",
+        JWTRefreshSecret:     "test-refresh-secret",
+        JWTExpiryHours:       1,
+        JWTRefreshExpiryDays: 7,
+    }
+
+    redisClient, err := storage.NewRedisClient(cfg, zap.NewNop())
+    if err != nil {
+        t.Fatalf("failed to connect to test Redis: %v", err)
+    }
+    t.Cleanup(func() { redisClient.Close() })
+
+    return NewService(cfg, nil, redisClient, zap.NewNop())
+}
+
+func TestLogoutRevokesTokens(t *testing.T) {
+    service := newTestService(t)
+    ctx := context.Background()
+    user := &models.User{ID: uuid.New(), Username: "user"}
+
+    tokens, err := service.generateTokens(ctx, user)
+    if err != nil {
+        t.Fatalf("failed to generate tokens: %v", err)
+    }
+    other, err := service.generateTokens(ctx, user)
+    if err != nil {
+        t.Fatalf("failed to generate tokens: %v", err)
+    }
+
+    claims, err := service.GetTokenClaims(tokens.AccessToken)
+    if err != nil {
+        t.Fatalf("failed to read claims: %v", err)
+    }
+    if err := service.Logout(ctx, user.ID, claims.TokenID); err != nil {
+        t.Fatalf("failed to log out: %v", err)
+    }
+
+    if !service.IsTokenBlacklisted(ctx, claims.TokenID) {
+        t.Error("logged out token isn't blacklisted")
+    }
+    if _, err := service.ValidateToken(tokens.AccessToken); err == nil || !strings.Contains(err.Error(), "revoked") {
+        t.Errorf("validating logged out token: err = %v, want revoked", err)
+    }
+    if _, err := service.RefreshToken(ctx, tokens.RefreshToken); err == nil {
+        t.Error("refreshed a logged out token")
+    }
+
+    otherClaims, err := service.GetTokenClaims(other.AccessToken)
+    if err != nil {
+        t.Fatalf("failed to read claims: %v", err)
+    }
+    if service.IsTokenBlacklisted(ctx, otherClaims.TokenID) {
+        t.Error("another session's token is blacklisted")
+    }
+}
+
+func TestRevokeAllUserTokens(t *testing.T) {
+    service := newTestService(t)
+    ctx := context.Background()
+    userID := uuid.New()
+    otherUserID := uuid.New()
+
+    if err := service.RevokeAllUserTokens(ctx, userID); err != nil {
+        t.Fatalf("failed to revoke tokens: %v", err)
+    }
+
+    now := time.Now()
+    tests := []struct {
+        name     string
+        userID   uuid.UUID
+        issuedAt time.Time
+        want     bool
+    }{
+        {name: "issued before", userID: userID, issuedAt: now.Add(-time.Hour), want: true},
+        {name: "issued after", userID: userID, issuedAt: now.Add(2 * time.Second), want: false},
+        {name: "other user", userID: otherUserID, issuedAt: now.Add(-time.Hour), want: false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := service.isRevoked(ctx, uuid.NewString(), tt.userID, jwt.NewNumericDate(tt.issuedAt))
+            if got != tt.want {
+                t.Errorf("revoked = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+)
+
+// GetArchive gets the current user's archived stories
+func (h *StoryHandler) GetArchive(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse query parameters
+    limit := 20
+    if l := c.Query("limit"); l != "" {
+        if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+            limit = parsed
+        }
+    }
+
+    offset := 0
+    if o := c.Query("offset"); o != "" {
+        if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+            offset = parsed
+        }
+    }
+
+    stories, err := h.storyStore.GetArchived(c.Request.Context(), user.ID, limit, offset)
+    if err != nil {
+        h.logger.Error("Failed to get archived stories",
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get archived stories",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "stories": stories,
+        "count":   len(stories),
+    })
+}
+
+// RepostStory publishes a fresh copy of an archived story
+func (h *StoryHandler) RepostStory(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story ID
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to get story for repost",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return
+    }
+
+    // Archives are private, so don't reveal other users' archived stories
+    if story.AuthorID != user.ID {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error":   "not_found",
+            "message": "Story not found",
+        })
+        return
+    }
+
+    if !story.IsArchived() {
+        c.JSON(http.StatusConflict, gin.H{
+            "error":   "not_archived",
+            "message": "Only archived stories can be reposted",
+        })
+        return
+    }
+
+    // A reshare points at someone else's story, which has expired too
+    if story.IsReshare() {
+        c.JSON(http.StatusConflict, gin.H{
+            "error":   "repost_not_allowed",
+            "message": "Reshares can't be reposted",
+        })
+        return
+    }
+
+    repost := story.NewRepost()
+
+    // Mentions are resolved again, since blocks may have changed
+    h.resolveEntities(c.Request.Context(), repost)
+
+    if err := h.storyStore.Create(c.Request.Context(), repost); err != nil {
+        h.logger.Error("Failed to repost story",
+            zap.String("story_id", storyID.String()),
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "create_failed",
+            "message": "Failed to repost story",
+        })
+        return
+    }
+
+    h.logger.Info("Story reposted from archive",
+        zap.String("story_id", repost.ID.String()),
+        zap.String("archived_story_id", storyID.String()),
+        zap.String("user_id", user.ID.String()),
+    )
+
+    h.broadcastStory(c.Request.Context(), repost, user)
+
+    h.notifyMentions(repost, user, nil)
+
+    c.JSON(http.StatusCreated, repost)
+}
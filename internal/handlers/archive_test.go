@@ -0,0 +1,41 @@
+package handlers
+
+import (
+    "net/http"
+    "testing"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/geohash"
+)
+
+func TestRepostKeepsGeohash(t *testing.T) {
+    handler, stores := newTestStoryHandler()
+    author := newTestUser("author")
+
+    text := "sunset"
+    latitude, longitude := 48.8584, 2.2945
+    story := models.NewStory(author.ID, models.StoryCreateRequest{
+        Type:       models.StoryTypeText,
+        Text:       &text,
+        Visibility: models.VisibilityPublic,
+        Location:   &models.LocationRequest{Latitude: &latitude, Longitude: &longitude},
+    })
+    story.Archive()
+    stores.stories.add(story)
+
+    recorder, _ := serve(t, handler.RepostStory, author, http.MethodPost, nil, idParam(story.ID))
+    checkStatus(t, recorder, nil, http.StatusCreated, "")
+
+    if len(stores.stories.created) != 1 {
+        t.Fatalf("created %d stories, want 1", len(stores.stories.created))
+    }
+    repost := stores.stories.created[0]
+    if !repost.HasCoordinates() {
+        t.Fatal("repost has no coordinates")
+    }
+
+    want := geohash.Encode(*story.Latitude, *story.Longitude, models.LocationGeohashPrecision)
+    if repost.Geohash == nil || *repost.Geohash != want {
+        t.Errorf("repost geohash = %v, want %s", repost.Geohash, want)
+    }
+}
@@ -163,8 +163,11 @@ func (h *AuthHandler) Logout(c *gin.Context) {
         return
     }
 
-    // Get token ID from context (if available)
-    tokenID := c.GetString("token_id")
+    // The token's ID is what gets blacklisted, for it and its refresh token
+    var tokenID string
+    if claims, err := h.authService.GetTokenClaims(c.GetString("token")); err == nil {
+        tokenID = claims.TokenID
+    }
 
     // Logout user
     if err := h.authService.Logout(c.Request.Context(), user.ID, tokenID); err != nil {
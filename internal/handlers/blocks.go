@@ -0,0 +1,143 @@
+package handlers
+
+import (
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+)
+
+// BlockUser blocks a user
+func (h *UserHandler) BlockUser(c *gin.Context) {
+    currentUser, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse target user ID
+    targetUserID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid user ID",
+        })
+        return
+    }
+
+    // Can't block yourself
+    if currentUser.ID == targetUserID {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_action",
+            "message": "Cannot block yourself",
+        })
+        return
+    }
+
+    // Check if target user exists
+    if _, err := h.userStore.GetByID(c.Request.Context(), targetUserID); err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "User not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to get target user for block",
+            zap.String("target_user_id", targetUserID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get user",
+        })
+        return
+    }
+
+    block := models.NewBlock(currentUser.ID, targetUserID)
+    if err := h.blockStore.Create(c.Request.Context(), block); err != nil {
+        if err == storage.ErrAlreadyExists {
+            c.JSON(http.StatusConflict, gin.H{
+                "error":   "already_blocked",
+                "message": "User is already blocked",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to block user",
+            zap.String("blocker_id", currentUser.ID.String()),
+            zap.String("blocked_id", targetUserID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "block_failed",
+            "message": "Failed to block user",
+        })
+        return
+    }
+
+    // Drop the blocked user's subscriptions to this user's topics
+    if h.wsHub != nil {
+        h.wsHub.RecheckUserSubscriptions(targetUserID)
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": "User blocked successfully",
+    })
+}
+
+// UnblockUser unblocks a user
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+    currentUser, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse target user ID
+    targetUserID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid user ID",
+        })
+        return
+    }
+
+    if err := h.blockStore.Delete(c.Request.Context(), currentUser.ID, targetUserID); err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_blocked",
+                "message": "User is not blocked",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to unblock user",
+            zap.String("blocker_id", currentUser.ID.String()),
+            zap.String("blocked_id", targetUserID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "unblock_failed",
+            "message": "Failed to unblock user",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "User unblocked successfully",
+    })
+}
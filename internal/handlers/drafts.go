@@ -0,0 +1,239 @@
+package handlers
+
+import (
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/validator"
+)
+
+// GetDrafts gets the current user's unpublished drafts
+func (h *StoryHandler) GetDrafts(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse query parameters
+    limit := 20
+    if l := c.Query("limit"); l != "" {
+        if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+            limit = parsed
+        }
+    }
+
+    offset := 0
+    if o := c.Query("offset"); o != "" {
+        if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+            offset = parsed
+        }
+    }
+
+    drafts, err := h.storyStore.GetDrafts(c.Request.Context(), user.ID, limit, offset)
+    if err != nil {
+        h.logger.Error("Failed to get drafts",
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get drafts",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "drafts": drafts,
+        "count":  len(drafts),
+    })
+}
+
+// UpdateDraft updates an unpublished draft
+func (h *StoryHandler) UpdateDraft(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story ID
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    var req models.DraftUpdateRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        h.logger.Warn("Invalid update draft request", zap.Error(err))
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_request",
+            "message": "Invalid request body",
+        })
+        return
+    }
+
+    // Validate request
+    if err := validator.ValidateStruct(&req); err != nil {
+        h.logger.Warn("Update draft validation failed", zap.Error(err))
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "validation_failed",
+            "message": "Request validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    story, ok := h.getOwnDraft(c, user.ID, storyID)
+    if !ok {
+        return
+    }
+
+    // Update draft
+    story.UpdateDraft(req)
+    if req.Text != nil {
+        h.resolveEntities(c.Request.Context(), story)
+    }
+
+    // Save to database
+    if err := h.storyStore.UpdateDraft(c.Request.Context(), story); err != nil {
+        h.logger.Error("Failed to update draft",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "update_failed",
+            "message": "Failed to update draft",
+        })
+        return
+    }
+
+    if req.Text != nil {
+        if err := h.storyStore.SetEntities(c.Request.Context(), story.ID, story.Entities); err != nil {
+            h.logger.Error("Failed to update draft entities",
+                zap.String("story_id", storyID.String()),
+                zap.Error(err),
+            )
+        }
+    }
+
+    h.logger.Info("Draft updated successfully",
+        zap.String("story_id", storyID.String()),
+        zap.String("user_id", user.ID.String()),
+    )
+
+    c.JSON(http.StatusOK, story)
+}
+
+// PublishDraft publishes a draft and starts its expiry clock
+func (h *StoryHandler) PublishDraft(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story ID
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    story, ok := h.getOwnDraft(c, user.ID, storyID)
+    if !ok {
+        return
+    }
+
+    // Publish draft
+    story.Publish()
+
+    if err := h.storyStore.Publish(c.Request.Context(), story); err != nil {
+        h.logger.Error("Failed to publish draft",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "publish_failed",
+            "message": "Failed to publish draft",
+        })
+        return
+    }
+
+    h.logger.Info("Draft published successfully",
+        zap.String("story_id", storyID.String()),
+        zap.String("user_id", user.ID.String()),
+    )
+
+    // Send real-time notification
+    h.broadcastStory(c.Request.Context(), story, user)
+
+    h.notifyMentions(story, user, nil)
+
+    c.JSON(http.StatusOK, story)
+}
+
+// getOwnDraft loads a draft owned by userID, writing an error response if it can't
+func (h *StoryHandler) getOwnDraft(c *gin.Context, userID, storyID uuid.UUID) (*models.Story, bool) {
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Draft not found",
+            })
+            return nil, false
+        }
+
+        h.logger.Error("Failed to get draft",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get draft",
+        })
+        return nil, false
+    }
+
+    if !story.CanEdit(userID) {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error":   "forbidden",
+            "message": "You don't have permission to edit this draft",
+        })
+        return nil, false
+    }
+
+    if !story.IsDraft() {
+        c.JSON(http.StatusConflict, gin.H{
+            "error":   "already_published",
+            "message": "Story has already been published",
+        })
+        return nil, false
+    }
+
+    return story, true
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/realtime"
+)
+
+// GetHashtagStories gets active public stories tagged with a hashtag
+func (h *StoryHandler) GetHashtagStories(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    tag := models.NormalizeHashtag(c.Param("tag"))
+    if tag == "" {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_tag",
+            "message": "Invalid hashtag",
+        })
+        return
+    }
+
+    // Parse query parameters
+    limit := 20
+    if l := c.Query("limit"); l != "" {
+        if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+            limit = parsed
+        }
+    }
+
+    offset := 0
+    if o := c.Query("offset"); o != "" {
+        if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+            offset = parsed
+        }
+    }
+
+    stories, err := h.storyStore.GetByHashtag(c.Request.Context(), tag, limit, offset)
+    if err != nil {
+        h.logger.Error("Failed to get hashtag stories",
+            zap.String("tag", tag),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get stories",
+        })
+        return
+    }
+
+    for i, story := range stories {
+        stories[i] = story.ForViewer(user.ID)
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "tag":     tag,
+        "stories": stories,
+        "count":   len(stories),
+    })
+}
+
+// resolveEntities parses mentions and hashtags from the story text and links mentions to users.
+// Unknown users, self-mentions and users who blocked the author are dropped.
+func (h *StoryHandler) resolveEntities(ctx context.Context, story *models.Story) {
+    story.Entities = nil
+    if story.Text == nil {
+        return
+    }
+
+    entities := models.ParseStoryEntities(story.ID, *story.Text)
+
+    mentioned := make(map[string]*uuid.UUID)
+    for _, username := range models.MentionedUsernames(entities) {
+        if len(mentioned) >= models.MaxStoryMentions {
+            break
+        }
+
+        mentionedUser, err := h.userStore.GetByUsername(ctx, username)
+        if err != nil {
+            continue
+        }
+        if mentionedUser.ID == story.AuthorID {
+            continue
+        }
+
+        blocked, err := h.blockStore.IsBlocked(ctx, mentionedUser.ID, story.AuthorID)
+        if err != nil {
+            h.logger.Warn("Failed to check block status for mention",
+                zap.String("author_id", story.AuthorID.String()),
+                zap.String("mentioned_id", mentionedUser.ID.String()),
+                zap.Error(err),
+            )
+            continue
+        }
+        if blocked {
+            continue
+        }
+
+        userID := mentionedUser.ID
+        mentioned[username] = &userID
+    }
+
+    for _, entity := range entities {
+        if entity.Type == models.EntityTypeMention {
+            userID, ok := mentioned[entity.Value]
+            if !ok {
+                continue
+            }
+            entity.UserID = userID
+        }
+        story.Entities = append(story.Entities, entity)
+    }
+}
+
+// notifyMentions notifies mentioned users, skipping any in alreadyNotified
+func (h *StoryHandler) notifyMentions(story *models.Story, author *models.User, alreadyNotified []uuid.UUID) {
+    if h.wsHub == nil || story.IsDraft() {
+        return
+    }
+
+    skip := make(map[uuid.UUID]bool)
+    for _, userID := range alreadyNotified {
+        skip[userID] = true
+    }
+
+    for _, userID := range models.MentionedUserIDs(story.Entities) {
+        if skip[userID] {
+            continue
+        }
+
+        event := realtime.NotificationEvent(
+            "mention",
+            "You were mentioned",
+            author.Username+" mentioned you in their story",
+            map[string]interface{}{
+                "story_id":    story.ID,
+                "author":      author.ToResponse(),
+                "can_reshare": true,
+            },
+        )
+        h.wsHub.SendToUser(userID, event)
+    }
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+// checkCanReact enforces the story's reaction settings for userID,
+// writing an error response if the user can't react
+func (h *StoryHandler) checkCanReact(c *gin.Context, story *models.Story, userID uuid.UUID) bool {
+    isFollower := false
+    if story.ReactionsRequireFollow() && userID != story.AuthorID {
+        following, err := h.followStore.IsFollowing(c.Request.Context(), userID, story.AuthorID)
+        if err != nil {
+            h.logger.Error("Failed to check follow status for reaction",
+                zap.String("story_id", story.ID.String()),
+                zap.String("user_id", userID.String()),
+                zap.Error(err),
+            )
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error":   "fetch_failed",
+                "message": "Failed to check reaction permissions",
+            })
+            return false
+        }
+        isFollower = following
+    }
+
+    if story.CanReact(userID, isFollower) {
+        return true
+    }
+
+    if story.AllowReactions == models.ReactionAudienceFollowers {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error":   "reactions_followers_only",
+            "message": "Only followers can react to this story",
+        })
+        return false
+    }
+
+    c.JSON(http.StatusForbidden, gin.H{
+        "error":   "reactions_disabled",
+        "message": "Reactions are turned off for this story",
+    })
+    return false
+}
+
+// canView checks if viewerID can see the story, including the follow check
+// Story.CanView leaves to the caller for friends-only stories and the author's
+// blocks
+func (h *StoryHandler) canView(ctx context.Context, story *models.Story, viewerID uuid.UUID) (bool, error) {
+    if !story.CanView(&viewerID) {
+        return false, nil
+    }
+    if story.AuthorID == viewerID {
+        return true, nil
+    }
+
+    blocked, err := h.blockStore.IsBlocked(ctx, story.AuthorID, viewerID)
+    if err != nil {
+        return false, err
+    }
+    if blocked {
+        return false, nil
+    }
+
+    if story.Visibility == models.VisibilityFriends {
+        return h.followStore.IsFollowing(ctx, viewerID, story.AuthorID)
+    }
+
+    return true, nil
+}
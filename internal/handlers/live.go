@@ -0,0 +1,183 @@
+package handlers
+
+import (
+    "context"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/realtime"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+)
+
+// broadcastStory announces a newly published story to the author's followers and
+// to subscribers of the author's and the story's hashtag topics
+func (h *StoryHandler) broadcastStory(ctx context.Context, story *models.Story, author *models.User) {
+    if h.wsHub == nil || story.IsDraft() {
+        return
+    }
+
+    // Users the author blocked can't see the story, even if they still follow
+    // the author or subscribe to one of its hashtags. Nobody gets the event if
+    // they can't be looked up; the story still shows up in their feeds.
+    blockedIDs, err := h.blockStore.GetBlockedIDs(ctx, author.ID)
+    if err != nil {
+        h.logger.Error("Failed to get blocked users for story broadcast",
+            zap.String("story_id", story.ID.String()),
+            zap.Error(err),
+        )
+        return
+    }
+
+    event := &realtime.Event{
+        Type: realtime.EventStoryCreated,
+        Payload: gin.H{
+            "story":  story.ForViewer(uuid.Nil),
+            "author": author.ToResponse(),
+        },
+    }
+    h.wsHub.BroadcastToFollowersExcept(author.ID, story.Visibility, event, blockedIDs)
+
+    // Anyone can subscribe to a hashtag, so only public stories go there
+    if story.Visibility != models.VisibilityPublic {
+        return
+    }
+    for _, tag := range models.Hashtags(story.Entities) {
+        h.wsHub.PublishToTopicExcept(realtime.HashtagTopic(tag), event, blockedIDs)
+    }
+}
+
+// liveMetrics gets a story's metrics including views not yet flushed to Postgres
+func (h *StoryHandler) liveMetrics(ctx context.Context, storyID uuid.UUID) (*storage.StoryMetrics, error) {
+    metrics, err := h.storyStore.GetMetrics(ctx, storyID)
+    if err != nil {
+        return nil, err
+    }
+
+    if pending, err := h.viewCounter.GetPending(ctx, storyID); err == nil {
+        metrics.Views += int(pending.TotalViews)
+        metrics.UniqueViews += int(pending.UniqueViews)
+    }
+
+    return metrics, nil
+}
+
+// recordView counts a view of a story and, on the viewer's first view, adds
+// them to the viewer list and tells the author. Failures are logged, as views
+// aren't critical.
+func (h *StoryHandler) recordView(ctx context.Context, story *models.Story, user *models.User, ipAddress, userAgent string) {
+    // Count the view in Redis; the counters are flushed to Postgres by the worker
+    firstView, err := h.viewCounter.Record(ctx, story, user.ID)
+    if err != nil {
+        h.logger.Warn("Failed to count story view", 
+            zap.String("story_id", story.ID.String()),
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        // Still make sure the viewer shows up in the viewer list
+        firstView = true
+    }
+
+    // Repeat views change the total, so the counters change on every view
+    h.storyCountersChanged(story.ID)
+
+    // Repeat views only count towards the total
+    if !firstView {
+        return
+    }
+
+    view := models.NewStoryView(story, user, &ipAddress, &userAgent)
+
+    // Save view
+    if err := h.viewStore.Create(ctx, view); err != nil {
+        h.logger.Error("Failed to create story view", 
+            zap.String("story_id", story.ID.String()),
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+    } else {
+        h.logger.Info("Story view recorded", 
+            zap.String("story_id", story.ID.String()),
+            zap.String("viewer_id", user.ID.String()),
+        )
+
+        // Send real-time notification to story author, without the viewer
+        // if they view public stories anonymously
+        if h.wsHub != nil && story.AuthorID != user.ID {
+            payload := gin.H{"story_id": story.ID}
+            if !view.IsAnonymous {
+                payload["viewer"] = user.ToResponse()
+            }
+            event := &realtime.Event{
+                Type:    realtime.EventStoryViewed,
+                Payload: payload,
+            }
+            h.wsHub.SendToUser(story.AuthorID, event)
+        }
+    }
+}
+
+// RecordView records a story view sent over a realtime connection, the same
+// way ViewStory does
+func (h *StoryHandler) RecordView(ctx context.Context, viewer *models.User, storyID uuid.UUID, ipAddress, userAgent string) error {
+    story, err := h.storyStore.GetByID(ctx, storyID)
+    if err != nil {
+        return err
+    }
+
+    if err := h.checkViewable(ctx, story, viewer.ID); err != nil {
+        return err
+    }
+
+    h.recordView(ctx, story, viewer, ipAddress, userAgent)
+    return nil
+}
+
+// checkViewable returns storage.ErrNotFound for drafts and expired stories, and
+// realtime.ErrViewForbidden if the viewer may not see the story, so only views
+// the viewer could have made in the app are counted
+func (h *StoryHandler) checkViewable(ctx context.Context, story *models.Story, viewerID uuid.UUID) error {
+    if story.IsDraft() || story.IsExpired() {
+        return storage.ErrNotFound
+    }
+
+    allowed, err := h.canView(ctx, story, viewerID)
+    if err != nil {
+        return err
+    }
+    if !allowed {
+        return realtime.ErrViewForbidden
+    }
+
+    return nil
+}
+
+// StoryCounters gets a story's live counters for the realtime counter updates
+func (h *StoryHandler) StoryCounters(ctx context.Context, storyID uuid.UUID) (*realtime.StoryCounters, error) {
+    story, err := h.storyStore.GetByID(ctx, storyID)
+    if err != nil {
+        return nil, err
+    }
+
+    metrics, err := h.liveMetrics(ctx, storyID)
+    if err != nil {
+        return nil, err
+    }
+
+    return &realtime.StoryCounters{
+        AuthorID:    story.AuthorID,
+        Views:       metrics.Views,
+        UniqueViews: metrics.UniqueViews,
+        Reactions:   metrics.Reactions,
+    }, nil
+}
+
+// storyCountersChanged schedules a counter update for a story's author and the
+// subscribers of its topic
+func (h *StoryHandler) storyCountersChanged(storyID uuid.UUID) {
+    if h.wsHub != nil {
+        h.wsHub.StoryCountersChanged(storyID)
+    }
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+    "context"
+    "testing"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+func TestRecordViewKeepsAnonymity(t *testing.T) {
+    tests := []struct {
+        name          string
+        visibility    models.StoryVisibility
+        anonymous     bool
+        wantAnonymous bool
+    }{
+        {name: "anonymous viewer of public story", visibility: models.VisibilityPublic, anonymous: true, wantAnonymous: true},
+        {name: "named viewer of public story", visibility: models.VisibilityPublic, wantAnonymous: false},
+        {name: "anonymous viewer of friends story", visibility: models.VisibilityFriends, anonymous: true, wantAnonymous: false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            handler, stores := newTestStoryHandler()
+            author := newTestUser("author")
+            viewer := newTestUser("viewer")
+            viewer.AnonymousPublicViews = tt.anonymous
+            stores.follows.follow(viewer.ID, author.ID)
+            story := stores.stories.add(newTestStory(author.ID, tt.visibility))
+
+            if err := handler.RecordView(context.Background(), viewer, story.ID, "127.0.0.1", "test"); err != nil {
+                t.Fatalf("failed to record view: %v", err)
+            }
+
+            if len(stores.views.views) != 1 {
+                t.Fatalf("saved %d views, want 1", len(stores.views.views))
+            }
+            if got := stores.views.views[0].IsAnonymous; got != tt.wantAnonymous {
+                t.Errorf("view anonymous = %v, want %v", got, tt.wantAnonymous)
+            }
+        })
+    }
+}
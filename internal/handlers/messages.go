@@ -0,0 +1,441 @@
+package handlers
+
+import (
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/realtime"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/validator"
+)
+
+// MessageHandler handles story replies and private conversations
+type MessageHandler struct {
+    storyStore   storage.StoryStore
+    messageStore storage.MessageStore
+    blockStore   storage.BlockStore
+    wsHub        *realtime.Hub
+    logger       *zap.Logger
+}
+
+// NewMessageHandler creates a new message handler
+func NewMessageHandler(
+    storyStore storage.StoryStore,
+    messageStore storage.MessageStore,
+    blockStore storage.BlockStore,
+    wsHub *realtime.Hub,
+    logger *zap.Logger,
+) *MessageHandler {
+    return &MessageHandler{
+        storyStore:   storyStore,
+        messageStore: messageStore,
+        blockStore:   blockStore,
+        wsHub:        wsHub,
+        logger:       logger.With(zap.String("handler", "message")),
+    }
+}
+
+// ReplyToStory sends a private reply to a story's author
+func (h *MessageHandler) ReplyToStory(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story ID
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    var req models.MessageCreateRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        h.logger.Warn("Invalid story reply request", zap.Error(err))
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_request",
+            "message": "Invalid request body",
+        })
+        return
+    }
+
+    // Validate request
+    if err := validator.ValidateStruct(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "validation_failed",
+            "message": "Request validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to get story for reply",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return
+    }
+
+    // Only live stories the user can see accept replies
+    if story.IsDraft() || story.IsExpired() || !story.CanView(&user.ID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error":   "not_found",
+            "message": "Story not found",
+        })
+        return
+    }
+
+    if story.AuthorID == user.ID {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "own_story",
+            "message": "You can't reply to your own story",
+        })
+        return
+    }
+
+    if !story.AcceptsReplies() {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error":   "replies_disabled",
+            "message": "Replies are turned off for this story",
+        })
+        return
+    }
+
+    if !h.checkNotBlocked(c, user.ID, story.AuthorID) {
+        return
+    }
+
+    conversation, err := h.messageStore.GetOrCreateConversation(c.Request.Context(), user.ID, story.AuthorID)
+    if err != nil {
+        h.logger.Error("Failed to get conversation for story reply",
+            zap.String("story_id", storyID.String()),
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "create_failed",
+            "message": "Failed to send reply",
+        })
+        return
+    }
+
+    message := models.NewStoryReply(conversation, user.ID, story, req)
+    h.sendMessage(c, user, message)
+}
+
+// GetConversations gets the current user's conversations
+func (h *MessageHandler) GetConversations(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse query parameters
+    limit := 20
+    if l := c.Query("limit"); l != "" {
+        if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+            limit = parsed
+        }
+    }
+
+    offset := 0
+    if o := c.Query("offset"); o != "" {
+        if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+            offset = parsed
+        }
+    }
+
+    conversations, err := h.messageStore.GetConversations(c.Request.Context(), user.ID, limit, offset)
+    if err != nil {
+        h.logger.Error("Failed to get conversations",
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get conversations",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "conversations": conversations,
+        "count":         len(conversations),
+    })
+}
+
+// GetMessages gets messages in a conversation
+func (h *MessageHandler) GetMessages(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    conversation, ok := h.getConversation(c, user.ID)
+    if !ok {
+        return
+    }
+
+    // Parse query parameters
+    limit := 20
+    if l := c.Query("limit"); l != "" {
+        if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+            limit = parsed
+        }
+    }
+
+    offset := 0
+    if o := c.Query("offset"); o != "" {
+        if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+            offset = parsed
+        }
+    }
+
+    messages, err := h.messageStore.GetMessages(c.Request.Context(), conversation.ID, limit, offset)
+    if err != nil {
+        h.logger.Error("Failed to get messages",
+            zap.String("conversation_id", conversation.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get messages",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "messages": messages,
+        "count":    len(messages),
+    })
+}
+
+// SendMessage sends a message in an existing conversation
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    var req models.MessageCreateRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        h.logger.Warn("Invalid send message request", zap.Error(err))
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_request",
+            "message": "Invalid request body",
+        })
+        return
+    }
+
+    // Validate request
+    if err := validator.ValidateStruct(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "validation_failed",
+            "message": "Request validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    conversation, ok := h.getConversation(c, user.ID)
+    if !ok {
+        return
+    }
+
+    if !h.checkNotBlocked(c, user.ID, conversation.OtherParticipant(user.ID)) {
+        return
+    }
+
+    message := models.NewMessage(conversation, user.ID, req)
+    h.sendMessage(c, user, message)
+}
+
+// MarkRead marks a conversation as read and sends a read receipt to the other participant
+func (h *MessageHandler) MarkRead(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    conversation, ok := h.getConversation(c, user.ID)
+    if !ok {
+        return
+    }
+
+    readAt := time.Now()
+    count, err := h.messageStore.MarkRead(c.Request.Context(), conversation.ID, user.ID)
+    if err != nil {
+        h.logger.Error("Failed to mark conversation read",
+            zap.String("conversation_id", conversation.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "update_failed",
+            "message": "Failed to mark conversation read",
+        })
+        return
+    }
+
+    // Send read receipt
+    if h.wsHub != nil && count > 0 {
+        event := &realtime.Event{
+            Type: realtime.EventMessageRead,
+            Payload: gin.H{
+                "conversation_id": conversation.ID,
+                "reader_id":       user.ID,
+                "read_at":         readAt,
+            },
+        }
+        h.wsHub.SendToUser(conversation.OtherParticipant(user.ID), event)
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Conversation marked as read",
+        "count":   count,
+    })
+}
+
+// sendMessage saves a message and delivers it to the recipient in real time
+func (h *MessageHandler) sendMessage(c *gin.Context, sender *models.User, message *models.Message) {
+    if err := h.messageStore.CreateMessage(c.Request.Context(), message); err != nil {
+        h.logger.Error("Failed to send message",
+            zap.String("conversation_id", message.ConversationID.String()),
+            zap.String("sender_id", sender.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "create_failed",
+            "message": "Failed to send message",
+        })
+        return
+    }
+
+    if h.wsHub != nil {
+        event := &realtime.Event{
+            Type: realtime.EventMessageNew,
+            Payload: gin.H{
+                "message": message,
+                "sender":  sender.ToResponse(),
+            },
+        }
+        h.wsHub.SendToUser(message.RecipientID, event)
+    }
+
+    c.JSON(http.StatusCreated, message)
+}
+
+// checkNotBlocked writes an error response if either user has blocked the other
+func (h *MessageHandler) checkNotBlocked(c *gin.Context, senderID, recipientID uuid.UUID) bool {
+    for _, pair := range [][2]uuid.UUID{{recipientID, senderID}, {senderID, recipientID}} {
+        blocked, err := h.blockStore.IsBlocked(c.Request.Context(), pair[0], pair[1])
+        if err != nil {
+            h.logger.Error("Failed to check block status for message",
+                zap.String("sender_id", senderID.String()),
+                zap.String("recipient_id", recipientID.String()),
+                zap.Error(err),
+            )
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error":   "fetch_failed",
+                "message": "Failed to check block status",
+            })
+            return false
+        }
+        if blocked {
+            c.JSON(http.StatusForbidden, gin.H{
+                "error":   "blocked",
+                "message": "You can't message this user",
+            })
+            return false
+        }
+    }
+
+    return true
+}
+
+// getConversation loads the conversation in the route, writing an error response if userID can't access it
+func (h *MessageHandler) getConversation(c *gin.Context, userID uuid.UUID) (*models.Conversation, bool) {
+    conversationID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid conversation ID",
+        })
+        return nil, false
+    }
+
+    conversation, err := h.messageStore.GetConversation(c.Request.Context(), conversationID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Conversation not found",
+            })
+            return nil, false
+        }
+
+        h.logger.Error("Failed to get conversation",
+            zap.String("conversation_id", conversationID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get conversation",
+        })
+        return nil, false
+    }
+
+    // Conversations are hidden from non-participants
+    if !conversation.HasParticipant(userID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error":   "not_found",
+            "message": "Conversation not found",
+        })
+        return nil, false
+    }
+
+    return conversation, true
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+    "net/http"
+    "testing"
+
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+// newTestMessageHandler creates a message handler with in-memory stores and no hub
+func newTestMessageHandler() (*MessageHandler, *fakeStoryStore, *fakeMessageStore, *fakeBlockStore) {
+    stories := &fakeStoryStore{}
+    messages := &fakeMessageStore{}
+    blocks := &fakeBlockStore{}
+    return NewMessageHandler(stories, messages, blocks, nil, zap.NewNop()), stories, messages, blocks
+}
+
+func TestMessagesRejectedAfterBlock(t *testing.T) {
+    author := newTestUser("author")
+    replier := newTestUser("replier")
+    request := models.MessageCreateRequest{Text: "hello"}
+
+    tests := []struct {
+        name    string
+        blocker *models.User
+        blocked *models.User
+    }{
+        {name: "author blocked sender", blocker: author, blocked: replier},
+        {name: "sender blocked author", blocker: replier, blocked: author},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            handler, stories, messages, blocks := newTestMessageHandler()
+            story := stories.add(newTestStory(author.ID, models.VisibilityPublic))
+
+            // Both can message each other before the block
+            recorder, _ := serve(t, handler.ReplyToStory, replier, http.MethodPost, request, idParam(story.ID))
+            checkStatus(t, recorder, nil, http.StatusCreated, "")
+            conversation := messages.messages[0].ConversationID
+
+            blocks.block(tt.blocker.ID, tt.blocked.ID)
+
+            recorder, response := serve(t, handler.ReplyToStory, replier, http.MethodPost, request, idParam(story.ID))
+            checkStatus(t, recorder, response, http.StatusForbidden, "blocked")
+
+            for _, sender := range []*models.User{author, replier} {
+                recorder, response := serve(t, handler.SendMessage, sender, http.MethodPost, request, idParam(conversation))
+                checkStatus(t, recorder, response, http.StatusForbidden, "blocked")
+            }
+
+            if sent := messages.sent(); sent != 1 {
+                t.Errorf("saved %d messages, want only the one before the block", sent)
+            }
+        })
+    }
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+// GetNearbyStories gets active public stories within a radius of a point.
+// Stories with hidden coordinates never appear in radius searches.
+func (h *StoryHandler) GetNearbyStories(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    latitude, err := strconv.ParseFloat(c.Query("lat"), 64)
+    if err != nil || latitude < -90 || latitude > 90 {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_location",
+            "message": "lat must be between -90 and 90",
+        })
+        return
+    }
+
+    longitude, err := strconv.ParseFloat(c.Query("lng"), 64)
+    if err != nil || longitude < -180 || longitude > 180 {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_location",
+            "message": "lng must be between -180 and 180",
+        })
+        return
+    }
+
+    radius := float64(models.DefaultNearbyRadius)
+    if r := c.Query("radius"); r != "" {
+        if parsed, err := strconv.ParseFloat(r, 64); err == nil && parsed > 0 {
+            radius = parsed
+        }
+    }
+
+    // Parse query parameters
+    limit := 20
+    if l := c.Query("limit"); l != "" {
+        if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+            limit = parsed
+        }
+    }
+
+    offset := 0
+    if o := c.Query("offset"); o != "" {
+        if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+            offset = parsed
+        }
+    }
+
+    query := models.NewNearbyStoriesQuery(latitude, longitude, radius)
+
+    stories, err := h.storyStore.GetNearby(c.Request.Context(), query, limit, offset)
+    if err != nil {
+        h.logger.Error("Failed to get nearby stories",
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get nearby stories",
+        })
+        return
+    }
+
+    for i, story := range stories {
+        stories[i] = story.ForViewer(user.ID)
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "stories": stories,
+        "count":   len(stories),
+        "radius":  query.Radius,
+    })
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/validator"
+)
+
+// ReactionTypeHandler handles the reaction catalogue endpoints
+type ReactionTypeHandler struct {
+    reactionTypeStore storage.ReactionTypeStore
+    allowAnyEmoji     bool
+    logger            *zap.Logger
+}
+
+// NewReactionTypeHandler creates a new reaction type handler
+func NewReactionTypeHandler(reactionTypeStore storage.ReactionTypeStore, allowAnyEmoji bool, logger *zap.Logger) *ReactionTypeHandler {
+    return &ReactionTypeHandler{
+        reactionTypeStore: reactionTypeStore,
+        allowAnyEmoji:     allowAnyEmoji,
+        logger:            logger.With(zap.String("handler", "reaction_type")),
+    }
+}
+
+// GetReactionTypes gets the reaction catalogue. Admins can include retired types.
+func (h *ReactionTypeHandler) GetReactionTypes(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    var definitions []*models.ReactionDefinition
+    var err error
+    if user.IsAdmin && c.Query("include_retired") == "true" {
+        definitions, err = h.reactionTypeStore.GetAll(c.Request.Context())
+    } else {
+        definitions, err = h.reactionTypeStore.GetActive(c.Request.Context())
+    }
+    if err != nil {
+        h.logger.Error("Failed to get reaction types", zap.Error(err))
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get reaction types",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "reaction_types":  definitions,
+        "count":           len(definitions),
+        "allow_any_emoji": h.allowAnyEmoji,
+    })
+}
+
+// CreateReactionType adds a reaction type to the catalogue (admin only)
+func (h *ReactionTypeHandler) CreateReactionType(c *gin.Context) {
+    var req models.ReactionDefinitionCreateRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        h.logger.Warn("Invalid create reaction type request", zap.Error(err))
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_request",
+            "message": "Invalid request body",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    // Validate request
+    if err := validator.ValidateStruct(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "validation_failed",
+            "message": "Request validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    definition := models.NewReactionDefinition(req)
+
+    if err := h.reactionTypeStore.Create(c.Request.Context(), definition); err != nil {
+        if err == storage.ErrAlreadyExists {
+            c.JSON(http.StatusConflict, gin.H{
+                "error":   "already_exists",
+                "message": "A reaction type with this key or emoji already exists",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to create reaction type",
+            zap.String("type", string(req.Type)),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "create_failed",
+            "message": "Failed to create reaction type",
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, definition)
+}
+
+// RetireReactionType retires a reaction type so it can't be used for new reactions (admin only)
+func (h *ReactionTypeHandler) RetireReactionType(c *gin.Context) {
+    reactionType := models.ReactionType(c.Param("type"))
+
+    if err := h.reactionTypeStore.Retire(c.Request.Context(), reactionType); err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Active reaction type not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to retire reaction type",
+            zap.String("type", string(reactionType)),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "delete_failed",
+            "message": "Failed to retire reaction type",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Reaction type retired",
+    })
+}
+
+// resolveReactionType checks a requested reaction against the catalogue,
+// writing an error response if it isn't allowed
+func (h *StoryHandler) resolveReactionType(c *gin.Context, requested models.ReactionType) (models.ReactionType, bool) {
+    catalogue, err := h.reactionTypeStore.GetActive(c.Request.Context())
+    if err != nil {
+        h.logger.Error("Failed to get reaction catalogue", zap.Error(err))
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to check reaction type",
+        })
+        return "", false
+    }
+
+    reactionType, ok := models.ResolveReactionType(requested, catalogue, h.config.ReactionsAllowAnyEmoji)
+    if !ok {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_reaction",
+            "message": "Invalid reaction type",
+        })
+        return "", false
+    }
+
+    return reactionType, true
+}
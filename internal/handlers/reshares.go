@@ -0,0 +1,281 @@
+package handlers
+
+import (
+    "context"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/realtime"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/validator"
+)
+
+// ReshareStory creates a new story that reshares another user's story
+func (h *StoryHandler) ReshareStory(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story ID
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    var req models.StoryReshareRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        h.logger.Warn("Invalid reshare request", zap.Error(err))
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_request",
+            "message": "Invalid request body",
+        })
+        return
+    }
+
+    // Validate request
+    if err := validator.ValidateStruct(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "validation_failed",
+            "message": "Request validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    original, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to get story for reshare",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return
+    }
+
+    canView, err := h.canView(c.Request.Context(), original, user.ID)
+    if err != nil {
+        h.logger.Error("Failed to check story visibility for reshare",
+            zap.String("story_id", storyID.String()),
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to check reshare permissions",
+        })
+        return
+    }
+
+    if !original.CanReshare(user.ID, canView) {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error":   "reshare_not_allowed",
+            "message": "You can't reshare this story",
+        })
+        return
+    }
+
+    if !original.AllowsReshareVisibility(req.Visibility) {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "visibility_not_allowed",
+            "message": "A reshare can't be more visible than the original story",
+        })
+        return
+    }
+
+    // Resharing a reshare shares the root story, which must still be live
+    if original.IsReshare() {
+        h.attachOriginal(c.Request.Context(), original, user.ID)
+        if original.OriginalUnavailable {
+            c.JSON(http.StatusGone, gin.H{
+                "error":   "original_unavailable",
+                "message": "The original story is no longer available",
+            })
+            return
+        }
+    }
+
+    reshare := models.NewReshare(user.ID, original, req)
+    reshare.SetInteractionSettings(user, nil, nil)
+
+    // Parse mentions and hashtags in the caption
+    h.resolveEntities(c.Request.Context(), reshare)
+
+    if err := h.storyStore.Create(c.Request.Context(), reshare); err != nil {
+        h.logger.Error("Failed to create reshare",
+            zap.String("story_id", storyID.String()),
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "create_failed",
+            "message": "Failed to reshare story",
+        })
+        return
+    }
+
+    // The cached original still has the old share count
+    h.storyStore.InvalidateCache(c.Request.Context(), *reshare.OriginalStoryID)
+
+    h.logger.Info("Story reshared successfully",
+        zap.String("story_id", reshare.ID.String()),
+        zap.String("original_story_id", reshare.OriginalStoryID.String()),
+        zap.String("user_id", user.ID.String()),
+    )
+
+    h.broadcastStory(c.Request.Context(), reshare, user)
+
+    if h.wsHub != nil {
+        notification := realtime.NotificationEvent(
+            "reshare",
+            "Your story was reshared",
+            user.Username+" reshared your story",
+            map[string]interface{}{
+                "story_id":   reshare.OriginalStoryID,
+                "reshare_id": reshare.ID,
+                "user":       user.ToResponse(),
+            },
+        )
+        h.wsHub.SendToUser(*reshare.OriginalAuthorID, notification)
+    }
+
+    h.notifyMentions(reshare, user, nil)
+
+    h.attachOriginal(c.Request.Context(), reshare, user.ID)
+
+    c.JSON(http.StatusCreated, reshare)
+}
+
+// GetStoryMetrics gets engagement metrics for a story
+func (h *StoryHandler) GetStoryMetrics(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story ID
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to get story for metrics",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return
+    }
+
+    // Only story author can see metrics
+    if story.AuthorID != user.ID {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error":   "forbidden",
+            "message": "You can only view metrics for your own stories",
+        })
+        return
+    }
+
+    metrics, err := h.liveMetrics(c.Request.Context(), storyID)
+    if err != nil {
+        h.logger.Error("Failed to get story metrics",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story metrics",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, metrics)
+}
+
+// attachOriginal loads the original of a reshare for display to viewerID.
+// If the original was deleted, expired or isn't visible, the reshare is marked unavailable instead.
+func (h *StoryHandler) attachOriginal(ctx context.Context, story *models.Story, viewerID uuid.UUID) {
+    if !story.IsReshare() {
+        return
+    }
+
+    original, err := h.storyStore.GetByID(ctx, *story.OriginalStoryID)
+    if err != nil {
+        if err != storage.ErrNotFound {
+            h.logger.Warn("Failed to get original story for reshare",
+                zap.String("story_id", story.ID.String()),
+                zap.String("original_story_id", story.OriginalStoryID.String()),
+                zap.Error(err),
+            )
+        }
+        story.OriginalUnavailable = true
+        return
+    }
+
+    if original.IsExpired() {
+        story.OriginalUnavailable = true
+        return
+    }
+
+    if !original.IsMentioned(viewerID) {
+        canView, err := h.canView(ctx, original, viewerID)
+        if err != nil {
+            h.logger.Warn("Failed to check original story visibility for reshare",
+                zap.String("story_id", story.ID.String()),
+                zap.String("original_story_id", story.OriginalStoryID.String()),
+                zap.Error(err),
+            )
+        }
+        if !canView {
+            story.OriginalUnavailable = true
+            return
+        }
+    }
+
+    story.Original = original.ForViewer(viewerID)
+}
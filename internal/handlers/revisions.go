@@ -0,0 +1,90 @@
+package handlers
+
+import (
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+)
+
+// GetStoryRevisions gets a story's edit history. Authors see their own stories'
+// history; moderators can see any story's, even after it was deleted.
+func (h *StoryHandler) GetStoryRevisions(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story ID
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    if user.CanModerate() {
+        h.logger.Info("Story revisions accessed for moderation",
+            zap.String("story_id", storyID.String()),
+            zap.String("moderator_id", user.ID.String()),
+        )
+    } else {
+        story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+        if err != nil {
+            if err == storage.ErrNotFound {
+                c.JSON(http.StatusNotFound, gin.H{
+                    "error":   "not_found",
+                    "message": "Story not found",
+                })
+                return
+            }
+
+            h.logger.Error("Failed to get story for revisions",
+                zap.String("story_id", storyID.String()),
+                zap.Error(err),
+            )
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error":   "fetch_failed",
+                "message": "Failed to get story",
+            })
+            return
+        }
+
+        // Only story author can see its history
+        if story.AuthorID != user.ID {
+            c.JSON(http.StatusForbidden, gin.H{
+                "error":   "forbidden",
+                "message": "You can only view the history of your own stories",
+            })
+            return
+        }
+    }
+
+    revisions, err := h.storyStore.GetRevisions(c.Request.Context(), storyID)
+    if err != nil {
+        h.logger.Error("Failed to get story revisions",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story revisions",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "revisions": revisions,
+        "count":     len(revisions),
+    })
+}
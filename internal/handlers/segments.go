@@ -0,0 +1,202 @@
+package handlers
+
+import (
+    "errors"
+    "fmt"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+)
+
+// ViewSegment records that the current user reached a segment of a sequence story
+func (h *StoryHandler) ViewSegment(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story and segment IDs
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    segmentID, err := uuid.Parse(c.Param("segment_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid segment ID",
+        })
+        return
+    }
+
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to get story for segment view",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return
+    }
+
+    // Drafts, expired stories and stories the user can't see are treated as missing
+    if story.IsDraft() || story.IsExpired() || !story.CanView(&user.ID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error":   "not_found",
+            "message": "Story not found",
+        })
+        return
+    }
+
+    if story.GetSegment(segmentID) == nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error":   "not_found",
+            "message": "Segment not found",
+        })
+        return
+    }
+
+    view := models.NewSegmentView(storyID, segmentID, user.ID)
+
+    // Save view
+    if err := h.viewStore.CreateSegmentView(c.Request.Context(), view); err != nil {
+        h.logger.Error("Failed to create segment view",
+            zap.String("story_id", storyID.String()),
+            zap.String("segment_id", segmentID.String()),
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        // Don't return error for views, as it's not critical
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": "Segment viewed",
+    })
+}
+
+// GetSegmentDropOff gets per-segment drop-off analytics for a sequence story
+func (h *StoryHandler) GetSegmentDropOff(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story ID
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    // Check if user owns this story
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to get story for drop-off",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return
+    }
+
+    // Only story author can see analytics
+    if story.AuthorID != user.ID {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error":   "forbidden",
+            "message": "You can only view analytics for your own stories",
+        })
+        return
+    }
+
+    if story.Type != models.StoryTypeSequence {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "not_sequence",
+            "message": "Drop-off analytics are only available for sequence stories",
+        })
+        return
+    }
+
+    report, err := h.viewStore.GetSegmentDropOff(c.Request.Context(), storyID)
+    if err != nil {
+        h.logger.Error("Failed to get segment drop-off",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get segment drop-off",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, report)
+}
+
+// validateStorySegments checks that segments are present exactly when the story is a sequence
+func validateStorySegments(req models.StoryCreateRequest) error {
+    if req.Type != models.StoryTypeSequence {
+        if len(req.Segments) > 0 {
+            return errors.New("Segments are only allowed on sequence stories")
+        }
+        return nil
+    }
+
+    if len(req.Segments) == 0 {
+        return errors.New("Sequence stories need at least one segment")
+    }
+    if len(req.Segments) > models.MaxStorySegments {
+        return fmt.Errorf("Sequence stories can have at most %d segments", models.MaxStorySegments)
+    }
+
+    for i, segment := range req.Segments {
+        if !models.ValidateSegment(segment) {
+            return fmt.Errorf("Segment %d is missing content for type %s", i, segment.Type)
+        }
+    }
+
+    return nil
+}
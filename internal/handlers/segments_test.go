@@ -0,0 +1,61 @@
+package handlers
+
+import (
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+func TestViewSegmentOfExpiredStory(t *testing.T) {
+    author := newTestUser("author")
+    viewer := newTestUser("viewer")
+
+    tests := []struct {
+        name   string
+        expire func(story *models.Story)
+        status int
+    }{
+        {name: "active", expire: func(story *models.Story) {}, status: http.StatusOK},
+        {name: "expired", expire: func(story *models.Story) { story.ExpiresAt = time.Now().Add(-time.Minute) }, status: http.StatusNotFound},
+        {name: "archived", expire: func(story *models.Story) {
+            story.ExpiresAt = time.Now().Add(-time.Minute)
+            story.Archive()
+        }, status: http.StatusNotFound},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            handler, stores := newTestStoryHandler()
+            text := "frame"
+            story := models.NewStory(author.ID, models.StoryCreateRequest{
+                Type:       models.StoryTypeText,
+                Visibility: models.VisibilityPublic,
+                Segments: []models.SegmentCreateRequest{
+                    {Type: models.StoryTypeText, Text: &text},
+                    {Type: models.StoryTypeText, Text: &text},
+                },
+            })
+            tt.expire(story)
+            stores.stories.add(story)
+
+            params := gin.Params{
+                {Key: "id", Value: story.ID.String()},
+                {Key: "segment_id", Value: story.Segments[1].ID.String()},
+            }
+            recorder, response := serve(t, handler.ViewSegment, viewer, http.MethodPost, nil, params)
+            checkStatus(t, recorder, response, tt.status, "")
+
+            wantViews := 0
+            if tt.status == http.StatusOK {
+                wantViews = 1
+            }
+            if len(stores.views.segmentViews) != wantViews {
+                t.Errorf("recorded %d segment views, want %d", len(stores.views.segmentViews), wantViews)
+            }
+        })
+    }
+}
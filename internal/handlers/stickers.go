@@ -0,0 +1,279 @@
+package handlers
+
+import (
+    "fmt"
+    "net/http"
+    "strconv"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/realtime"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/validator"
+)
+
+// liveResultsRespondents is the number of recent respondents pushed with live results
+const liveResultsRespondents = 10
+
+// StickerHandler handles interactive sticker endpoints
+type StickerHandler struct {
+    storyStore   storage.StoryStore
+    stickerStore storage.StickerStore
+    wsHub        *realtime.Hub
+    logger       *zap.Logger
+}
+
+// NewStickerHandler creates a new sticker handler
+func NewStickerHandler(
+    storyStore storage.StoryStore,
+    stickerStore storage.StickerStore,
+    wsHub *realtime.Hub,
+    logger *zap.Logger,
+) *StickerHandler {
+    return &StickerHandler{
+        storyStore:   storyStore,
+        stickerStore: stickerStore,
+        wsHub:        wsHub,
+        logger:       logger.With(zap.String("handler", "sticker")),
+    }
+}
+
+// Respond records the current user's response to a sticker
+func (h *StickerHandler) Respond(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    var req models.StickerRespondRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        h.logger.Warn("Invalid sticker response request", zap.Error(err))
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_request",
+            "message": "Invalid request body",
+        })
+        return
+    }
+
+    // Validate request
+    if err := validator.ValidateStruct(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "validation_failed",
+            "message": "Request validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    story, sticker, ok := h.getSticker(c)
+    if !ok {
+        return
+    }
+
+    // Stickers can only be answered on live stories
+    if story.IsDraft() || story.IsExpired() || !story.CanView(&user.ID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error":   "not_found",
+            "message": "Story not found",
+        })
+        return
+    }
+
+    if story.AuthorID == user.ID {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "own_story",
+            "message": "You can't respond to stickers on your own story",
+        })
+        return
+    }
+
+    response, err := sticker.NewStickerResponse(user.ID, req)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_response",
+            "message": err.Error(),
+        })
+        return
+    }
+
+    if err := h.stickerStore.CreateResponse(c.Request.Context(), response); err != nil {
+        if err == storage.ErrAlreadyExists {
+            c.JSON(http.StatusConflict, gin.H{
+                "error":   "already_responded",
+                "message": "You have already responded to this sticker",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to create sticker response",
+            zap.String("sticker_id", sticker.ID.String()),
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "create_failed",
+            "message": "Failed to record response",
+        })
+        return
+    }
+
+    // Push updated results to the story author
+    if h.wsHub != nil {
+        results, err := h.stickerStore.GetResults(c.Request.Context(), sticker, liveResultsRespondents)
+        if err != nil {
+            h.logger.Warn("Failed to get live sticker results",
+                zap.String("sticker_id", sticker.ID.String()),
+                zap.Error(err),
+            )
+        } else {
+            event := &realtime.Event{
+                Type: realtime.EventStickerResults,
+                Payload: gin.H{
+                    "story_id": story.ID,
+                    "results":  results,
+                },
+            }
+            h.wsHub.SendToUser(story.AuthorID, event)
+        }
+    }
+
+    // Quiz answers are revealed once the viewer has answered
+    c.JSON(http.StatusCreated, gin.H{
+        "response":       response,
+        "correct_option": sticker.CorrectOption,
+    })
+}
+
+// GetResults gets aggregate results and respondents for a sticker
+func (h *StickerHandler) GetResults(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    limit := 50
+    if l := c.Query("limit"); l != "" {
+        if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+            limit = parsed
+        }
+    }
+
+    story, sticker, ok := h.getSticker(c)
+    if !ok {
+        return
+    }
+
+    // Only story author can see results
+    if story.AuthorID != user.ID {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error":   "forbidden",
+            "message": "You can only view results for your own stickers",
+        })
+        return
+    }
+
+    results, err := h.stickerStore.GetResults(c.Request.Context(), sticker, limit)
+    if err != nil {
+        h.logger.Error("Failed to get sticker results",
+            zap.String("sticker_id", sticker.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get sticker results",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, results)
+}
+
+// getSticker loads the story and sticker named in the route, writing an error response if it can't
+func (h *StickerHandler) getSticker(c *gin.Context) (*models.Story, *models.Sticker, bool) {
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return nil, nil, false
+    }
+
+    stickerID, err := uuid.Parse(c.Param("sticker_id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid sticker ID",
+        })
+        return nil, nil, false
+    }
+
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return nil, nil, false
+        }
+
+        h.logger.Error("Failed to get story for sticker",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return nil, nil, false
+    }
+
+    // Read stickers from the database since the cached story omits quiz answers
+    stickers, err := h.storyStore.GetStickers(c.Request.Context(), storyID)
+    if err != nil {
+        h.logger.Error("Failed to get story stickers",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get sticker",
+        })
+        return nil, nil, false
+    }
+
+    for _, sticker := range stickers {
+        if sticker.ID == stickerID {
+            return story, sticker, true
+        }
+    }
+
+    c.JSON(http.StatusNotFound, gin.H{
+        "error":   "not_found",
+        "message": "Sticker not found",
+    })
+    return nil, nil, false
+}
+
+// validateStoryStickers checks that each sticker carries the fields its type requires
+func validateStoryStickers(req models.StoryCreateRequest) error {
+    for i, sticker := range req.Stickers {
+        if err := models.ValidateStickerRequest(sticker); err != nil {
+            return fmt.Errorf("Sticker %d: %v", i, err)
+        }
+    }
+    return nil
+}
@@ -0,0 +1,379 @@
+package handlers
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "net/http/httptest"
+    "sync"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+func init() {
+    gin.SetMode(gin.TestMode)
+}
+
+// fakeStoryStore keeps stories in memory. Methods a test doesn't use panic
+// through the embedded nil interface.
+type fakeStoryStore struct {
+    storage.StoryStore
+
+    mu      sync.Mutex
+    stories map[uuid.UUID]*models.Story
+    created []*models.Story
+    updated []*models.Story
+}
+
+// add stores a story and returns it
+func (f *fakeStoryStore) add(story *models.Story) *models.Story {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    if f.stories == nil {
+        f.stories = make(map[uuid.UUID]*models.Story)
+    }
+    f.stories[story.ID] = story
+    return story
+}
+
+func (f *fakeStoryStore) GetByID(ctx context.Context, id uuid.UUID) (*models.Story, error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    story, ok := f.stories[id]
+    if !ok {
+        return nil, storage.ErrNotFound
+    }
+
+    // Stories come back as they are decoded from the cache, without the
+    // fields that aren't cached
+    data, err := json.Marshal(story)
+    if err != nil {
+        return nil, err
+    }
+    var cached models.Story
+    if err := json.Unmarshal(data, &cached); err != nil {
+        return nil, err
+    }
+    return &cached, nil
+}
+
+func (f *fakeStoryStore) Create(ctx context.Context, story *models.Story) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    f.created = append(f.created, story)
+    return nil
+}
+
+func (f *fakeStoryStore) Update(ctx context.Context, story *models.Story, revision *models.StoryRevision) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    f.stories[story.ID] = story
+    f.updated = append(f.updated, story)
+    return nil
+}
+
+// fakeMessageStore keeps conversations and messages in memory
+type fakeMessageStore struct {
+    storage.MessageStore
+
+    mu            sync.Mutex
+    conversations map[uuid.UUID]*models.Conversation
+    messages      []*models.Message
+}
+
+func (f *fakeMessageStore) GetOrCreateConversation(ctx context.Context, userID, otherUserID uuid.UUID) (*models.Conversation, error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    for _, conversation := range f.conversations {
+        if conversation.HasParticipant(userID) && conversation.HasParticipant(otherUserID) {
+            return conversation, nil
+        }
+    }
+
+    conversation := models.NewConversation(userID, otherUserID)
+    if f.conversations == nil {
+        f.conversations = make(map[uuid.UUID]*models.Conversation)
+    }
+    f.conversations[conversation.ID] = conversation
+    return conversation, nil
+}
+
+func (f *fakeMessageStore) GetConversation(ctx context.Context, id uuid.UUID) (*models.Conversation, error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    conversation, ok := f.conversations[id]
+    if !ok {
+        return nil, storage.ErrNotFound
+    }
+    return conversation, nil
+}
+
+func (f *fakeMessageStore) CreateMessage(ctx context.Context, message *models.Message) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    f.messages = append(f.messages, message)
+    return nil
+}
+
+// sent returns the number of messages saved
+func (f *fakeMessageStore) sent() int {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    return len(f.messages)
+}
+
+// fakeBlockStore keeps blocks in memory
+type fakeBlockStore struct {
+    mu     sync.Mutex
+    blocks map[[2]uuid.UUID]bool
+}
+
+func (f *fakeBlockStore) Create(ctx context.Context, block *models.Block) error {
+    f.block(block.BlockerID, block.BlockedID)
+    return nil
+}
+
+func (f *fakeBlockStore) Delete(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    delete(f.blocks, [2]uuid.UUID{blockerID, blockedID})
+    return nil
+}
+
+func (f *fakeBlockStore) IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    return f.blocks[[2]uuid.UUID{blockerID, blockedID}], nil
+}
+
+func (f *fakeBlockStore) GetBlockedIDs(ctx context.Context, blockerID uuid.UUID) ([]uuid.UUID, error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    var ids []uuid.UUID
+    for pair := range f.blocks {
+        if pair[0] == blockerID {
+            ids = append(ids, pair[1])
+        }
+    }
+    return ids, nil
+}
+
+// block makes blockerID block blockedID
+func (f *fakeBlockStore) block(blockerID, blockedID uuid.UUID) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    if f.blocks == nil {
+        f.blocks = make(map[[2]uuid.UUID]bool)
+    }
+    f.blocks[[2]uuid.UUID{blockerID, blockedID}] = true
+}
+
+// fakeViewStore keeps story and segment views in memory
+type fakeViewStore struct {
+    storage.ViewStore
+
+    mu           sync.Mutex
+    views        []*models.StoryView
+    segmentViews []*models.SegmentView
+}
+
+func (f *fakeViewStore) Create(ctx context.Context, view *models.StoryView) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    f.views = append(f.views, view)
+    return nil
+}
+
+func (f *fakeViewStore) CreateSegmentView(ctx context.Context, view *models.SegmentView) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    f.segmentViews = append(f.segmentViews, view)
+    return nil
+}
+
+// fakeViewCounter counts each viewer's first view of a story as unique
+type fakeViewCounter struct {
+    storage.ViewCounter
+
+    mu     sync.Mutex
+    viewed map[[2]uuid.UUID]bool
+}
+
+func (f *fakeViewCounter) Record(ctx context.Context, story *models.Story, viewerID uuid.UUID) (bool, error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    key := [2]uuid.UUID{story.ID, viewerID}
+    if f.viewed[key] {
+        return false, nil
+    }
+    if f.viewed == nil {
+        f.viewed = make(map[[2]uuid.UUID]bool)
+    }
+    f.viewed[key] = true
+    return true, nil
+}
+
+// fakeFollowStore keeps follows in memory
+type fakeFollowStore struct {
+    storage.FollowStore
+
+    mu      sync.Mutex
+    follows map[[2]uuid.UUID]bool
+}
+
+func (f *fakeFollowStore) IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    return f.follows[[2]uuid.UUID{followerID, followeeID}], nil
+}
+
+// follow makes followerID follow followeeID
+func (f *fakeFollowStore) follow(followerID, followeeID uuid.UUID) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    if f.follows == nil {
+        f.follows = make(map[[2]uuid.UUID]bool)
+    }
+    f.follows[[2]uuid.UUID{followerID, followeeID}] = true
+}
+
+// fakeUserStore keeps users in memory
+type fakeUserStore struct {
+    storage.UserStore
+
+    mu    sync.Mutex
+    users map[uuid.UUID]*models.User
+}
+
+func (f *fakeUserStore) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    user, ok := f.users[id]
+    if !ok {
+        return nil, storage.ErrNotFound
+    }
+    return user, nil
+}
+
+// add stores a user and returns it
+func (f *fakeUserStore) add(user *models.User) *models.User {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    if f.users == nil {
+        f.users = make(map[uuid.UUID]*models.User)
+    }
+    f.users[user.ID] = user
+    return user
+}
+
+// testStores are the in-memory stores behind a test story handler
+type testStores struct {
+    stories *fakeStoryStore
+    views   *fakeViewStore
+    follows *fakeFollowStore
+    blocks  *fakeBlockStore
+}
+
+// newTestStoryHandler creates a story handler with in-memory stores and no hub
+func newTestStoryHandler() (*StoryHandler, *testStores) {
+    stores := &testStores{
+        stories: &fakeStoryStore{},
+        views:   &fakeViewStore{},
+        follows: &fakeFollowStore{},
+        blocks:  &fakeBlockStore{},
+    }
+    handler := NewStoryHandler(
+        stores.stories, stores.views, &fakeViewCounter{}, nil, nil, nil,
+        stores.follows, stores.blocks, nil, config.StoryConfig{}, zap.NewNop(),
+    )
+    return handler, stores
+}
+
+// newTestStory creates a published text story
+func newTestStory(authorID uuid.UUID, visibility models.StoryVisibility) *models.Story {
+    return models.NewStory(authorID, models.StoryCreateRequest{
+        Type:       models.StoryTypeText,
+        Visibility: visibility,
+    })
+}
+
+// newTestUser creates a user for handler tests
+func newTestUser(username string) *models.User {
+    return &models.User{ID: uuid.New(), Username: username}
+}
+
+// serve calls a handler as user with a JSON body and route parameters, and
+// returns the response with its decoded body
+func serve(t *testing.T, handler gin.HandlerFunc, user *models.User, method string, body interface{}, params gin.Params) (*httptest.ResponseRecorder, map[string]interface{}) {
+    t.Helper()
+
+    var data []byte
+    if body != nil {
+        var err error
+        if data, err = json.Marshal(body); err != nil {
+            t.Fatalf("failed to marshal request: %v", err)
+        }
+    }
+
+    recorder := httptest.NewRecorder()
+    c, _ := gin.CreateTestContext(recorder)
+    c.Request = httptest.NewRequest(method, "/", bytes.NewReader(data))
+    c.Request.Header.Set("Content-Type", "application/json")
+    c.Params = params
+    if user != nil {
+        c.Set("user", user)
+    }
+
+    handler(c)
+
+    var response map[string]interface{}
+    if recorder.Body.Len() > 0 {
+        if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+            t.Fatalf("failed to decode response %q: %v", recorder.Body.String(), err)
+        }
+    }
+    return recorder, response
+}
+
+// idParam is the route parameters for an ID
+func idParam(id uuid.UUID) gin.Params {
+    return gin.Params{{Key: "id", Value: id.String()}}
+}
+
+// checkStatus checks a response's status and error code
+func checkStatus(t *testing.T, recorder *httptest.ResponseRecorder, response map[string]interface{}, status int, code string) {
+    t.Helper()
+
+    if recorder.Code != status {
+        t.Fatalf("status = %d, want %d: %s", recorder.Code, status, recorder.Body.String())
+    }
+    if code != "" && response["error"] != code {
+        t.Errorf("error = %v, want %s", response["error"], code)
+    }
+}
@@ -12,32 +12,51 @@ import (
     "github.com/Abhiro0p/stories-backend/internal/models"
     "github.com/Abhiro0p/stories-backend/internal/realtime"
     "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
     "github.com/Abhiro0p/stories-backend/pkg/validator"
 )
 
 // StoryHandler handles story-related endpoints
 type StoryHandler struct {
-    storyStore    storage.StoryStore
-    viewStore     storage.ViewStore
-    reactionStore storage.ReactionStore
-    wsHub         *realtime.Hub
-    logger        *zap.Logger
+    storyStore        storage.StoryStore
+    viewStore         storage.ViewStore
+    viewCounter       storage.ViewCounter
+    reactionStore     storage.ReactionStore
+    reactionTypeStore storage.ReactionTypeStore
+    userStore         storage.UserStore
+    followStore       storage.FollowStore
+    blockStore        storage.BlockStore
+    wsHub             *realtime.Hub
+    config            config.StoryConfig
+    logger            *zap.Logger
 }
 
 // NewStoryHandler creates a new story handler
 func NewStoryHandler(
     storyStore storage.StoryStore,
     viewStore storage.ViewStore,
+    viewCounter storage.ViewCounter,
     reactionStore storage.ReactionStore,
+    reactionTypeStore storage.ReactionTypeStore,
+    userStore storage.UserStore,
+    followStore storage.FollowStore,
+    blockStore storage.BlockStore,
     wsHub *realtime.Hub,
+    config config.StoryConfig,
     logger *zap.Logger,
 ) *StoryHandler {
     return &StoryHandler{
-        storyStore:    storyStore,
-        viewStore:     viewStore,
-        reactionStore: reactionStore,
-        wsHub:         wsHub,
-        logger:        logger.With(zap.String("handler", "story")),
+        storyStore:        storyStore,
+        viewStore:         viewStore,
+        viewCounter:       viewCounter,
+        reactionStore:     reactionStore,
+        reactionTypeStore: reactionTypeStore,
+        userStore:         userStore,
+        followStore:       followStore,
+        blockStore:        blockStore,
+        wsHub:             wsHub,
+        config:            config,
+        logger:            logger.With(zap.String("handler", "story")),
     }
 }
 
@@ -74,8 +93,29 @@ func (h *StoryHandler) CreateStory(c *gin.Context) {
         return
     }
 
+    // Sequence stories need at least one segment, and each segment its content
+    if err := validateStorySegments(req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_segments",
+            "message": err.Error(),
+        })
+        return
+    }
+
+    if err := validateStoryStickers(req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_stickers",
+            "message": err.Error(),
+        })
+        return
+    }
+
     // Create story
     story := models.NewStory(user.ID, req)
+    story.SetInteractionSettings(user, req.AllowReactions, req.RepliesDisabled)
+
+    // Parse mentions and hashtags
+    h.resolveEntities(c.Request.Context(), story)
 
     // Save to database
     if err := h.storyStore.Create(c.Request.Context(), story); err != nil {
@@ -96,17 +136,10 @@ func (h *StoryHandler) CreateStory(c *gin.Context) {
         zap.String("type", string(story.Type)),
     )
 
-    // Send real-time notification
-    if h.wsHub != nil {
-        event := &realtime.Event{
-            Type: realtime.EventStoryCreated,
-            Payload: gin.H{
-                "story":  story,
-                "author": user.ToResponse(),
-            },
-        }
-        h.wsHub.BroadcastToFollowers(user.ID, event)
-    }
+    // Send real-time notification (drafts stay private until published)
+    h.broadcastStory(c.Request.Context(), story, user)
+
+    h.notifyMentions(story, user, nil)
 
     c.JSON(http.StatusCreated, story)
 }
@@ -151,6 +184,11 @@ func (h *StoryHandler) GetStories(c *gin.Context) {
         return
     }
 
+    for i, story := range stories {
+        h.attachOriginal(c.Request.Context(), story, user.ID)
+        stories[i] = story.ForViewer(user.ID)
+    }
+
     c.JSON(http.StatusOK, gin.H{
         "stories": stories,
         "count":   len(stories),
@@ -209,7 +247,9 @@ func (h *StoryHandler) GetStory(c *gin.Context) {
         return
     }
 
-    c.JSON(http.StatusOK, story)
+    h.attachOriginal(c.Request.Context(), story, user.ID)
+
+    c.JSON(http.StatusOK, story.ForViewer(user.ID))
 }
 
 // UpdateStory updates a story
@@ -274,11 +314,32 @@ func (h *StoryHandler) UpdateStory(c *gin.Context) {
         return
     }
 
+    // Drafts have their own endpoint, and expired or archived stories can't be changed
+    if story.IsDraft() {
+        c.JSON(http.StatusConflict, gin.H{
+            "error":   "not_published",
+            "message": "Drafts are updated with PUT /stories/:id/draft",
+        })
+        return
+    }
+    if story.IsExpired() || story.IsArchived() {
+        c.JSON(http.StatusConflict, gin.H{
+            "error":   "story_expired",
+            "message": "Expired stories can't be edited",
+        })
+        return
+    }
+
     // Update story
-    story.Update(req)
+    previouslyMentioned := models.MentionedUserIDs(story.Entities)
+    previousVisibility := story.Visibility
+    revision := story.Edit(req, user.ID)
+    if req.Text != nil {
+        h.resolveEntities(c.Request.Context(), story)
+    }
 
     // Save to database
-    if err := h.storyStore.Update(c.Request.Context(), story); err != nil {
+    if err := h.storyStore.Update(c.Request.Context(), story, revision); err != nil {
         h.logger.Error("Failed to update story", 
             zap.String("story_id", storyID.String()),
             zap.Error(err),
@@ -290,6 +351,22 @@ func (h *StoryHandler) UpdateStory(c *gin.Context) {
         return
     }
 
+    // Live counter subscribers may not be allowed to see the story any more
+    if h.wsHub != nil && story.Visibility != previousVisibility {
+        h.wsHub.RecheckTopic(realtime.StoryTopic(story.ID))
+    }
+
+    if req.Text != nil {
+        if err := h.storyStore.SetEntities(c.Request.Context(), story.ID, story.Entities); err != nil {
+            h.logger.Error("Failed to update story entities", 
+                zap.String("story_id", storyID.String()),
+                zap.Error(err),
+            )
+        } else {
+            h.notifyMentions(story, user, previouslyMentioned)
+        }
+    }
+
     h.logger.Info("Story updated successfully", 
         zap.String("story_id", storyID.String()),
         zap.String("user_id", user.ID.String()),
@@ -363,6 +440,10 @@ func (h *StoryHandler) DeleteStory(c *gin.Context) {
         return
     }
 
+    if h.wsHub != nil {
+        h.wsHub.RecheckTopic(realtime.StoryTopic(storyID))
+    }
+
     h.logger.Info("Story deleted successfully", 
         zap.String("story_id", storyID.String()),
         zap.String("user_id", user.ID.String()),
@@ -394,43 +475,55 @@ func (h *StoryHandler) ViewStory(c *gin.Context) {
         return
     }
 
-    // Create story view
-    ipAddress := c.ClientIP()
-    userAgent := c.GetHeader("User-Agent")
-    
-    view := models.NewStoryView(storyID, user.ID, &ipAddress, &userAgent)
-
-    // Save view
-    if err := h.viewStore.Create(c.Request.Context(), view); err != nil {
-        h.logger.Error("Failed to create story view", 
+    // Get story to make sure it can be viewed
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+        
+        h.logger.Error("Failed to get story for view", 
             zap.String("story_id", storyID.String()),
-            zap.String("user_id", user.ID.String()),
             zap.Error(err),
         )
-        // Don't return error for views, as it's not critical
-    } else {
-        h.logger.Info("Story view recorded", 
-            zap.String("story_id", storyID.String()),
-            zap.String("viewer_id", user.ID.String()),
-        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return
+    }
 
-        // Send real-time notification to story author
-        if h.wsHub != nil {
-            // Get story to get author ID
-            story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
-            if err == nil && story.AuthorID != user.ID {
-                event := &realtime.Event{
-                    Type: realtime.EventStoryViewed,
-                    Payload: gin.H{
-                        "story_id": storyID,
-                        "viewer":   user.ToResponse(),
-                    },
-                }
-                h.wsHub.SendToUser(story.AuthorID, event)
-            }
+    if err := h.checkViewable(c.Request.Context(), story, user.ID); err != nil {
+        switch err {
+        case storage.ErrNotFound:
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+        case realtime.ErrViewForbidden:
+            c.JSON(http.StatusForbidden, gin.H{
+                "error":   "forbidden",
+                "message": "You don't have permission to view this story",
+            })
+        default:
+            h.logger.Error("Failed to check story visibility for view",
+                zap.String("story_id", storyID.String()),
+                zap.Error(err),
+            )
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error":   "fetch_failed",
+                "message": "Failed to get story",
+            })
         }
+        return
     }
 
+    h.recordView(c.Request.Context(), story, user, c.ClientIP(), c.GetHeader("User-Agent"))
+
     c.JSON(http.StatusOK, gin.H{
         "message": "Story viewed",
     })
@@ -488,8 +581,23 @@ func (h *StoryHandler) GetStoryViews(c *gin.Context) {
         return
     }
 
+    // Parse query parameters
+    limit := 20
+    if l := c.Query("limit"); l != "" {
+        if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+            limit = parsed
+        }
+    }
+
+    offset := 0
+    if o := c.Query("offset"); o != "" {
+        if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+            offset = parsed
+        }
+    }
+
     // Get views
-    views, err := h.viewStore.GetByStoryID(c.Request.Context(), storyID, 50, 0)
+    views, err := h.viewStore.GetByStoryID(c.Request.Context(), storyID, limit, offset)
     if err != nil {
         h.logger.Error("Failed to get story views", 
             zap.String("story_id", storyID.String()),
@@ -508,6 +616,80 @@ func (h *StoryHandler) GetStoryViews(c *gin.Context) {
     })
 }
 
+// GetSeenBy gets a compact "seen by" summary of a story for its author
+func (h *StoryHandler) GetSeenBy(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    // Parse story ID
+    storyID, err := uuid.Parse(c.Param("id"))
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_id",
+            "message": "Invalid story ID",
+        })
+        return
+    }
+
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to get story for seen by",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return
+    }
+
+    // Only story author can see who viewed it
+    if story.AuthorID != user.ID {
+        c.JSON(http.StatusForbidden, gin.H{
+            "error":   "forbidden",
+            "message": "You can only view your own story views",
+        })
+        return
+    }
+
+    limit := models.DefaultSeenByViewers
+    if l := c.Query("limit"); l != "" {
+        if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= models.MaxSeenByViewers {
+            limit = parsed
+        }
+    }
+
+    summary, err := h.viewStore.GetSeenBy(c.Request.Context(), storyID, user.ID, limit)
+    if err != nil {
+        h.logger.Error("Failed to get seen by summary",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story views",
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, summary)
+}
+
 // GetStoryReactions gets reactions for a story
 func (h *StoryHandler) GetStoryReactions(c *gin.Context) {
     // Parse story ID
@@ -520,6 +702,36 @@ func (h *StoryHandler) GetStoryReactions(c *gin.Context) {
         return
     }
 
+    // Get story to make sure it is published
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+        
+        h.logger.Error("Failed to get story for reactions", 
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
+        })
+        return
+    }
+
+    if story.IsDraft() {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error":   "not_found",
+            "message": "Story not found",
+        })
+        return
+    }
+
     // Get reactions
     reactions, err := h.reactionStore.GetByStoryID(c.Request.Context(), storyID, 100, 0)
     if err != nil {
@@ -534,9 +746,24 @@ func (h *StoryHandler) GetStoryReactions(c *gin.Context) {
         return
     }
 
+    // Per-type totals, including custom and retired types
+    summary, err := h.reactionStore.GetReactionSummary(c.Request.Context(), storyID)
+    if err != nil {
+        h.logger.Error("Failed to get reaction summary", 
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get reactions",
+        })
+        return
+    }
+
     c.JSON(http.StatusOK, gin.H{
         "reactions": reactions,
         "count":     len(reactions),
+        "summary":   summary,
     })
 }
 
@@ -571,17 +798,48 @@ func (h *StoryHandler) AddReaction(c *gin.Context) {
         return
     }
 
-    // Validate reaction type
-    if !models.ValidateReactionType(string(req.Type)) {
-        c.JSON(http.StatusBadRequest, gin.H{
-            "error":   "invalid_reaction",
-            "message": "Invalid reaction type",
+    // Resolve the reaction against the catalogue
+    reactionType, ok := h.resolveReactionType(c, req.Type)
+    if !ok {
+        return
+    }
+
+    // Get story to make sure it can be reacted to
+    story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+        
+        h.logger.Error("Failed to get story for reaction", 
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
         })
         return
     }
 
+    if story.IsDraft() {
+        c.JSON(http.StatusNotFound, gin.H{
+            "error":   "not_found",
+            "message": "Story not found",
+        })
+        return
+    }
+
+    if !h.checkCanReact(c, story, user.ID) {
+        return
+    }
+
     // Create reaction
-    reaction := models.NewReaction(storyID, user.ID, req.Type)
+    reaction := models.NewReaction(storyID, user.ID, reactionType)
 
     // Save reaction
     if err := h.reactionStore.Create(c.Request.Context(), reaction); err != nil {
@@ -601,26 +859,24 @@ func (h *StoryHandler) AddReaction(c *gin.Context) {
         zap.String("reaction_id", reaction.ID.String()),
         zap.String("story_id", storyID.String()),
         zap.String("user_id", user.ID.String()),
-        zap.String("type", string(req.Type)),
+        zap.String("type", string(reactionType)),
     )
 
     // Send real-time notification
-    if h.wsHub != nil {
-        // Get story to get author ID
-        story, err := h.storyStore.GetByID(c.Request.Context(), storyID)
-        if err == nil && story.AuthorID != user.ID {
-            event := &realtime.Event{
-                Type: realtime.EventStoryReaction,
-                Payload: gin.H{
-                    "story_id": storyID,
-                    "reaction": reaction,
-                    "user":     user.ToResponse(),
-                },
-            }
-            h.wsHub.SendToUser(story.AuthorID, event)
+    if h.wsHub != nil && story.AuthorID != user.ID {
+        event := &realtime.Event{
+            Type: realtime.EventStoryReaction,
+            Payload: gin.H{
+                "story_id": storyID,
+                "reaction": reaction,
+                "user":     user.ToResponse(),
+            },
         }
+        h.wsHub.SendToUser(story.AuthorID, event)
     }
 
+    h.storyCountersChanged(storyID)
+
     c.JSON(http.StatusCreated, reaction)
 }
 
@@ -686,17 +942,40 @@ func (h *StoryHandler) UpdateReaction(c *gin.Context) {
         return
     }
 
-    // Validate reaction type
-    if !models.ValidateReactionType(string(req.Type)) {
-        c.JSON(http.StatusBadRequest, gin.H{
-            "error":   "invalid_reaction",
-            "message": "Invalid reaction type",
+    // Resolve the reaction against the catalogue
+    reactionType, ok := h.resolveReactionType(c, req.Type)
+    if !ok {
+        return
+    }
+
+    // Changing a reaction is subject to the story's current reaction settings
+    story, err := h.storyStore.GetByID(c.Request.Context(), reaction.StoryID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            c.JSON(http.StatusNotFound, gin.H{
+                "error":   "not_found",
+                "message": "Story not found",
+            })
+            return
+        }
+
+        h.logger.Error("Failed to get story for reaction update",
+            zap.String("story_id", reaction.StoryID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get story",
         })
         return
     }
 
+    if !h.checkCanReact(c, story, user.ID) {
+        return
+    }
+
     // Update reaction
-    reaction.Update(req.Type)
+    reaction.Update(reactionType)
 
     // Save reaction
     if err := h.reactionStore.Update(c.Request.Context(), reaction); err != nil {
@@ -789,6 +1068,8 @@ func (h *StoryHandler) RemoveReaction(c *gin.Context) {
         zap.String("user_id", user.ID.String()),
     )
 
+    h.storyCountersChanged(reaction.StoryID)
+
     c.JSON(http.StatusOK, gin.H{
         "message": "Reaction removed successfully",
     })
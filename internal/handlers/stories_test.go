@@ -0,0 +1,57 @@
+package handlers
+
+import (
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+func TestUpdateStoryOnlyWhilePublished(t *testing.T) {
+    author := newTestUser("author")
+    request := models.StoryUpdateRequest{Visibility: models.VisibilityFriends}
+
+    tests := []struct {
+        name   string
+        story  func() *models.Story
+        status int
+        code   string
+    }{
+        {name: "published", story: func() *models.Story {
+            return newTestStory(author.ID, models.VisibilityPublic)
+        }, status: http.StatusOK},
+        {name: "draft", story: func() *models.Story {
+            return models.NewStory(author.ID, models.StoryCreateRequest{Type: models.StoryTypeText, Visibility: models.VisibilityPublic, Draft: true})
+        }, status: http.StatusConflict, code: "not_published"},
+        {name: "expired", story: func() *models.Story {
+            story := newTestStory(author.ID, models.VisibilityPublic)
+            story.ExpiresAt = time.Now().Add(-time.Minute)
+            return story
+        }, status: http.StatusConflict, code: "story_expired"},
+        {name: "archived", story: func() *models.Story {
+            story := newTestStory(author.ID, models.VisibilityPublic)
+            story.ExpiresAt = time.Now().Add(-time.Minute)
+            story.Archive()
+            return story
+        }, status: http.StatusConflict, code: "story_expired"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            handler, stores := newTestStoryHandler()
+            story := stores.stories.add(tt.story())
+
+            recorder, response := serve(t, handler.UpdateStory, author, http.MethodPut, request, idParam(story.ID))
+            checkStatus(t, recorder, response, tt.status, tt.code)
+
+            wantUpdates := 0
+            if tt.status == http.StatusOK {
+                wantUpdates = 1
+            }
+            if len(stores.stories.updated) != wantUpdates {
+                t.Errorf("saved %d updates, want %d", len(stores.stories.updated), wantUpdates)
+            }
+        })
+    }
+}
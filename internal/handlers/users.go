@@ -3,6 +3,7 @@ package handlers
 import (
     "net/http"
     "strconv"
+    "strings"
 
     "github.com/gin-gonic/gin"
     "github.com/google/uuid"
@@ -10,22 +11,32 @@ import (
 
     "github.com/Abhiro0p/stories-backend/internal/auth"
     "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/realtime"
     "github.com/Abhiro0p/stories-backend/internal/storage"
     "github.com/Abhiro0p/stories-backend/pkg/validator"
 )
 
+// maxPresenceLookup caps the users in one presence request
+const maxPresenceLookup = 100
+
 // UserHandler handles user-related endpoints
 type UserHandler struct {
     userStore   storage.UserStore
     followStore storage.FollowStore
+    blockStore  storage.BlockStore
+    wsHub       *realtime.Hub
+    presence    *realtime.Presence
     logger      *zap.Logger
 }
 
 // NewUserHandler creates a new user handler
-func NewUserHandler(userStore storage.UserStore, followStore storage.FollowStore, logger *zap.Logger) *UserHandler {
+func NewUserHandler(userStore storage.UserStore, followStore storage.FollowStore, blockStore storage.BlockStore, wsHub *realtime.Hub, presence *realtime.Presence, logger *zap.Logger) *UserHandler {
     return &UserHandler{
         userStore:   userStore,
         followStore: followStore,
+        blockStore:  blockStore,
+        wsHub:       wsHub,
+        presence:    presence,
         logger:      logger.With(zap.String("handler", "user")),
     }
 }
@@ -41,7 +52,7 @@ func (h *UserHandler) GetCurrentUser(c *gin.Context) {
         return
     }
 
-    c.JSON(http.StatusOK, user.ToResponse())
+    c.JSON(http.StatusOK, user.ToPrivateResponse())
 }
 
 // UpdateCurrentUser updates the current user's profile
@@ -109,7 +120,7 @@ func (h *UserHandler) UpdateCurrentUser(c *gin.Context) {
         zap.String("user_id", user.ID.String()),
     )
 
-    c.JSON(http.StatusOK, user.ToResponse())
+    c.JSON(http.StatusOK, user.ToPrivateResponse())
 }
 
 // GetUser gets a user by ID
@@ -153,6 +164,9 @@ func (h *UserHandler) GetUser(c *gin.Context) {
         if err == nil {
             user.IsFollowing = isFollowing
         }
+        if h.presence != nil {
+            h.presence.Fill(c.Request.Context(), currentUser, user)
+        }
     }
 
     c.JSON(http.StatusOK, user.ToResponse())
@@ -198,6 +212,10 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
         return
     }
 
+    if currentUser, ok := auth.GetCurrentUser(c); ok && h.presence != nil {
+        h.presence.Fill(c.Request.Context(), currentUser, users...)
+    }
+
     // Convert to response format
     responses := make([]*models.UserResponse, len(users))
     for i, user := range users {
@@ -211,6 +229,99 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
     })
 }
 
+// GetPresence gets the online status and last active time of several users.
+// Users whose activity the current user can't see are left out.
+func (h *UserHandler) GetPresence(c *gin.Context) {
+    currentUser, ok := auth.GetCurrentUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "User not found in context",
+        })
+        return
+    }
+
+    var userIDs []uuid.UUID
+    for _, value := range strings.Split(c.Query("ids"), ",") {
+        value = strings.TrimSpace(value)
+        if value == "" {
+            continue
+        }
+        userID, err := uuid.Parse(value)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error":   "invalid_id",
+                "message": "Invalid user ID",
+            })
+            return
+        }
+        userIDs = append(userIDs, userID)
+    }
+
+    if len(userIDs) == 0 {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "missing_ids",
+            "message": "At least one user ID is required",
+        })
+        return
+    }
+    if len(userIDs) > maxPresenceLookup {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "too_many_ids",
+            "message": "Too many user IDs",
+        })
+        return
+    }
+
+    if h.presence == nil {
+        c.JSON(http.StatusOK, gin.H{
+            "presence": []*models.UserPresence{},
+            "count":    0,
+        })
+        return
+    }
+
+    users, err := h.userStore.GetByIDs(c.Request.Context(), userIDs)
+    if err != nil {
+        h.logger.Error("Failed to get users for presence",
+            zap.Int("users", len(userIDs)),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get presence",
+        })
+        return
+    }
+
+    presences, err := h.presence.Lookup(c.Request.Context(), currentUser, users)
+    if err != nil {
+        h.logger.Error("Failed to look up presence",
+            zap.String("user_id", currentUser.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "fetch_failed",
+            "message": "Failed to get presence",
+        })
+        return
+    }
+
+    // Keep the order the IDs were asked in, listing each user once
+    responses := make([]*models.UserPresence, 0, len(presences))
+    for _, userID := range userIDs {
+        if presence, ok := presences[userID]; ok {
+            responses = append(responses, presence)
+            delete(presences, userID)
+        }
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "presence": responses,
+        "count":    len(responses),
+    })
+}
+
 // FollowUser follows a user
 func (h *UserHandler) FollowUser(c *gin.Context) {
     currentUser, ok := auth.GetCurrentUser(c)
@@ -301,6 +412,10 @@ func (h *UserHandler) FollowUser(c *gin.Context) {
         zap.String("followee_id", targetUserID.String()),
     )
 
+    if h.wsHub != nil {
+        h.wsHub.UpdateFollow(currentUser.ID, targetUserID, true)
+    }
+
     c.JSON(http.StatusCreated, gin.H{
         "message":     "User followed successfully",
         "followed_user": targetUser.ToResponse(),
@@ -372,6 +487,12 @@ func (h *UserHandler) UnfollowUser(c *gin.Context) {
         zap.String("followee_id", targetUserID.String()),
     )
 
+    if h.wsHub != nil {
+        h.wsHub.UpdateFollow(currentUser.ID, targetUserID, false)
+        // Friends-only story topics were allowed because of the follow
+        h.wsHub.RecheckUserSubscriptions(currentUser.ID)
+    }
+
     c.JSON(http.StatusOK, gin.H{
         "message": "User unfollowed successfully",
     })
@@ -2,64 +2,109 @@ package handlers
 
 import (
     "net/http"
+    "net/url"
+    "strconv"
+    "strings"
 
     "github.com/gin-gonic/gin"
     "github.com/gorilla/websocket"
     "go.uber.org/zap"
 
     "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
     "github.com/Abhiro0p/stories-backend/internal/realtime"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
 )
 
 // WebSocketHandler handles WebSocket connections
 type WebSocketHandler struct {
     hub         *realtime.Hub
     authService *auth.Service
+    tickets     *realtime.TicketStore
+    origins     config.CORSConfig
+    queryToken  bool
     logger      *zap.Logger
     upgrader    websocket.Upgrader
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
-func NewWebSocketHandler(hub *realtime.Hub, authService *auth.Service, logger *zap.Logger) *WebSocketHandler {
-    return &WebSocketHandler{
+func NewWebSocketHandler(hub *realtime.Hub, authService *auth.Service, tickets *realtime.TicketStore, cfg *config.Config, logger *zap.Logger) *WebSocketHandler {
+    h := &WebSocketHandler{
         hub:         hub,
         authService: authService,
+        tickets:     tickets,
+        origins:     websocketOrigins(cfg.CORS),
+        queryToken:  cfg.Realtime.AllowQueryToken,
         logger:      logger.With(zap.String("handler", "websocket")),
-        upgrader: websocket.Upgrader{
-            ReadBufferSize:  1024,
-            WriteBufferSize: 1024,
-            CheckOrigin: func(r *http.Request) bool {
-                // TODO: Implement proper origin checking for production
-                return true
-            },
-        },
     }
+    h.upgrader = websocket.Upgrader{
+        ReadBufferSize:    1024,
+        WriteBufferSize:   1024,
+        Subprotocols:      realtime.Subprotocols,
+        EnableCompression: cfg.Realtime.Compression,
+        CheckOrigin:       h.checkOrigin,
+    }
+
+    if cfg.CORS.Enabled && len(h.origins.AllowedOrigins) < len(cfg.CORS.AllowedOrigins) {
+        h.logger.Warn("CORS allows every origin, but websocket upgrades are only accepted from listed origins")
+    }
+
+    return h
 }
 
-// HandleWebSocket handles WebSocket connection upgrade and management
-func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
-    // Get token from query parameter
-    token := c.Query("token")
-    if token == "" {
-        h.logger.Warn("WebSocket connection attempted without token")
+// IssueTicket exchanges the caller's access token for a short-lived ticket
+// that opens one realtime connection, keeping the token out of URLs
+func (h *WebSocketHandler) IssueTicket(c *gin.Context) {
+    user, ok := auth.GetCurrentUser(c)
+    if !ok {
         c.JSON(http.StatusUnauthorized, gin.H{
             "error":   "unauthorized",
-            "message": "Token is required for WebSocket connection",
+            "message": "User not found in context",
         })
         return
     }
 
-    // Validate token and get user
-    user, err := h.authService.ValidateToken(token)
-    if err != nil {
-        h.logger.Warn("WebSocket connection with invalid token", zap.Error(err))
+    token, ok := auth.GetCurrentToken(c)
+    if !ok {
         c.JSON(http.StatusUnauthorized, gin.H{
             "error":   "unauthorized",
-            "message": "Invalid or expired token",
+            "message": "Token not found in context",
         })
         return
     }
 
+    ticket, err := h.tickets.Issue(c.Request.Context(), user.ID, token)
+    if err != nil {
+        h.logger.Error("Failed to issue realtime ticket",
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "ticket_failed",
+            "message": "Failed to issue ticket",
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "ticket":     ticket,
+        "expires_in": int(realtime.TicketTTL.Seconds()),
+    })
+}
+
+// HandleWebSocket handles WebSocket connection upgrade and management
+func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
+    user, token, ok := h.authenticate(c)
+    if !ok {
+        return
+    }
+
+    // Reconnecting clients pass the seq of the last event they received
+    lastEventID, resume, ok := parseLastEventID(c, c.Query("last_event_id"))
+    if !ok {
+        return
+    }
+
     // Upgrade HTTP connection to WebSocket
     conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
     if err != nil {
@@ -71,13 +116,203 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
         zap.String("user_id", user.ID.String()),
         zap.String("username", user.Username),
         zap.String("remote_addr", c.Request.RemoteAddr),
+        zap.String("subprotocol", conn.Subprotocol()),
     )
 
     // Create and register client
-    client := realtime.NewClient(h.hub, conn, user, h.logger)
-    h.hub.Register(client)
+    client := realtime.NewClient(h.hub, conn, user, token, h.logger)
+    client.SetRequestInfo(c.ClientIP(), c.GetHeader("User-Agent"))
+    if resume {
+        h.hub.Resume(client, lastEventID)
+    } else {
+        h.hub.Register(client)
+    }
 
     // Start client goroutines
     go client.WritePump()
     go client.ReadPump()
 }
+
+// HandleEvents streams the same events as HandleWebSocket over Server-Sent
+// Events, for networks that block websockets. Topics to subscribe to are passed
+// as a comma-separated topics query parameter.
+func (h *WebSocketHandler) HandleEvents(c *gin.Context) {
+    user, token, ok := h.authenticate(c)
+    if !ok {
+        return
+    }
+
+    // Browsers send Last-Event-ID when they reconnect a dropped stream
+    lastEventValue := c.GetHeader("Last-Event-ID")
+    if lastEventValue == "" {
+        lastEventValue = c.Query("last_event_id")
+    }
+    lastEventID, resume, ok := parseLastEventID(c, lastEventValue)
+    if !ok {
+        return
+    }
+
+    var topics []string
+    for _, topic := range strings.Split(c.Query("topics"), ",") {
+        if topic = strings.TrimSpace(topic); topic != "" {
+            topics = append(topics, topic)
+        }
+    }
+
+    if _, ok := c.Writer.(http.Flusher); !ok {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "error":   "streaming_unsupported",
+            "message": "Streaming is not supported",
+        })
+        return
+    }
+
+    c.Header("Content-Type", "text/event-stream")
+    c.Header("Cache-Control", "no-cache")
+    c.Header("Connection", "keep-alive")
+    c.Header("X-Accel-Buffering", "no")
+    c.Status(http.StatusOK)
+
+    h.logger.Info("SSE connection established",
+        zap.String("user_id", user.ID.String()),
+        zap.String("username", user.Username),
+        zap.String("remote_addr", c.Request.RemoteAddr),
+    )
+
+    client := realtime.NewSSEClient(h.hub, user, token, h.logger)
+    if resume {
+        // Topics are part of the stream, so events missed on them count too
+        var resumeTopics []realtime.Topic
+        for _, value := range topics {
+            if topic, err := realtime.ParseTopic(value); err == nil {
+                resumeTopics = append(resumeTopics, topic)
+            }
+        }
+        h.hub.Resume(client, lastEventID, resumeTopics...)
+    } else {
+        h.hub.Register(client)
+    }
+    if len(topics) > 0 {
+        go client.SubscribeTopics(topics)
+    }
+
+    // Blocks until the client disconnects
+    client.StreamSSE(c.Request.Context(), c.Writer)
+}
+
+// authenticate validates the ticket query parameter of a realtime connection,
+// or the deprecated token parameter when it is allowed, responding with 401 if
+// neither is valid. It returns the access token so the connection can be
+// checked against it later.
+func (h *WebSocketHandler) authenticate(c *gin.Context) (*models.User, string, bool) {
+    token := c.Query("token")
+    if token != "" && c.Query("ticket") == "" {
+        if !h.queryToken {
+            c.JSON(http.StatusUnauthorized, gin.H{
+                "error":   "unauthorized",
+                "message": "Tokens aren't accepted in URLs, use a ticket from /api/v1/realtime/ticket",
+            })
+            return nil, "", false
+        }
+        h.logger.Warn("Realtime connection with deprecated token query parameter",
+            zap.String("remote_addr", c.Request.RemoteAddr),
+            zap.String("user_agent", c.GetHeader("User-Agent")),
+        )
+    }
+
+    if ticket := c.Query("ticket"); ticket != "" {
+        redeemed, err := h.tickets.Redeem(c.Request.Context(), ticket)
+        if err != nil {
+            if err != realtime.ErrInvalidTicket {
+                h.logger.Error("Failed to redeem realtime ticket", zap.Error(err))
+            }
+            c.JSON(http.StatusUnauthorized, gin.H{
+                "error":   "unauthorized",
+                "message": "Invalid or expired ticket",
+            })
+            return nil, "", false
+        }
+        token = redeemed
+    }
+
+    if token == "" {
+        h.logger.Warn("Realtime connection attempted without token")
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "A ticket is required for realtime connection",
+        })
+        return nil, "", false
+    }
+
+    // Validate token and get user
+    user, err := h.authService.ValidateToken(token)
+    if err != nil {
+        h.logger.Warn("Realtime connection with invalid token", zap.Error(err))
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "error":   "unauthorized",
+            "message": "Invalid or expired token",
+        })
+        return nil, "", false
+    }
+
+    return user, token, true
+}
+
+// checkOrigin allows websocket upgrades from the API's own origin and the CORS
+// allowed origins. Requests without an Origin header come from native clients,
+// not browsers, and are allowed.
+func (h *WebSocketHandler) checkOrigin(r *http.Request) bool {
+    origin := r.Header.Get("Origin")
+    if origin == "" {
+        return true
+    }
+
+    allowed := h.origins.AllowsOrigin(origin)
+    if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+        allowed = true
+    }
+
+    if !allowed {
+        h.logger.Warn("WebSocket connection from disallowed origin",
+            zap.String("origin", origin),
+            zap.String("remote_addr", r.RemoteAddr),
+        )
+    }
+    return allowed
+}
+
+// websocketOrigins returns the CORS allowed origins without "*". Browsers don't
+// apply CORS to websockets, so the origin check is all that stops other sites
+// from connecting, and websocket origins have to be listed by name.
+func websocketOrigins(cors config.CORSConfig) config.CORSConfig {
+    origins := config.CORSConfig{Enabled: cors.Enabled}
+    if !cors.Enabled {
+        return origins
+    }
+    for _, origin := range cors.AllowedOrigins {
+        if origin != "*" {
+            origins.AllowedOrigins = append(origins.AllowedOrigins, origin)
+        }
+    }
+    return origins
+}
+
+// parseLastEventID parses the seq a reconnecting client last received. It
+// reports whether the client is resuming, and responds with 400 if the value
+// is invalid.
+func parseLastEventID(c *gin.Context, value string) (int64, bool, bool) {
+    if value == "" {
+        return 0, false, true
+    }
+
+    lastEventID, err := strconv.ParseInt(value, 10, 64)
+    if err != nil || lastEventID < 0 {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error":   "invalid_last_event_id",
+            "message": "last_event_id must be a non-negative integer",
+        })
+        return 0, false, false
+    }
+
+    return lastEventID, true, true
+}
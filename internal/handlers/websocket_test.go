@@ -0,0 +1,305 @@
+package handlers
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/gin-gonic/gin"
+    "github.com/golang-jwt/jwt/v5"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/realtime"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// streamTimeout bounds how long a test waits for the event stream
+const streamTimeout = 2 * time.Second
+
+// newTestWebSocketHandler creates a websocket handler with no hub or tickets.
+// Tokens are checked against a secret nothing was signed with.
+func newTestWebSocketHandler(cfg *config.Config) *WebSocketHandler {
+    cfg.JWTSecret = "test-secret"
+    authService := auth.NewService(cfg, nil, nil, zap.NewNop())
+    return NewWebSocketHandler(nil, authService, nil, cfg, zap.NewNop())
+}
+
+func TestQueryTokenNeedsFlag(t *testing.T) {
+    tests := []struct {
+        name    string
+        allow   bool
+        message string
+    }{
+        {name: "rejected by default", allow: false, message: "Tokens aren't accepted in URLs, use a ticket from /api/v1/realtime/ticket"},
+        {name: "validated when allowed", allow: true, message: "Invalid or expired token"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            handler := newTestWebSocketHandler(&config.Config{Realtime: config.RealtimeConfig{AllowQueryToken: tt.allow}})
+
+            recorder := httptest.NewRecorder()
+            c, _ := gin.CreateTestContext(recorder)
+            c.Request = httptest.NewRequest(http.MethodGet, "/ws?token=not-a-jwt", nil)
+
+            handler.HandleWebSocket(c)
+
+            var response map[string]interface{}
+            if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+                t.Fatalf("failed to decode response %q: %v", recorder.Body.String(), err)
+            }
+            checkStatus(t, recorder, response, http.StatusUnauthorized, "unauthorized")
+            if response["message"] != tt.message {
+                t.Errorf("message = %v, want %s", response["message"], tt.message)
+            }
+        })
+    }
+}
+
+func TestCheckOrigin(t *testing.T) {
+    tests := []struct {
+        name    string
+        cors    config.CORSConfig
+        origin  string
+        allowed bool
+    }{
+        {name: "native client", cors: config.CORSConfig{Enabled: true, AllowedOrigins: []string{"*"}}, allowed: true},
+        {name: "same origin", cors: config.CORSConfig{Enabled: true, AllowedOrigins: []string{"*"}}, origin: "https://api.example.com", allowed: true},
+        {name: "any origin ignored", cors: config.CORSConfig{Enabled: true, AllowedOrigins: []string{"*"}}, origin: "https://evil.example.net", allowed: false},
+        {name: "listed origin", cors: config.CORSConfig{Enabled: true, AllowedOrigins: []string{"*", "https://app.example.com"}}, origin: "https://app.example.com", allowed: true},
+        {name: "listed subdomains", cors: config.CORSConfig{Enabled: true, AllowedOrigins: []string{"*.example.com"}}, origin: "https://app.example.com", allowed: true},
+        {name: "unlisted origin", cors: config.CORSConfig{Enabled: true, AllowedOrigins: []string{"https://app.example.com"}}, origin: "https://evil.example.net", allowed: false},
+        {name: "cors disabled", cors: config.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}, origin: "https://app.example.com", allowed: false},
+        {name: "cors disabled same origin", cors: config.CORSConfig{}, origin: "https://api.example.com", allowed: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            handler := newTestWebSocketHandler(&config.Config{CORS: tt.cors})
+
+            request := httptest.NewRequest(http.MethodGet, "https://api.example.com/ws", nil)
+            if tt.origin != "" {
+                request.Header.Set("Origin", tt.origin)
+            }
+
+            if got := handler.checkOrigin(request); got != tt.allowed {
+                t.Errorf("checkOrigin(%q) = %v, want %v", tt.origin, got, tt.allowed)
+            }
+        })
+    }
+}
+
+// eventsServer serves /events from a running hub that keeps events for replay
+type eventsServer struct {
+    url     string
+    hub     *realtime.Hub
+    tickets *realtime.TicketStore
+    user    *models.User
+    token   string
+}
+
+func newEventsServer(t *testing.T) *eventsServer {
+    t.Helper()
+
+    redisServer := miniredis.RunT(t)
+    cfg := &config.Config{RedisURL: "redis://" + redisServer.Addr(), JWTSecret: "test-secret"}
+    redisClient, err := storage.NewRedisClient(cfg, zap.NewNop())
+    if err != nil {
+        t.Fatalf("failed to connect to test Redis: %v", err)
+    }
+    t.Cleanup(func() { redisClient.Close() })
+
+    users := &fakeUserStore{}
+    user := users.add(&models.User{ID: uuid.New(), Username: "user", IsActive: true})
+    authService := auth.NewService(cfg, users, redisClient, zap.NewNop())
+
+    replay := realtime.NewReplayBuffer(redisClient, cfg.Realtime, zap.NewNop())
+    hub := realtime.NewHub(nil, nil, nil, replay, nil, cfg.Realtime, zap.NewNop())
+    go hub.Run()
+
+    tickets := realtime.NewTicketStore(redisClient, zap.NewNop())
+    handler := NewWebSocketHandler(hub, authService, tickets, cfg, zap.NewNop())
+    router := gin.New()
+    router.GET("/events", handler.HandleEvents)
+    server := httptest.NewServer(router)
+    t.Cleanup(server.Close)
+
+    claims := &models.TokenClaims{
+        UserID:  user.ID,
+        TokenID: uuid.NewString(),
+        RegisteredClaims: jwt.RegisteredClaims{
+            IssuedAt:  jwt.NewNumericDate(time.Now()),
+            ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+        },
+    }
+    token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.JWTSecret))
+    if err != nil {
+        t.Fatalf("failed to sign token: %v", err)
+    }
+
+    return &eventsServer{url: server.URL, hub: hub, tickets: tickets, user: user, token: token}
+}
+
+// sseFrame is one event read from the stream
+type sseFrame struct {
+    id   string
+    data string
+}
+
+// open connects to the stream with a fresh ticket, resuming after lastEventID
+// if it is set. Events arrive on the channel, which is closed when the stream
+// ends. Cancelling the context disconnects.
+func (s *eventsServer) open(t *testing.T, ctx context.Context, lastEventID string) <-chan sseFrame {
+    t.Helper()
+
+    ticket, err := s.tickets.Issue(ctx, s.user.ID, s.token)
+    if err != nil {
+        t.Fatalf("failed to issue ticket: %v", err)
+    }
+
+    request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"/events?ticket="+ticket, nil)
+    if err != nil {
+        t.Fatalf("failed to build request: %v", err)
+    }
+    if lastEventID != "" {
+        request.Header.Set("Last-Event-ID", lastEventID)
+    }
+
+    response, err := http.DefaultClient.Do(request)
+    if err != nil {
+        t.Fatalf("failed to connect: %v", err)
+    }
+    if response.StatusCode != http.StatusOK {
+        response.Body.Close()
+        t.Fatalf("status = %d, want %d", response.StatusCode, http.StatusOK)
+    }
+    if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+        t.Errorf("Content-Type = %q, want text/event-stream", contentType)
+    }
+
+    frames := make(chan sseFrame, 64)
+    go func() {
+        defer close(frames)
+        defer response.Body.Close()
+
+        // Comments and the retry field aren't events, so they are skipped
+        var frame sseFrame
+        scanner := bufio.NewScanner(response.Body)
+        for scanner.Scan() {
+            line := scanner.Text()
+            switch {
+            case line == "":
+                if frame.data != "" {
+                    frames <- frame
+                }
+                frame = sseFrame{}
+            case strings.HasPrefix(line, "id: "):
+                frame.id = strings.TrimPrefix(line, "id: ")
+            case strings.HasPrefix(line, "data: "):
+                frame.data += strings.TrimPrefix(line, "data: ")
+            }
+        }
+    }()
+
+    return frames
+}
+
+// nextEvent waits for the next event on the stream and decodes it
+func nextEvent(t *testing.T, frames <-chan sseFrame) (sseFrame, *realtime.Event) {
+    t.Helper()
+
+    select {
+    case frame, ok := <-frames:
+        if !ok {
+            t.Fatal("stream ended")
+        }
+        event, err := realtime.FromJSON([]byte(frame.data))
+        if err != nil {
+            t.Fatalf("failed to decode event %q: %v", frame.data, err)
+        }
+        return frame, event
+    case <-time.After(streamTimeout):
+        t.Fatal("timed out waiting for an event")
+        return sseFrame{}, nil
+    }
+}
+
+// nextSequenced skips unsequenced events such as the welcome and returns the
+// next sequenced one, checking its id matches its seq
+func nextSequenced(t *testing.T, frames <-chan sseFrame) *realtime.Event {
+    t.Helper()
+
+    for {
+        frame, event := nextEvent(t, frames)
+        if event.Seq == 0 {
+            if frame.id != "" {
+                t.Errorf("unsequenced %s event has id %q", event.Type, frame.id)
+            }
+            continue
+        }
+        if frame.id != fmt.Sprint(event.Seq) {
+            t.Errorf("%s event with seq %d has id %q", event.Type, event.Seq, frame.id)
+        }
+        return event
+    }
+}
+
+func TestEventStreamResumesFromLastEventID(t *testing.T) {
+    server := newEventsServer(t)
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+
+    // Sent while the user is away, so they are only in the replay buffer
+    for i := 1; i <= 3; i++ {
+        server.hub.SendToUser(server.user.ID, realtime.NotificationEvent("test", "Missed", fmt.Sprint(i), nil))
+    }
+
+    frames := server.open(t, ctx, "1")
+
+    for _, want := range []int64{2, 3} {
+        if event := nextSequenced(t, frames); event.Seq != want || event.Type != realtime.EventNotification {
+            t.Fatalf("got %s event with seq %d, want replayed notification %d", event.Type, event.Seq, want)
+        }
+    }
+
+    server.hub.SendToUser(server.user.ID, realtime.NotificationEvent("test", "Live", "4", nil))
+    if event := nextSequenced(t, frames); event.Seq != 4 {
+        t.Errorf("got live event with seq %d, want 4", event.Seq)
+    }
+}
+
+func TestEventStreamStopsWhenClientDisconnects(t *testing.T) {
+    server := newEventsServer(t)
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+
+    frames := server.open(t, ctx, "")
+    if _, event := nextEvent(t, frames); event.Type != realtime.EventWelcome {
+        t.Fatalf("got %s event, want welcome", event.Type)
+    }
+    if !server.hub.IsUserConnected(server.user.ID) {
+        t.Fatal("user isn't connected to the hub")
+    }
+
+    cancel()
+
+    // The handler unregisters the client once the stream stops
+    deadline := time.Now().Add(streamTimeout)
+    for server.hub.IsUserConnected(server.user.ID) {
+        if time.Now().After(deadline) {
+            t.Fatal("client still registered after disconnecting")
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+    "github.com/gin-gonic/gin"
+    "github.com/google/uuid"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+)
+
+// ActivityTracker records that a user is active
+type ActivityTracker interface {
+    Touch(userID uuid.UUID)
+}
+
+// Activity middleware records authenticated requests as user activity. It must
+// run after the auth middleware has set the current user.
+func Activity(tracker ActivityTracker) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.Next()
+
+        if user, ok := auth.GetCurrentUser(c); ok {
+            tracker.Touch(user.ID)
+        }
+    }
+}
@@ -25,7 +25,7 @@ func CORS(cfg *config.Config) gin.HandlerFunc {
         c.Header("Access-Control-Max-Age", "86400") // 24 hours
 
         // Handle allowed origins
-        if isAllowedOrigin(origin, cfg.CORS.AllowedOrigins) {
+        if cfg.CORS.AllowsOrigin(origin) {
             c.Header("Access-Control-Allow-Origin", origin)
         } else if len(cfg.CORS.AllowedOrigins) == 1 && cfg.CORS.AllowedOrigins[0] == "*" {
             c.Header("Access-Control-Allow-Origin", "*")
@@ -51,21 +51,3 @@ func CORS(cfg *config.Config) gin.HandlerFunc {
         c.Next()
     })
 }
-
-// isAllowedOrigin checks if the origin is in the allowed origins list
-func isAllowedOrigin(origin string, allowedOrigins []string) bool {
-    for _, allowed := range allowedOrigins {
-        if allowed == "*" || allowed == origin {
-            return true
-        }
-        
-        // Support for wildcard subdomains
-        if strings.HasPrefix(allowed, "*.") {
-            domain := strings.TrimPrefix(allowed, "*.")
-            if strings.HasSuffix(origin, "."+domain) || origin == domain {
-                return true
-            }
-        }
-    }
-    return false
-}
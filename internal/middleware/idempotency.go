@@ -0,0 +1,197 @@
+package middleware
+
+import (
+    "bytes"
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+)
+
+const (
+    // IdempotencyKeyHeader is the request header clients use to make a retry safe
+    IdempotencyKeyHeader = "Idempotency-Key"
+
+    // IdempotencyReplayedHeader is set on responses replayed from a previous request
+    IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+    // idempotencyTTL is how long a key and its response are kept (24 hours)
+    idempotencyTTL = 24 * 60 * 60
+
+    // idempotencyLockTTL bounds how long a key stays locked by a request that never finishes
+    idempotencyLockTTL = 60 * time.Second
+
+    maxIdempotencyKeyLength = 255
+)
+
+// idempotencyRecord is what is stored in Redis for each key
+type idempotencyRecord struct {
+    Fingerprint string `json:"fingerprint"`
+    Completed   bool   `json:"completed"`
+    StatusCode  int    `json:"status_code,omitempty"`
+    ContentType string `json:"content_type,omitempty"`
+    Body        []byte `json:"body,omitempty"`
+}
+
+// idempotencyWriter captures the response so it can be replayed
+type idempotencyWriter struct {
+    gin.ResponseWriter
+    body *bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+    w.body.Write(data)
+    return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+    w.body.WriteString(s)
+    return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes a mutating endpoint safe to retry. Requests carrying an
+// Idempotency-Key header are executed once per user and key; retries get the
+// original response replayed, and reusing a key for a different request is rejected.
+// Requests without the header are passed through unchanged.
+func Idempotency(redisClient *storage.RedisClient) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        key := c.GetHeader(IdempotencyKeyHeader)
+        if key == "" {
+            c.Next()
+            return
+        }
+
+        if len(key) > maxIdempotencyKeyLength {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error":   "invalid_idempotency_key",
+                "message": fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength),
+            })
+            c.Abort()
+            return
+        }
+
+        // Keys are scoped to the caller so users can't collide with each other
+        scope := c.ClientIP()
+        if userID, exists := c.Get("user_id"); exists {
+            scope = fmt.Sprintf("user:%v", userID)
+        }
+        redisKey := fmt.Sprintf("idempotency:%s:%s", scope, key)
+
+        body, err := io.ReadAll(c.Request.Body)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error":   "invalid_request",
+                "message": "Failed to read request body",
+            })
+            c.Abort()
+            return
+        }
+        c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+        fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+        ctx := c.Request.Context()
+
+        // Claim the key; only the first request gets to run the handler
+        lock, err := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+        if err != nil {
+            zap.L().Error("Failed to encode idempotency record", zap.Error(err))
+            c.Next()
+            return
+        }
+
+        claimed, err := redisClient.GetClient().SetNX(ctx, redisKey, lock, idempotencyLockTTL).Result()
+        if err != nil {
+            // Log error but don't block request if Redis is down
+            zap.L().Error("Idempotency check failed", zap.Error(err))
+            c.Next()
+            return
+        }
+
+        if !claimed {
+            var record idempotencyRecord
+            if err := redisClient.Get(ctx, redisKey, &record); err != nil {
+                // The lock expired between the two calls; let the client retry
+                c.JSON(http.StatusConflict, gin.H{
+                    "error":   "request_in_progress",
+                    "message": "A request with this Idempotency-Key is already in progress",
+                })
+                c.Abort()
+                return
+            }
+
+            if record.Fingerprint != fingerprint {
+                c.JSON(http.StatusUnprocessableEntity, gin.H{
+                    "error":   "idempotency_key_reused",
+                    "message": "This Idempotency-Key was already used for a different request",
+                })
+                c.Abort()
+                return
+            }
+
+            if !record.Completed {
+                c.JSON(http.StatusConflict, gin.H{
+                    "error":   "request_in_progress",
+                    "message": "A request with this Idempotency-Key is already in progress",
+                })
+                c.Abort()
+                return
+            }
+
+            // Replay the original response
+            c.Header(IdempotencyReplayedHeader, "true")
+            c.Data(record.StatusCode, record.ContentType, record.Body)
+            c.Abort()
+            return
+        }
+
+        writer := &idempotencyWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+        c.Writer = writer
+
+        c.Next()
+
+        // The client may have gone away, but the result must still be recorded
+        ctx = context.Background()
+
+        // Server errors aren't cached so the client can retry them
+        status := writer.Status()
+        if status >= 500 {
+            redisClient.Delete(ctx, redisKey)
+            return
+        }
+
+        record := idempotencyRecord{
+            Fingerprint: fingerprint,
+            Completed:   true,
+            StatusCode:  status,
+            ContentType: writer.Header().Get("Content-Type"),
+            Body:        writer.body.Bytes(),
+        }
+        if err := redisClient.Set(ctx, redisKey, record, idempotencyTTL); err != nil {
+            zap.L().Error("Failed to store idempotent response",
+                zap.String("key", redisKey),
+                zap.Error(err),
+            )
+            redisClient.Delete(ctx, redisKey)
+        }
+    }
+}
+
+// requestFingerprint identifies a request by its method, path and body
+func requestFingerprint(method, path string, body []byte) string {
+    hash := sha256.New()
+    hash.Write([]byte(method))
+    hash.Write([]byte{0})
+    hash.Write([]byte(path))
+    hash.Write([]byte{0})
+    hash.Write(body)
+    return hex.EncodeToString(hash.Sum(nil))
+}
@@ -3,6 +3,8 @@ package middleware
 import (
     "time"
     "fmt"
+    "net/url"
+    "strings"
     "github.com/gin-gonic/gin"
     "go.uber.org/zap"
 )
@@ -13,7 +15,7 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
         // Use zap for structured logging instead of gin's default formatter
         fields := []zap.Field{
             zap.String("method", param.Method),
-            zap.String("path", param.Path),
+            zap.String("path", redactQuery(param.Path)),
             zap.String("protocol", param.Request.Proto),
             zap.Int("status_code", param.StatusCode),
             zap.Duration("latency", param.Latency),
@@ -45,6 +47,36 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
     })
 }
 
+// redactedParams are query parameters carrying credentials, such as the token
+// or ticket of a realtime connection
+var redactedParams = []string{"token", "ticket"}
+
+// redactQuery hides credential values in a logged path and query
+func redactQuery(path string) string {
+    base, rawQuery, ok := strings.Cut(path, "?")
+    if !ok {
+        return path
+    }
+
+    query, err := url.ParseQuery(rawQuery)
+    if err != nil {
+        return base + "?REDACTED"
+    }
+
+    redacted := false
+    for _, param := range redactedParams {
+        if query.Has(param) {
+            query.Set(param, "REDACTED")
+            redacted = true
+        }
+    }
+    if !redacted {
+        return path
+    }
+
+    return base + "?" + query.Encode()
+}
+
 // RequestID middleware adds a unique request ID to each request
 func RequestID() gin.HandlerFunc {
     return func(c *gin.Context) {
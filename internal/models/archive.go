@@ -0,0 +1,74 @@
+package models
+
+import (
+    "time"
+
+    "github.com/google/uuid"
+
+    "github.com/Abhiro0p/stories-backend/pkg/geohash"
+)
+
+// IsArchived checks if the story expired into its author's archive
+func (s *Story) IsArchived() bool {
+    return s.Status == StoryStatusArchived
+}
+
+// Archive moves an expired story into its author's archive
+func (s *Story) Archive() {
+    now := time.Now()
+    s.Status = StoryStatusArchived
+    s.ArchivedAt = &now
+    s.UpdatedAt = now
+}
+
+// NewRepost creates a fresh published copy of an archived story.
+// Content, segments and location are copied; views, reactions and stickers start over.
+func (s *Story) NewRepost() *Story {
+    now := time.Now()
+    expiresIn := s.ExpiresIn
+    if expiresIn <= 0 {
+        expiresIn = DefaultStoryExpiresIn
+    }
+
+    repost := &Story{
+        ID:              uuid.New(),
+        AuthorID:        s.AuthorID,
+        Type:            s.Type,
+        Text:            s.Text,
+        MediaURL:        s.MediaURL,
+        MediaKey:        s.MediaKey,
+        Visibility:      s.Visibility,
+        Status:          StoryStatusPublished,
+        ExpiresIn:       expiresIn,
+        PublishedAt:     &now,
+        ExpiresAt:       now.Add(time.Duration(expiresIn) * time.Second),
+        CreatedAt:       now,
+        UpdatedAt:       now,
+        PlaceName:       s.PlaceName,
+        Latitude:        s.Latitude,
+        Longitude:       s.Longitude,
+        HideCoordinates: s.HideCoordinates,
+        AllowReactions:  s.AllowReactions,
+        RepliesDisabled: s.RepliesDisabled,
+    }
+    if repost.AllowReactions == "" {
+        repost.AllowReactions = ReactionAudienceEveryone
+    }
+
+    // The geohash isn't cached with the story, so it is encoded again from the
+    // copied coordinates for nearby search
+    if repost.HasCoordinates() {
+        hash := geohash.Encode(*repost.Latitude, *repost.Longitude, LocationGeohashPrecision)
+        repost.Geohash = &hash
+    }
+
+    for _, segment := range s.Segments {
+        copied := *segment
+        copied.ID = uuid.New()
+        copied.StoryID = repost.ID
+        copied.CreatedAt = now
+        repost.Segments = append(repost.Segments, &copied)
+    }
+
+    return repost
+}
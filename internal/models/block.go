@@ -0,0 +1,25 @@
+package models
+
+import (
+    "time"
+
+    "github.com/google/uuid"
+)
+
+// Block represents one user blocking another
+type Block struct {
+    ID        uuid.UUID `json:"id" db:"id"`
+    BlockerID uuid.UUID `json:"blocker_id" db:"blocker_id"`
+    BlockedID uuid.UUID `json:"blocked_id" db:"blocked_id"`
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewBlock creates a new block
+func NewBlock(blockerID, blockedID uuid.UUID) *Block {
+    return &Block{
+        ID:        uuid.New(),
+        BlockerID: blockerID,
+        BlockedID: blockedID,
+        CreatedAt: time.Now(),
+    }
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+    "time"
+
+    "github.com/google/uuid"
+)
+
+// CounterEntity is the kind of record a denormalized counter lives on
+type CounterEntity string
+
+const (
+    CounterEntityStory CounterEntity = "story"
+    CounterEntityUser  CounterEntity = "user"
+)
+
+// CounterCorrection records a counter that had drifted from the rows it counts
+type CounterCorrection struct {
+    Entity   CounterEntity `json:"entity"`
+    EntityID uuid.UUID     `json:"entity_id"`
+    Counter  string        `json:"counter"`
+    OldValue int           `json:"old_value"`
+    NewValue int           `json:"new_value"`
+}
+
+// ReconciliationReport summarizes a counter reconciliation run
+type ReconciliationReport struct {
+    StartedAt   time.Time            `json:"started_at"`
+    Duration    time.Duration        `json:"duration"`
+    Corrections []*CounterCorrection `json:"corrections"`
+}
+
+// NewCounterCorrection creates a correction if the stored value differs from the actual one
+func NewCounterCorrection(entity CounterEntity, entityID uuid.UUID, counter string, oldValue, newValue int) *CounterCorrection {
+    if oldValue == newValue {
+        return nil
+    }
+
+    return &CounterCorrection{
+        Entity:   entity,
+        EntityID: entityID,
+        Counter:  counter,
+        OldValue: oldValue,
+        NewValue: newValue,
+    }
+}
+
+// CountByCounter tallies the corrections by entity and counter, e.g. "story.reaction_count"
+func (r *ReconciliationReport) CountByCounter() map[string]int {
+    counts := make(map[string]int)
+    for _, correction := range r.Corrections {
+        counts[string(correction.Entity)+"."+correction.Counter]++
+    }
+    return counts
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+    "regexp"
+    "strings"
+    "time"
+    "unicode/utf8"
+
+    "github.com/google/uuid"
+)
+
+// EntityType represents the type of entity parsed from story text
+type EntityType string
+
+const (
+    EntityTypeMention EntityType = "mention"
+    EntityTypeHashtag EntityType = "hashtag"
+)
+
+// MaxStoryMentions caps how many users a single story can mention
+const MaxStoryMentions = 20
+
+var (
+    mentionPattern = regexp.MustCompile(`@([a-zA-Z0-9_]{3,30})\b`)
+    hashtagPattern = regexp.MustCompile(`#([\p{L}\p{N}_]{1,100})`)
+    tagPattern     = regexp.MustCompile(`^[\p{L}\p{N}_]{1,100}$`)
+)
+
+// StoryEntity represents a mention or hashtag in story text.
+// Offset and Length are measured in Unicode code points and include the @ or # prefix.
+type StoryEntity struct {
+    ID        uuid.UUID  `json:"id" db:"id"`
+    StoryID   uuid.UUID  `json:"story_id" db:"story_id"`
+    Type      EntityType `json:"type" db:"type"`
+    Value     string     `json:"value" db:"value"` // Lowercased username or tag, without prefix
+    UserID    *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+    Offset    int        `json:"offset" db:"offset"`
+    Length    int        `json:"length" db:"length"`
+    CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ParseStoryEntities extracts mentions and hashtags from story text
+func ParseStoryEntities(storyID uuid.UUID, text string) []*StoryEntity {
+    var entities []*StoryEntity
+    now := time.Now()
+
+    add := func(entityType EntityType, pattern *regexp.Regexp) {
+        for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+            // Skip matches glued to a preceding word, e.g. emails
+            if match[0] > 0 {
+                prev, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+                if isEntityRune(prev) {
+                    continue
+                }
+            }
+
+            entities = append(entities, &StoryEntity{
+                ID:        uuid.New(),
+                StoryID:   storyID,
+                Type:      entityType,
+                Value:     strings.ToLower(text[match[2]:match[3]]),
+                Offset:    utf8.RuneCountInString(text[:match[0]]),
+                Length:    utf8.RuneCountInString(text[match[0]:match[1]]),
+                CreatedAt: now,
+            })
+        }
+    }
+
+    add(EntityTypeMention, mentionPattern)
+    add(EntityTypeHashtag, hashtagPattern)
+
+    return entities
+}
+
+// MentionedUsernames returns the distinct usernames mentioned in the entities
+func MentionedUsernames(entities []*StoryEntity) []string {
+    seen := make(map[string]bool)
+    var usernames []string
+    for _, entity := range entities {
+        if entity.Type == EntityTypeMention && !seen[entity.Value] {
+            seen[entity.Value] = true
+            usernames = append(usernames, entity.Value)
+        }
+    }
+    return usernames
+}
+
+// MentionedUserIDs returns the distinct resolved user IDs mentioned in the entities
+func MentionedUserIDs(entities []*StoryEntity) []uuid.UUID {
+    seen := make(map[uuid.UUID]bool)
+    var userIDs []uuid.UUID
+    for _, entity := range entities {
+        if entity.Type == EntityTypeMention && entity.UserID != nil && !seen[*entity.UserID] {
+            seen[*entity.UserID] = true
+            userIDs = append(userIDs, *entity.UserID)
+        }
+    }
+    return userIDs
+}
+
+// Hashtags returns the distinct tags among the entities
+func Hashtags(entities []*StoryEntity) []string {
+    seen := make(map[string]bool)
+    var tags []string
+    for _, entity := range entities {
+        if entity.Type == EntityTypeHashtag && !seen[entity.Value] {
+            seen[entity.Value] = true
+            tags = append(tags, entity.Value)
+        }
+    }
+    return tags
+}
+
+// NormalizeHashtag lowercases a tag and strips a leading #
+func NormalizeHashtag(tag string) string {
+    return strings.ToLower(strings.TrimPrefix(tag, "#"))
+}
+
+// IsValidHashtag checks if a normalized tag could have been parsed from story text
+func IsValidHashtag(tag string) bool {
+    return tagPattern.MatchString(tag)
+}
+
+// IsMentioned checks if the user is mentioned in the story
+func (s *Story) IsMentioned(userID uuid.UUID) bool {
+    for _, entity := range s.Entities {
+        if entity.Type == EntityTypeMention && entity.UserID != nil && *entity.UserID == userID {
+            return true
+        }
+    }
+    return false
+}
+
+func isEntityRune(r rune) bool {
+    return r == '_' || r == '@' || r == '#' ||
+        ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
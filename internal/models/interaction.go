@@ -0,0 +1,60 @@
+package models
+
+import (
+    "github.com/google/uuid"
+)
+
+// ReactionAudience controls who can react to a story
+type ReactionAudience string
+
+const (
+    ReactionAudienceEveryone  ReactionAudience = "everyone"
+    ReactionAudienceFollowers ReactionAudience = "followers"
+    ReactionAudienceNobody    ReactionAudience = "nobody"
+)
+
+// SetInteractionSettings applies the author's account defaults, overridden by any
+// settings given with the story
+func (s *Story) SetInteractionSettings(author *User, allowReactions *ReactionAudience, repliesDisabled *bool) {
+    s.AllowReactions = author.DefaultAllowReactions
+    s.RepliesDisabled = author.DefaultRepliesDisabled
+
+    if allowReactions != nil {
+        s.AllowReactions = *allowReactions
+    }
+    if repliesDisabled != nil {
+        s.RepliesDisabled = *repliesDisabled
+    }
+
+    // Accounts that never chose a default allow everyone
+    if s.AllowReactions == "" {
+        s.AllowReactions = ReactionAudienceEveryone
+    }
+}
+
+// ReactionsRequireFollow checks if only followers of the author can react
+func (s *Story) ReactionsRequireFollow() bool {
+    return s.AllowReactions == ReactionAudienceFollowers
+}
+
+// CanReact checks if a user can react to this story.
+// isFollower is only consulted when reactions are limited to followers.
+func (s *Story) CanReact(userID uuid.UUID, isFollower bool) bool {
+    if userID == s.AuthorID {
+        return true
+    }
+
+    switch s.AllowReactions {
+    case ReactionAudienceNobody:
+        return false
+    case ReactionAudienceFollowers:
+        return isFollower
+    default:
+        return true
+    }
+}
+
+// AcceptsReplies checks if the author allows private replies to this story
+func (s *Story) AcceptsReplies() bool {
+    return !s.RepliesDisabled
+}
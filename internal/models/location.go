@@ -0,0 +1,110 @@
+package models
+
+import (
+    "math"
+
+    "github.com/google/uuid"
+
+    "github.com/Abhiro0p/stories-backend/pkg/geohash"
+)
+
+// Location precision limits, to avoid exposing exact positions
+const (
+    // LocationDecimals rounds coordinates to about 110 meters
+    LocationDecimals = 3
+    // LocationGeohashPrecision is the stored geohash length (cells of about 150 meters)
+    LocationGeohashPrecision = 7
+    // MaxNearbyRadius is the largest nearby search radius in meters
+    MaxNearbyRadius = 50000
+    // DefaultNearbyRadius is the default nearby search radius in meters
+    DefaultNearbyRadius = 5000
+)
+
+// LocationRequest represents optional location metadata on a story
+type LocationRequest struct {
+    PlaceName       *string  `json:"place_name,omitempty" validate:"omitempty,min=1,max=100"`
+    Latitude        *float64 `json:"latitude,omitempty" validate:"required_with=Longitude,omitempty,min=-90,max=90"`
+    Longitude       *float64 `json:"longitude,omitempty" validate:"required_with=Latitude,omitempty,min=-180,max=180"`
+    HideCoordinates bool     `json:"hide_coordinates,omitempty"`
+}
+
+// NearbyStoriesQuery represents a radius search around a coordinate
+type NearbyStoriesQuery struct {
+    Latitude  float64
+    Longitude float64
+    Radius    float64  // Meters
+    Cells     []string // Geohash cells covering the radius
+}
+
+// SetLocation applies location metadata to the story, rounding coordinates
+func (s *Story) SetLocation(req *LocationRequest) {
+    if req == nil {
+        return
+    }
+
+    s.PlaceName = req.PlaceName
+    s.HideCoordinates = req.HideCoordinates
+
+    if req.Latitude != nil && req.Longitude != nil {
+        latitude := roundCoordinate(*req.Latitude)
+        longitude := roundCoordinate(*req.Longitude)
+        hash := geohash.Encode(latitude, longitude, LocationGeohashPrecision)
+
+        s.Latitude = &latitude
+        s.Longitude = &longitude
+        s.Geohash = &hash
+    }
+}
+
+// HasCoordinates checks if the story carries coordinates
+func (s *Story) HasCoordinates() bool {
+    return s.Latitude != nil && s.Longitude != nil
+}
+
+// ForViewer returns the story as viewerID may see it.
+// Coordinates are stripped for non-authors when the author chose to hide them.
+func (s *Story) ForViewer(viewerID uuid.UUID) *Story {
+    if !s.HideCoordinates || s.AuthorID == viewerID || !s.HasCoordinates() {
+        return s
+    }
+
+    stripped := *s
+    stripped.Latitude = nil
+    stripped.Longitude = nil
+
+    // Exact distances would let viewers triangulate hidden coordinates
+    if s.Distance != nil {
+        distance := math.Ceil(*s.Distance/1000) * 1000
+        stripped.Distance = &distance
+    }
+    return &stripped
+}
+
+// NewNearbyStoriesQuery builds a radius search, clamping the radius and coordinates to the stored precision
+func NewNearbyStoriesQuery(latitude, longitude, radius float64) NearbyStoriesQuery {
+    if radius <= 0 {
+        radius = DefaultNearbyRadius
+    }
+    if radius > MaxNearbyRadius {
+        radius = MaxNearbyRadius
+    }
+
+    latitude = roundCoordinate(latitude)
+    longitude = roundCoordinate(longitude)
+    precision := geohash.PrecisionForRadius(radius, latitude)
+    if precision > LocationGeohashPrecision {
+        precision = LocationGeohashPrecision
+    }
+
+    return NearbyStoriesQuery{
+        Latitude:  latitude,
+        Longitude: longitude,
+        Radius:    radius,
+        Cells:     geohash.CoveringCells(latitude, longitude, precision),
+    }
+}
+
+func roundCoordinate(value float64) float64 {
+    scale := math.Pow(10, LocationDecimals)
+    return math.Round(value*scale) / scale
+}
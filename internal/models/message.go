@@ -0,0 +1,144 @@
+package models
+
+import (
+    "database/sql/driver"
+    "encoding/json"
+    "fmt"
+    "time"
+
+    "github.com/google/uuid"
+)
+
+// Conversation represents a private 1:1 conversation between two users
+type Conversation struct {
+    ID            uuid.UUID `json:"id" db:"id"`
+    UserAID       uuid.UUID `json:"user_a_id" db:"user_a_id"`
+    UserBID       uuid.UUID `json:"user_b_id" db:"user_b_id"`
+    LastMessageAt time.Time `json:"last_message_at" db:"last_message_at"`
+    CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// ConversationWithUser represents a conversation from one participant's point of view
+type ConversationWithUser struct {
+    Conversation
+    OtherUserID         uuid.UUID `json:"other_user_id" db:"other_user_id"`
+    OtherUsername       string    `json:"other_username" db:"other_username"`
+    OtherFullName       *string   `json:"other_full_name,omitempty" db:"other_full_name"`
+    OtherProfilePicture *string   `json:"other_profile_picture,omitempty" db:"other_profile_picture"`
+    OtherIsVerified     bool      `json:"other_is_verified" db:"other_is_verified"`
+    LastMessageText     *string   `json:"last_message_text,omitempty" db:"last_message_text"`
+    UnreadCount         int       `json:"unread_count" db:"unread_count"`
+}
+
+// StoryPreview is a snapshot of a story kept on a reply after the story expires
+type StoryPreview struct {
+    StoryID   uuid.UUID `json:"story_id"`
+    AuthorID  uuid.UUID `json:"author_id"`
+    Type      StoryType `json:"type"`
+    Text      *string   `json:"text,omitempty"`
+    MediaURL  *string   `json:"media_url,omitempty"`
+    ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Value implements driver.Valuer
+func (p *StoryPreview) Value() (driver.Value, error) {
+    if p == nil {
+        return nil, nil
+    }
+    return json.Marshal(p)
+}
+
+// Scan implements sql.Scanner
+func (p *StoryPreview) Scan(value interface{}) error {
+    if value == nil {
+        return nil
+    }
+
+    var data []byte
+    switch v := value.(type) {
+    case []byte:
+        data = v
+    case string:
+        data = []byte(v)
+    default:
+        return fmt.Errorf("cannot scan %T into StoryPreview", value)
+    }
+
+    return json.Unmarshal(data, p)
+}
+
+// Message represents a private message in a conversation
+type Message struct {
+    ID             uuid.UUID     `json:"id" db:"id"`
+    ConversationID uuid.UUID     `json:"conversation_id" db:"conversation_id"`
+    SenderID       uuid.UUID     `json:"sender_id" db:"sender_id"`
+    RecipientID    uuid.UUID     `json:"recipient_id" db:"recipient_id"`
+    Text           string        `json:"text" db:"text"`
+    StoryID        *uuid.UUID    `json:"story_id,omitempty" db:"story_id"`
+    StoryPreview   *StoryPreview `json:"story_preview,omitempty" db:"story_preview"`
+    ReadAt         *time.Time    `json:"read_at,omitempty" db:"read_at"`
+    CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+}
+
+// MessageCreateRequest represents the request to send a message or story reply
+type MessageCreateRequest struct {
+    Text string `json:"text" validate:"required,min=1,max=1000"`
+}
+
+// NewConversation creates a new conversation between two users.
+// Participants are stored in a fixed order so each pair has a single conversation.
+func NewConversation(userID, otherUserID uuid.UUID) *Conversation {
+    userA, userB := userID, otherUserID
+    if userB.String() < userA.String() {
+        userA, userB = userB, userA
+    }
+
+    now := time.Now()
+    return &Conversation{
+        ID:            uuid.New(),
+        UserAID:       userA,
+        UserBID:       userB,
+        LastMessageAt: now,
+        CreatedAt:     now,
+    }
+}
+
+// HasParticipant checks if the user is part of the conversation
+func (c *Conversation) HasParticipant(userID uuid.UUID) bool {
+    return c.UserAID == userID || c.UserBID == userID
+}
+
+// OtherParticipant returns the participant that isn't userID
+func (c *Conversation) OtherParticipant(userID uuid.UUID) uuid.UUID {
+    if c.UserAID == userID {
+        return c.UserBID
+    }
+    return c.UserAID
+}
+
+// NewMessage creates a new message in a conversation
+func NewMessage(conversation *Conversation, senderID uuid.UUID, req MessageCreateRequest) *Message {
+    return &Message{
+        ID:             uuid.New(),
+        ConversationID: conversation.ID,
+        SenderID:       senderID,
+        RecipientID:    conversation.OtherParticipant(senderID),
+        Text:           req.Text,
+        CreatedAt:      time.Now(),
+    }
+}
+
+// NewStoryReply creates a message replying to a story, with a snapshot of the story
+func NewStoryReply(conversation *Conversation, senderID uuid.UUID, story *Story, req MessageCreateRequest) *Message {
+    message := NewMessage(conversation, senderID, req)
+    message.StoryID = &story.ID
+    message.StoryPreview = &StoryPreview{
+        StoryID:   story.ID,
+        AuthorID:  story.AuthorID,
+        Type:      story.Type,
+        Text:      story.Text,
+        MediaURL:  story.MediaURL,
+        ExpiresAt: story.ExpiresAt,
+    }
+    return message
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+    "time"
+
+    "github.com/google/uuid"
+)
+
+// UserPresence represents whether a user is online and when they were last active
+type UserPresence struct {
+    UserID       uuid.UUID  `json:"user_id"`
+    IsOnline     bool       `json:"is_online"`
+    LastActiveAt *time.Time `json:"last_active_at,omitempty"`
+}
@@ -4,9 +4,12 @@ import (
     "time"
 
     "github.com/google/uuid"
+
+    "github.com/Abhiro0p/stories-backend/pkg/emoji"
 )
 
-// ReactionType represents the type of reaction
+// ReactionType represents the type of reaction: a key from the reaction
+// catalogue, or the emoji itself for arbitrary emoji reactions
 type ReactionType string
 
 const (
@@ -20,6 +23,34 @@ const (
     ReactionHundred ReactionType = "hundred"
 )
 
+// ReactionDefinition is an entry in the reaction catalogue
+type ReactionDefinition struct {
+    Type        ReactionType `json:"type" db:"type"`
+    Emoji       string       `json:"emoji" db:"emoji"`
+    DisplayName string       `json:"display_name" db:"display_name"`
+    IsPositive  bool         `json:"is_positive" db:"is_positive"`
+    Position    int          `json:"position" db:"position"`
+    RetiredAt   *time.Time   `json:"retired_at,omitempty" db:"retired_at"`
+    CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+}
+
+// ReactionDefinitionCreateRequest represents the request to add a reaction type to the catalogue
+type ReactionDefinitionCreateRequest struct {
+    Type        ReactionType `json:"type" validate:"required,reaction_key"`
+    Emoji       string       `json:"emoji" validate:"required,emoji"`
+    DisplayName string       `json:"display_name" validate:"required,min=1,max=50"`
+    IsPositive  *bool        `json:"is_positive,omitempty"`
+    Position    int          `json:"position" validate:"min=0"`
+}
+
+// ReactionTypeCount is the number of reactions of one type, with display information
+type ReactionTypeCount struct {
+    Type        ReactionType `json:"type" db:"type"`
+    Emoji       string       `json:"emoji" db:"emoji"`
+    DisplayName *string      `json:"display_name,omitempty" db:"display_name"`
+    Count       int          `json:"count" db:"count"`
+}
+
 // Reaction represents a user's reaction to a story
 type Reaction struct {
     ID        uuid.UUID    `json:"id" db:"id"`
@@ -54,6 +85,7 @@ type ReactionSummary struct {
     StoryID        uuid.UUID                `json:"story_id"`
     TotalReactions int                      `json:"total_reactions"`
     ReactionCounts map[ReactionType]int     `json:"reaction_counts"`
+    Breakdown      []ReactionTypeCount      `json:"breakdown"`
     RecentReactions []ReactionWithUser      `json:"recent_reactions"`
     UserReaction   *ReactionType            `json:"user_reaction,omitempty"`
 }
@@ -89,90 +121,46 @@ func (rw *ReactionWithUser) GetUserInfo() *UserResponse {
     }
 }
 
-// ValidateReactionType validates if the reaction type is valid
-func ValidateReactionType(reactionType string) bool {
-    validTypes := []ReactionType{
-        ReactionLike,
-        ReactionLove,
-        ReactionLaugh,
-        ReactionWow,
-        ReactionSad,
-        ReactionAngry,
-        ReactionFire,
-        ReactionHundred,
-    }
-    
-    for _, valid := range validTypes {
-        if reactionType == string(valid) {
-            return true
-        }
+// NewReactionDefinition creates a new reaction catalogue entry
+func NewReactionDefinition(req ReactionDefinitionCreateRequest) *ReactionDefinition {
+    isPositive := true
+    if req.IsPositive != nil {
+        isPositive = *req.IsPositive
     }
-    return false
-}
 
-// GetReactionEmoji returns the emoji representation of the reaction
-func (rt ReactionType) GetEmoji() string {
-    switch rt {
-    case ReactionLike:
-        return "👍"
-    case ReactionLove:
-        return "❤️"
-    case ReactionLaugh:
-        return "😂"
-    case ReactionWow:
-        return "😮"
-    case ReactionSad:
-        return "😢"
-    case ReactionAngry:
-        return "😡"
-    case ReactionFire:
-        return "🔥"
-    case ReactionHundred:
-        return "💯"
-    default:
-        return "👍"
+    return &ReactionDefinition{
+        Type:        req.Type,
+        Emoji:       req.Emoji,
+        DisplayName: req.DisplayName,
+        IsPositive:  isPositive,
+        Position:    req.Position,
+        CreatedAt:   time.Now(),
     }
 }
 
-// GetReactionDisplayName returns the display name of the reaction
-func (rt ReactionType) GetDisplayName() string {
-    switch rt {
-    case ReactionLike:
-        return "Like"
-    case ReactionLove:
-        return "Love"
-    case ReactionLaugh:
-        return "Laugh"
-    case ReactionWow:
-        return "Wow"
-    case ReactionSad:
-        return "Sad"
-    case ReactionAngry:
-        return "Angry"
-    case ReactionFire:
-        return "Fire"
-    case ReactionHundred:
-        return "100"
-    default:
-        return "Like"
-    }
+// IsRetired checks if the reaction type can no longer be used for new reactions
+func (d *ReactionDefinition) IsRetired() bool {
+    return d.RetiredAt != nil
 }
 
-// IsPositive returns true if the reaction is generally positive
-func (rt ReactionType) IsPositive() bool {
-    positiveReactions := []ReactionType{
-        ReactionLike,
-        ReactionLove,
-        ReactionLaugh,
-        ReactionWow,
-        ReactionFire,
-        ReactionHundred,
-    }
-    
-    for _, positive := range positiveReactions {
-        if rt == positive {
-            return true
+// ResolveReactionType maps a requested reaction to a reaction type using the active catalogue.
+// Catalogue entries match by key or by emoji, so "👍" and "like" count together. Any other
+// emoji is accepted as its own type when allowAnyEmoji is set. Emoji are compared and stored
+// without presentation selectors, so "❤" and "❤️" are the same reaction.
+func ResolveReactionType(value ReactionType, catalogue []*ReactionDefinition, allowAnyEmoji bool) (ReactionType, bool) {
+    normalized := emoji.Normalize(string(value))
+
+    for _, definition := range catalogue {
+        if definition.IsRetired() {
+            continue
+        }
+        if value == definition.Type || normalized == emoji.Normalize(definition.Emoji) {
+            return definition.Type, true
         }
     }
-    return false
+
+    if allowAnyEmoji && emoji.IsEmoji(normalized) {
+        return ReactionType(normalized), true
+    }
+    return "", false
 }
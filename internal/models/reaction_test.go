@@ -0,0 +1,42 @@
+package models
+
+import (
+    "testing"
+    "time"
+)
+
+func TestResolveReactionType(t *testing.T) {
+    retiredAt := time.Now()
+    catalogue := []*ReactionDefinition{
+        {Type: "like", Emoji: "\U0001F44D"},
+        {Type: "love", Emoji: "❤️"},
+        {Type: "star", Emoji: "⭐", RetiredAt: &retiredAt},
+    }
+
+    tests := []struct {
+        name          string
+        value         ReactionType
+        allowAnyEmoji bool
+        want          ReactionType
+        ok            bool
+    }{
+        {name: "key", value: "like", want: "like", ok: true},
+        {name: "emoji", value: "\U0001F44D", want: "like", ok: true},
+        {name: "emoji as catalogued", value: "❤️", want: "love", ok: true},
+        {name: "emoji without selector", value: "❤", want: "love", ok: true},
+        {name: "retired", value: "star", ok: false},
+        {name: "other emoji not allowed", value: "\U0001F525", ok: false},
+        {name: "other emoji", value: "\U0001F525", allowAnyEmoji: true, want: "\U0001F525", ok: true},
+        {name: "other emoji stored without selector", value: "☺️", allowAnyEmoji: true, want: "☺", ok: true},
+        {name: "not an emoji", value: "hello", allowAnyEmoji: true, ok: false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, ok := ResolveReactionType(tt.value, catalogue, tt.allowAnyEmoji)
+            if got != tt.want || ok != tt.ok {
+                t.Errorf("ResolveReactionType(%+q) = %+q, %v, want %+q, %v", tt.value, got, ok, tt.want, tt.ok)
+            }
+        })
+    }
+}
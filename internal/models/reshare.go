@@ -0,0 +1,76 @@
+package models
+
+import (
+    "time"
+
+    "github.com/google/uuid"
+)
+
+// visibilityRank orders visibilities from most to least open
+var visibilityRank = map[StoryVisibility]int{
+    VisibilityPublic:  0,
+    VisibilityFriends: 1,
+    VisibilityPrivate: 2,
+}
+
+// IsReshare checks if the story is a reshare of another story
+func (s *Story) IsReshare() bool {
+    return s.OriginalStoryID != nil
+}
+
+// CanReshare checks if userID may reshare the story. canView is whether the
+// user can see it, which for friends-only stories needs a follow check.
+func (s *Story) CanReshare(userID uuid.UUID, canView bool) bool {
+    if s.IsDraft() || s.IsExpired() || s.AuthorID == userID {
+        return false
+    }
+
+    // Mentioned users may reshare stories they can't otherwise see
+    return canView || s.IsMentioned(userID)
+}
+
+// AllowsReshareVisibility checks that a reshare is no more visible than the original.
+// Private or friends-only originals can't be reshared publicly.
+func (s *Story) AllowsReshareVisibility(visibility StoryVisibility) bool {
+    requested, ok := visibilityRank[visibility]
+    if !ok {
+        return false
+    }
+    return requested >= visibilityRank[s.Visibility]
+}
+
+// NewReshare creates a story that reshares the original with attribution.
+// Reshares of a reshare point at the root story.
+func NewReshare(authorID uuid.UUID, original *Story, req StoryReshareRequest) *Story {
+    now := time.Now()
+
+    originalID := original.ID
+    originalAuthorID := original.AuthorID
+    if original.IsReshare() {
+        originalID = *original.OriginalStoryID
+        originalAuthorID = *original.OriginalAuthorID
+    }
+
+    // A reshare can't outlive the story it points at
+    expiresAt := now.Add(time.Duration(DefaultStoryExpiresIn) * time.Second)
+    if original.ExpiresAt.Before(expiresAt) {
+        expiresAt = original.ExpiresAt
+    }
+
+    return &Story{
+        ID:               uuid.New(),
+        AuthorID:         authorID,
+        Type:             StoryTypeReshare,
+        Text:             req.Text,
+        Visibility:       req.Visibility,
+        Status:           StoryStatusPublished,
+        ExpiresIn:        int(expiresAt.Sub(now).Seconds()),
+        PublishedAt:      &now,
+        ExpiresAt:        expiresAt,
+        CreatedAt:        now,
+        UpdatedAt:        now,
+        OriginalStoryID:  &originalID,
+        OriginalAuthorID: &originalAuthorID,
+        AllowReactions:   ReactionAudienceEveryone,
+    }
+}
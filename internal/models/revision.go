@@ -0,0 +1,57 @@
+package models
+
+import (
+    "time"
+
+    "github.com/google/uuid"
+)
+
+// StoryRevision records one edit of a story's content
+type StoryRevision struct {
+    ID               uuid.UUID       `json:"id" db:"id"`
+    StoryID          uuid.UUID       `json:"story_id" db:"story_id"`
+    Revision         int             `json:"revision" db:"revision"`
+    EditorID         uuid.UUID       `json:"editor_id" db:"editor_id"`
+    EditorUsername   string          `json:"editor_username" db:"editor_username"`
+    TextBefore       *string         `json:"text_before" db:"text_before"`
+    TextAfter        *string         `json:"text_after" db:"text_after"`
+    VisibilityBefore StoryVisibility `json:"visibility_before" db:"visibility_before"`
+    VisibilityAfter  StoryVisibility `json:"visibility_after" db:"visibility_after"`
+    CreatedAt        time.Time       `json:"created_at" db:"created_at"`
+}
+
+// Edit applies an update and returns the revision it made, or nil if the
+// text and visibility didn't change. Interaction settings aren't content,
+// so changing only those doesn't count as an edit.
+func (s *Story) Edit(req StoryUpdateRequest, editorID uuid.UUID) *StoryRevision {
+    textBefore := s.Text
+    visibilityBefore := s.Visibility
+
+    s.Update(req)
+
+    if equalText(textBefore, s.Text) && visibilityBefore == s.Visibility {
+        return nil
+    }
+
+    editedAt := s.UpdatedAt
+    s.EditedAt = &editedAt
+
+    return &StoryRevision{
+        ID:               uuid.New(),
+        StoryID:          s.ID,
+        EditorID:         editorID,
+        TextBefore:       textBefore,
+        TextAfter:        s.Text,
+        VisibilityBefore: visibilityBefore,
+        VisibilityAfter:  s.Visibility,
+        CreatedAt:        editedAt,
+    }
+}
+
+// equalText compares optional texts by value
+func equalText(a, b *string) bool {
+    if a == nil || b == nil {
+        return a == b
+    }
+    return *a == *b
+}
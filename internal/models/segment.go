@@ -0,0 +1,144 @@
+package models
+
+import (
+    "time"
+
+    "github.com/google/uuid"
+)
+
+// MaxStorySegments is the maximum number of frames in a story sequence
+const MaxStorySegments = 10
+
+// DefaultSegmentDuration is the default display time of a segment in seconds
+const DefaultSegmentDuration = 5
+
+// StorySegment represents a single frame of a multi-segment story
+type StorySegment struct {
+    ID        uuid.UUID  `json:"id" db:"id"`
+    StoryID   uuid.UUID  `json:"story_id" db:"story_id"`
+    Position  int        `json:"position" db:"position"`
+    Type      StoryType  `json:"type" db:"type"`
+    Text      *string    `json:"text,omitempty" db:"text"`
+    MediaURL  *string    `json:"media_url,omitempty" db:"media_url"`
+    MediaKey  *uuid.UUID `json:"media_key,omitempty" db:"media_key"`
+    Caption   *string    `json:"caption,omitempty" db:"caption"`
+    Duration  int        `json:"duration" db:"duration"` // Display time in seconds
+    CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// SegmentCreateRequest represents a single segment in a story sequence request
+type SegmentCreateRequest struct {
+    Type     StoryType  `json:"type" validate:"required,oneof=text image video"`
+    Text     *string    `json:"text,omitempty" validate:"omitempty,story_text"`
+    MediaKey *uuid.UUID `json:"media_key,omitempty"`
+    Caption  *string    `json:"caption,omitempty" validate:"omitempty,max=200"`
+    Duration *int       `json:"duration,omitempty" validate:"omitempty,min=1,max=60"`
+}
+
+// SegmentView represents a view of a single segment by a user
+type SegmentView struct {
+    ID        uuid.UUID `json:"id" db:"id"`
+    StoryID   uuid.UUID `json:"story_id" db:"story_id"`
+    SegmentID uuid.UUID `json:"segment_id" db:"segment_id"`
+    ViewerID  uuid.UUID `json:"viewer_id" db:"viewer_id"`
+    ViewedAt  time.Time `json:"viewed_at" db:"viewed_at"`
+}
+
+// SegmentDropOff represents how many viewers reached a segment
+type SegmentDropOff struct {
+    SegmentID uuid.UUID `json:"segment_id" db:"segment_id"`
+    Position  int       `json:"position" db:"position"`
+    Viewers   int       `json:"viewers" db:"viewers"`
+    Retention float64   `json:"retention" db:"-"` // Share of first-segment viewers that reached this segment
+    DropOff   int       `json:"drop_off" db:"-"`  // Viewers lost since the previous segment
+}
+
+// SegmentDropOffReport represents drop-off analytics for a story sequence
+type SegmentDropOffReport struct {
+    StoryID        uuid.UUID         `json:"story_id"`
+    TotalViewers   int               `json:"total_viewers"`
+    CompletionRate float64           `json:"completion_rate"`
+    Segments       []*SegmentDropOff `json:"segments"`
+}
+
+// NewStorySegment creates a new segment for a story at the given position
+func NewStorySegment(storyID uuid.UUID, position int, req SegmentCreateRequest) *StorySegment {
+    duration := DefaultSegmentDuration
+    if req.Duration != nil {
+        duration = *req.Duration
+    }
+
+    return &StorySegment{
+        ID:        uuid.New(),
+        StoryID:   storyID,
+        Position:  position,
+        Type:      req.Type,
+        Text:      req.Text,
+        MediaKey:  req.MediaKey,
+        Caption:   req.Caption,
+        Duration:  duration,
+        CreatedAt: time.Now(),
+    }
+}
+
+// NewSegmentView creates a new segment view
+func NewSegmentView(storyID, segmentID, viewerID uuid.UUID) *SegmentView {
+    return &SegmentView{
+        ID:        uuid.New(),
+        StoryID:   storyID,
+        SegmentID: segmentID,
+        ViewerID:  viewerID,
+        ViewedAt:  time.Now(),
+    }
+}
+
+// NewSegmentDropOffReport builds a drop-off report from per-segment viewer counts ordered by position
+func NewSegmentDropOffReport(storyID uuid.UUID, segments []*SegmentDropOff) *SegmentDropOffReport {
+    report := &SegmentDropOffReport{
+        StoryID:  storyID,
+        Segments: segments,
+    }
+
+    if len(segments) == 0 {
+        return report
+    }
+
+    report.TotalViewers = segments[0].Viewers
+
+    previous := segments[0].Viewers
+    for _, segment := range segments {
+        if report.TotalViewers > 0 {
+            segment.Retention = float64(segment.Viewers) / float64(report.TotalViewers)
+        }
+        if previous > segment.Viewers {
+            segment.DropOff = previous - segment.Viewers
+        }
+        previous = segment.Viewers
+    }
+
+    report.CompletionRate = segments[len(segments)-1].Retention
+
+    return report
+}
+
+// GetSegment finds a segment of the story by ID
+func (s *Story) GetSegment(segmentID uuid.UUID) *StorySegment {
+    for _, segment := range s.Segments {
+        if segment.ID == segmentID {
+            return segment
+        }
+    }
+    return nil
+}
+
+// ValidateSegment checks that a segment carries the content its type requires
+func ValidateSegment(req SegmentCreateRequest) bool {
+    switch req.Type {
+    case StoryTypeText:
+        return req.Text != nil && *req.Text != ""
+    case StoryTypeImage, StoryTypeVideo:
+        return req.MediaKey != nil
+    default:
+        return false
+    }
+}
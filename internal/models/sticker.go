@@ -0,0 +1,207 @@
+package models
+
+import (
+    "database/sql/driver"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/google/uuid"
+)
+
+// StickerType represents the type of interactive sticker
+type StickerType string
+
+const (
+    StickerTypePoll     StickerType = "poll"
+    StickerTypeQuiz     StickerType = "quiz"
+    StickerTypeSlider   StickerType = "slider"
+    StickerTypeQuestion StickerType = "question"
+)
+
+// Sticker limits
+const (
+    MaxStickersPerStory = 5
+    MinStickerOptions   = 2
+    MaxStickerOptions   = 4
+    MinSliderValue      = 0
+    MaxSliderValue      = 100
+)
+
+// StickerOptions is a list of answer options stored as JSON
+type StickerOptions []string
+
+// Value implements driver.Valuer
+func (o StickerOptions) Value() (driver.Value, error) {
+    if o == nil {
+        return nil, nil
+    }
+    return json.Marshal(o)
+}
+
+// Scan implements sql.Scanner
+func (o *StickerOptions) Scan(value interface{}) error {
+    if value == nil {
+        *o = nil
+        return nil
+    }
+
+    var data []byte
+    switch v := value.(type) {
+    case []byte:
+        data = v
+    case string:
+        data = []byte(v)
+    default:
+        return fmt.Errorf("cannot scan %T into StickerOptions", value)
+    }
+
+    return json.Unmarshal(data, o)
+}
+
+// Sticker represents an interactive sticker attached to a story
+type Sticker struct {
+    ID            uuid.UUID      `json:"id" db:"id"`
+    StoryID       uuid.UUID      `json:"story_id" db:"story_id"`
+    Type          StickerType    `json:"type" db:"type"`
+    Question      string         `json:"question" db:"question"`
+    Options       StickerOptions `json:"options,omitempty" db:"options"`
+    CorrectOption *int           `json:"-" db:"correct_option"` // Only revealed after answering
+    Emoji         *string        `json:"emoji,omitempty" db:"emoji"`
+    CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+}
+
+// StickerCreateRequest represents a sticker in a create story request
+type StickerCreateRequest struct {
+    Type          StickerType `json:"type" validate:"required,oneof=poll quiz slider question"`
+    Question      string      `json:"question" validate:"required,min=1,max=200"`
+    Options       []string    `json:"options,omitempty" validate:"omitempty,max=4,dive,min=1,max=50"`
+    CorrectOption *int        `json:"correct_option,omitempty" validate:"omitempty,min=0"`
+    Emoji         *string     `json:"emoji,omitempty" validate:"omitempty,max=16"`
+}
+
+// StickerResponse represents a viewer's response to a sticker
+type StickerResponse struct {
+    ID          uuid.UUID `json:"id" db:"id"`
+    StickerID   uuid.UUID `json:"sticker_id" db:"sticker_id"`
+    StoryID     uuid.UUID `json:"story_id" db:"story_id"`
+    UserID      uuid.UUID `json:"user_id" db:"user_id"`
+    OptionIndex *int      `json:"option_index,omitempty" db:"option_index"`
+    SliderValue *int      `json:"slider_value,omitempty" db:"slider_value"`
+    Text        *string   `json:"text,omitempty" db:"text"`
+    IsCorrect   *bool     `json:"is_correct,omitempty" db:"is_correct"`
+    CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// StickerRespondRequest represents the request to respond to a sticker
+type StickerRespondRequest struct {
+    OptionIndex *int    `json:"option_index,omitempty" validate:"omitempty,min=0,max=3"`
+    SliderValue *int    `json:"slider_value,omitempty" validate:"omitempty,min=0,max=100"`
+    Text        *string `json:"text,omitempty" validate:"omitempty,min=1,max=500"`
+}
+
+// StickerResponseWithUser represents a sticker response with respondent information
+type StickerResponseWithUser struct {
+    StickerResponse
+    Username       string  `json:"username" db:"username"`
+    FullName       *string `json:"full_name,omitempty" db:"full_name"`
+    ProfilePicture *string `json:"profile_picture,omitempty" db:"profile_picture"`
+    IsVerified     bool    `json:"is_verified" db:"is_verified"`
+}
+
+// StickerResults represents aggregate results for a sticker
+type StickerResults struct {
+    StickerID      uuid.UUID                  `json:"sticker_id"`
+    Type           StickerType                `json:"type"`
+    Question       string                     `json:"question"`
+    Options        StickerOptions             `json:"options,omitempty"`
+    CorrectOption  *int                       `json:"correct_option,omitempty"`
+    TotalResponses int                        `json:"total_responses"`
+    OptionCounts   []int                      `json:"option_counts,omitempty"`
+    CorrectCount   *int                       `json:"correct_count,omitempty"`
+    AverageSlider  *float64                   `json:"average_slider,omitempty"`
+    Respondents    []*StickerResponseWithUser `json:"respondents"`
+}
+
+// NewSticker creates a new sticker for a story
+func NewSticker(storyID uuid.UUID, req StickerCreateRequest) *Sticker {
+    sticker := &Sticker{
+        ID:        uuid.New(),
+        StoryID:   storyID,
+        Type:      req.Type,
+        Question:  req.Question,
+        Emoji:     req.Emoji,
+        CreatedAt: time.Now(),
+    }
+
+    if req.Type == StickerTypePoll || req.Type == StickerTypeQuiz {
+        sticker.Options = StickerOptions(req.Options)
+    }
+    if req.Type == StickerTypeQuiz {
+        sticker.CorrectOption = req.CorrectOption
+    }
+
+    return sticker
+}
+
+// ValidateStickerRequest checks that a sticker carries the fields its type requires
+func ValidateStickerRequest(req StickerCreateRequest) error {
+    switch req.Type {
+    case StickerTypePoll, StickerTypeQuiz:
+        if len(req.Options) < MinStickerOptions || len(req.Options) > MaxStickerOptions {
+            return fmt.Errorf("%s stickers need %d to %d options", req.Type, MinStickerOptions, MaxStickerOptions)
+        }
+        if req.Type == StickerTypeQuiz {
+            if req.CorrectOption == nil || *req.CorrectOption >= len(req.Options) {
+                return errors.New("quiz stickers need a valid correct option")
+            }
+        }
+    case StickerTypeSlider:
+        if req.Emoji == nil || *req.Emoji == "" {
+            return errors.New("slider stickers need an emoji")
+        }
+    case StickerTypeQuestion:
+        // Open question only needs the prompt
+    default:
+        return fmt.Errorf("invalid sticker type: %s", req.Type)
+    }
+    return nil
+}
+
+// NewStickerResponse builds a response to the sticker, validating it against the sticker type
+func (s *Sticker) NewStickerResponse(userID uuid.UUID, req StickerRespondRequest) (*StickerResponse, error) {
+    response := &StickerResponse{
+        ID:        uuid.New(),
+        StickerID: s.ID,
+        StoryID:   s.StoryID,
+        UserID:    userID,
+        CreatedAt: time.Now(),
+    }
+
+    switch s.Type {
+    case StickerTypePoll, StickerTypeQuiz:
+        if req.OptionIndex == nil || *req.OptionIndex < 0 || *req.OptionIndex >= len(s.Options) {
+            return nil, errors.New("a valid option_index is required")
+        }
+        response.OptionIndex = req.OptionIndex
+        if s.Type == StickerTypeQuiz && s.CorrectOption != nil {
+            isCorrect := *req.OptionIndex == *s.CorrectOption
+            response.IsCorrect = &isCorrect
+        }
+    case StickerTypeSlider:
+        if req.SliderValue == nil || *req.SliderValue < MinSliderValue || *req.SliderValue > MaxSliderValue {
+            return nil, fmt.Errorf("slider_value must be between %d and %d", MinSliderValue, MaxSliderValue)
+        }
+        response.SliderValue = req.SliderValue
+    case StickerTypeQuestion:
+        if req.Text == nil || *req.Text == "" {
+            return nil, errors.New("text is required")
+        }
+        response.Text = req.Text
+    default:
+        return nil, fmt.Errorf("invalid sticker type: %s", s.Type)
+    }
+
+    return response, nil
+}
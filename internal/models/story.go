@@ -13,6 +13,12 @@ const (
     StoryTypeText  StoryType = "text"
     StoryTypeImage StoryType = "image"
     StoryTypeVideo StoryType = "video"
+
+    // StoryTypeSequence is a story made up of ordered segments
+    StoryTypeSequence StoryType = "sequence"
+    
+    // StoryTypeReshare is a story that shares another user's story
+    StoryTypeReshare StoryType = "reshare"
 )
 
 // StoryVisibility represents who can see the story
@@ -24,6 +30,18 @@ const (
     VisibilityFriends StoryVisibility = "friends"
 )
 
+// StoryStatus represents the publication state of a story
+type StoryStatus string
+
+const (
+    StoryStatusDraft     StoryStatus = "draft"
+    StoryStatusPublished StoryStatus = "published"
+    StoryStatusArchived  StoryStatus = "archived"
+)
+
+// DefaultStoryExpiresIn is the default story lifetime in seconds (24 hours)
+const DefaultStoryExpiresIn = 24 * 60 * 60
+
 // Story represents a story in the system
 type Story struct {
     ID            uuid.UUID       `json:"id" db:"id"`
@@ -35,31 +53,89 @@ type Story struct {
     Visibility    StoryVisibility `json:"visibility" db:"visibility"`
     ViewCount     int             `json:"view_count" db:"view_count"`
     ReactionCount int             `json:"reaction_count" db:"reaction_count"`
+    Status        StoryStatus     `json:"status" db:"status"`
+    ExpiresIn     int             `json:"expires_in" db:"expires_in"`
+    PublishedAt   *time.Time      `json:"published_at,omitempty" db:"published_at"`
+    ShareCount    int             `json:"share_count" db:"share_count"`
     ExpiresAt     time.Time       `json:"expires_at" db:"expires_at"`
     CreatedAt     time.Time       `json:"created_at" db:"created_at"`
     UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
     DeletedAt     *time.Time      `json:"deleted_at,omitempty" db:"deleted_at"`
+    ArchivedAt    *time.Time      `json:"archived_at,omitempty" db:"archived_at"`
+    EditedAt      *time.Time      `json:"edited_at,omitempty" db:"edited_at"`
+    
+    // Distinct viewers; like ViewCount it is flushed from Redis in batches
+    UniqueViewCount int `json:"unique_view_count" db:"unique_view_count"`
+    
+    // Reshare attribution, kept even if the original is deleted
+    OriginalStoryID  *uuid.UUID `json:"original_story_id,omitempty" db:"original_story_id"`
+    OriginalAuthorID *uuid.UUID `json:"original_author_id,omitempty" db:"original_author_id"`
+    
+    // Optional location, rounded to LocationDecimals
+    PlaceName       *string  `json:"place_name,omitempty" db:"place_name"`
+    Latitude        *float64 `json:"latitude,omitempty" db:"latitude"`
+    Longitude       *float64 `json:"longitude,omitempty" db:"longitude"`
+    Geohash         *string  `json:"-" db:"geohash"`
+    HideCoordinates bool     `json:"hide_coordinates,omitempty" db:"hide_coordinates"`
+    
+    // Who may interact with the story
+    AllowReactions  ReactionAudience `json:"allow_reactions" db:"allow_reactions"`
+    RepliesDisabled bool             `json:"replies_disabled" db:"replies_disabled"`
     
     // Additional fields not stored in DB
     Author        *UserResponse   `json:"author,omitempty" db:"-"`
     IsViewed      bool            `json:"is_viewed,omitempty" db:"-"`
     UserReaction  *ReactionType   `json:"user_reaction,omitempty" db:"-"`
     TimeRemaining *time.Duration  `json:"time_remaining,omitempty" db:"-"`
+    Segments      []*StorySegment `json:"segments,omitempty" db:"-"`
+    Stickers      []*Sticker      `json:"stickers,omitempty" db:"-"`
+    Entities      []*StoryEntity  `json:"entities,omitempty" db:"-"`
+    Original      *Story          `json:"original,omitempty" db:"-"`
+    
+    // OriginalUnavailable is set on reshares whose original was deleted or expired
+    OriginalUnavailable bool `json:"original_unavailable,omitempty" db:"-"`
+    
+    // Distance is the distance in meters from a nearby search point
+    Distance *float64 `json:"distance,omitempty" db:"-"`
 }
 
-// StoryCreateRequest represents the request to create a new story
-type StoryCreateRequest struct {
-    Type       StoryType       `json:"type" validate:"required,oneof=text image video"`
+// StoryReshareRequest represents the request to reshare a story
+type StoryReshareRequest struct {
     Text       *string         `json:"text,omitempty" validate:"omitempty,story_text"`
-    MediaKey   *uuid.UUID      `json:"media_key,omitempty"`
     Visibility StoryVisibility `json:"visibility" validate:"required,visibility"`
-    ExpiresIn  *int            `json:"expires_in,omitempty" validate:"omitempty,min=3600,max=604800"` // 1 hour to 7 days in seconds
+}
+
+// StoryCreateRequest represents the request to create a new story
+type StoryCreateRequest struct {
+    Type       StoryType              `json:"type" validate:"required,oneof=text image video sequence"`
+    Text       *string                `json:"text,omitempty" validate:"omitempty,story_text"`
+    MediaKey   *uuid.UUID             `json:"media_key,omitempty"`
+    Visibility StoryVisibility        `json:"visibility" validate:"required,visibility"`
+    ExpiresIn  *int                   `json:"expires_in,omitempty" validate:"omitempty,min=3600,max=604800"` // 1 hour to 7 days in seconds
+    Draft      bool                   `json:"draft,omitempty"`
+    Segments   []SegmentCreateRequest `json:"segments,omitempty" validate:"omitempty,max=10,dive"`
+    Stickers   []StickerCreateRequest `json:"stickers,omitempty" validate:"omitempty,max=5,dive"`
+    Location   *LocationRequest       `json:"location,omitempty"`
+    
+    // Interaction settings default to the author's account settings
+    AllowReactions  *ReactionAudience `json:"allow_reactions,omitempty" validate:"omitempty,oneof=everyone followers nobody"`
+    RepliesDisabled *bool             `json:"replies_disabled,omitempty"`
 }
 
 // StoryUpdateRequest represents the request to update a story
 type StoryUpdateRequest struct {
+    Text            *string           `json:"text,omitempty" validate:"omitempty,story_text"`
+    Visibility      StoryVisibility   `json:"visibility,omitempty" validate:"omitempty,visibility"`
+    AllowReactions  *ReactionAudience `json:"allow_reactions,omitempty" validate:"omitempty,oneof=everyone followers nobody"`
+    RepliesDisabled *bool             `json:"replies_disabled,omitempty"`
+}
+
+// DraftUpdateRequest represents the request to update a draft story
+type DraftUpdateRequest struct {
     Text       *string         `json:"text,omitempty" validate:"omitempty,story_text"`
+    MediaKey   *uuid.UUID      `json:"media_key,omitempty"`
     Visibility StoryVisibility `json:"visibility,omitempty" validate:"omitempty,visibility"`
+    ExpiresIn  *int            `json:"expires_in,omitempty" validate:"omitempty,min=3600,max=604800"`
 }
 
 // StoryWithAuthor represents a story with embedded author information
@@ -77,26 +153,81 @@ func NewStory(authorID uuid.UUID, req StoryCreateRequest) *Story {
     now := time.Now()
     
     // Default expiry is 24 hours
-    expiresIn := 24 * 60 * 60 // 24 hours in seconds
+    expiresIn := DefaultStoryExpiresIn
     if req.ExpiresIn != nil {
         expiresIn = *req.ExpiresIn
     }
     
     story := &Story{
-        ID:         id,
-        AuthorID:   authorID,
-        Type:       req.Type,
-        Text:       req.Text,
-        MediaKey:   req.MediaKey,
-        Visibility: req.Visibility,
-        ExpiresAt:  now.Add(time.Duration(expiresIn) * time.Second),
-        CreatedAt:  now,
-        UpdatedAt:  now,
+        ID:             id,
+        AuthorID:       authorID,
+        Type:           req.Type,
+        Text:           req.Text,
+        MediaKey:       req.MediaKey,
+        Visibility:     req.Visibility,
+        Status:         StoryStatusPublished,
+        ExpiresIn:      expiresIn,
+        PublishedAt:    &now,
+        ExpiresAt:      now.Add(time.Duration(expiresIn) * time.Second),
+        CreatedAt:      now,
+        UpdatedAt:      now,
+        AllowReactions: ReactionAudienceEveryone,
+    }
+    
+    // Sequence stories carry their content in ordered segments
+    for i, segmentReq := range req.Segments {
+        story.Segments = append(story.Segments, NewStorySegment(id, i, segmentReq))
+    }
+    
+    story.SetLocation(req.Location)
+    
+    for _, stickerReq := range req.Stickers {
+        story.Stickers = append(story.Stickers, NewSticker(id, stickerReq))
+    }
+    
+    // Drafts don't start their expiry clock until they are published
+    if req.Draft {
+        story.Status = StoryStatusDraft
+        story.PublishedAt = nil
     }
     
     return story
 }
 
+// UpdateDraft updates draft fields from request
+func (s *Story) UpdateDraft(req DraftUpdateRequest) {
+    if req.Text != nil {
+        s.Text = req.Text
+    }
+    if req.MediaKey != nil {
+        s.MediaKey = req.MediaKey
+    }
+    if req.Visibility != "" {
+        s.Visibility = req.Visibility
+    }
+    if req.ExpiresIn != nil {
+        s.ExpiresIn = *req.ExpiresIn
+    }
+    s.UpdatedAt = time.Now()
+}
+
+// Publish moves a draft to the published state and starts its expiry clock
+func (s *Story) Publish() {
+    now := time.Now()
+    if s.ExpiresIn <= 0 {
+        s.ExpiresIn = DefaultStoryExpiresIn
+    }
+    s.Status = StoryStatusPublished
+    s.PublishedAt = &now
+    s.ExpiresAt = now.Add(time.Duration(s.ExpiresIn) * time.Second)
+    s.UpdatedAt = now
+}
+
+// IsDraft checks if the story has not been published yet
+func (s *Story) IsDraft() bool {
+    return s.Status == StoryStatusDraft
+}
+
 // Update updates story fields from request
 func (s *Story) Update(req StoryUpdateRequest) {
     if req.Text != nil {
@@ -105,16 +236,30 @@ func (s *Story) Update(req StoryUpdateRequest) {
     if req.Visibility != "" {
         s.Visibility = req.Visibility
     }
+    if req.AllowReactions != nil {
+        s.AllowReactions = *req.AllowReactions
+    }
+    if req.RepliesDisabled != nil {
+        s.RepliesDisabled = *req.RepliesDisabled
+    }
     s.UpdatedAt = time.Now()
 }
 
 // IsExpired checks if the story has expired
 func (s *Story) IsExpired() bool {
+    if s.IsDraft() {
+        return false
+    }
     return time.Now().After(s.ExpiresAt)
 }
 
 // CanView checks if a user can view this story
 func (s *Story) CanView(userID *uuid.UUID) bool {
+    // Drafts and archived stories are only visible to their author
+    if s.IsDraft() || s.IsArchived() {
+        return userID != nil && *userID == s.AuthorID
+    }
+    
     // Public stories can be viewed by anyone
     if s.Visibility == VisibilityPublic {
         return true
@@ -167,7 +312,7 @@ func (swa *StoryWithAuthor) GetAuthorInfo() *UserResponse {
 
 // ValidateStoryType validates if the story type is valid
 func ValidateStoryType(storyType string) bool {
-    validTypes := []string{string(StoryTypeText), string(StoryTypeImage), string(StoryTypeVideo)}
+    validTypes := []string{string(StoryTypeText), string(StoryTypeImage), string(StoryTypeVideo), string(StoryTypeSequence)}
     for _, valid := range validTypes {
         if storyType == valid {
             return true
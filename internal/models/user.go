@@ -23,6 +23,7 @@ type User struct {
     IsActive         bool       `json:"is_active" db:"is_active"`
     IsVerified       bool       `json:"is_verified" db:"is_verified"`
     IsAdmin          bool       `json:"is_admin" db:"is_admin"`
+    IsModerator      bool       `json:"is_moderator" db:"is_moderator"`
     FollowerCount    int        `json:"follower_count" db:"follower_count"`
     FollowingCount   int        `json:"following_count" db:"following_count"`
     StoryCount       int        `json:"story_count" db:"story_count"`
@@ -30,8 +31,16 @@ type User struct {
     UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
     DeletedAt        *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
     
+    // Account settings, including defaults for new stories
+    ArchiveDisabled        bool             `json:"archive_disabled" db:"archive_disabled"`
+    DefaultAllowReactions  ReactionAudience `json:"default_allow_reactions" db:"default_allow_reactions"`
+    DefaultRepliesDisabled bool             `json:"default_replies_disabled" db:"default_replies_disabled"`
+    AnonymousPublicViews   bool             `json:"anonymous_public_views" db:"anonymous_public_views"`
+    HideActivityStatus     bool             `json:"hide_activity_status" db:"hide_activity_status"`
+    
     // Additional fields not stored in DB
     IsFollowing      bool       `json:"is_following,omitempty" db:"-"`
+    IsOnline         bool       `json:"is_online,omitempty" db:"-"`
     LastActiveAt     *time.Time `json:"last_active_at,omitempty" db:"-"`
 }
 
@@ -49,23 +58,45 @@ type UserUpdateRequest struct {
     FullName       *string `json:"full_name,omitempty" validate:"omitempty,min=1,max=100"`
     Bio            *string `json:"bio,omitempty" validate:"omitempty,max=500"`
     ProfilePicture *string `json:"profile_picture,omitempty" validate:"omitempty,url,max=255"`
+    ArchiveStories *bool   `json:"archive_stories,omitempty"`
+    
+    // Defaults applied to new stories
+    DefaultAllowReactions  *ReactionAudience `json:"default_allow_reactions,omitempty" validate:"omitempty,oneof=everyone followers nobody"`
+    DefaultRepliesDisabled *bool             `json:"default_replies_disabled,omitempty"`
+    
+    // Views of public stories are counted but not named to the author
+    AnonymousPublicViews *bool `json:"anonymous_public_views,omitempty"`
+    
+    // Online status and last active time are hidden from everyone
+    HideActivityStatus *bool `json:"hide_activity_status,omitempty"`
+}
+
+// UserSettings represents account settings, only returned to the user themselves
+type UserSettings struct {
+    ArchiveStories         bool             `json:"archive_stories"`
+    DefaultAllowReactions  ReactionAudience `json:"default_allow_reactions"`
+    DefaultRepliesDisabled bool             `json:"default_replies_disabled"`
+    AnonymousPublicViews   bool             `json:"anonymous_public_views"`
+    HideActivityStatus     bool             `json:"hide_activity_status"`
 }
 
 // UserResponse represents the user data returned in API responses
 type UserResponse struct {
-    ID             uuid.UUID  `json:"id"`
-    Email          string     `json:"email"`
-    Username       string     `json:"username"`
-    FullName       *string    `json:"full_name"`
-    Bio            *string    `json:"bio"`
-    ProfilePicture *string    `json:"profile_picture"`
-    IsVerified     bool       `json:"is_verified"`
-    FollowerCount  int        `json:"follower_count"`
-    FollowingCount int        `json:"following_count"`
-    StoryCount     int        `json:"story_count"`
-    CreatedAt      time.Time  `json:"created_at"`
-    IsFollowing    bool       `json:"is_following,omitempty"`
-    LastActiveAt   *time.Time `json:"last_active_at,omitempty"`
+    ID             uuid.UUID     `json:"id"`
+    Email          string        `json:"email"`
+    Username       string        `json:"username"`
+    FullName       *string       `json:"full_name"`
+    Bio            *string       `json:"bio"`
+    ProfilePicture *string       `json:"profile_picture"`
+    IsVerified     bool          `json:"is_verified"`
+    FollowerCount  int           `json:"follower_count"`
+    FollowingCount int           `json:"following_count"`
+    StoryCount     int           `json:"story_count"`
+    CreatedAt      time.Time     `json:"created_at"`
+    IsFollowing    bool          `json:"is_following,omitempty"`
+    IsOnline       bool          `json:"is_online,omitempty"`
+    LastActiveAt   *time.Time    `json:"last_active_at,omitempty"`
+    Settings       *UserSettings `json:"settings,omitempty"`
 }
 
 // UserStats represents user statistics
@@ -98,6 +129,8 @@ func NewUser(email, username, password, fullName string) (*User, error) {
         IsAdmin:      false,
         CreatedAt:    now,
         UpdatedAt:    now,
+        
+        DefaultAllowReactions: ReactionAudienceEveryone,
     }, nil
 }
 
@@ -118,6 +151,11 @@ func (u *User) UpdatePassword(password string) error {
     return nil
 }
 
+// CanModerate checks if the user may review other users' content
+func (u *User) CanModerate() bool {
+    return u.IsAdmin || u.IsModerator
+}
+
 // Update updates user fields from request
 func (u *User) Update(req UserUpdateRequest) {
     if req.Username != nil {
@@ -132,6 +170,24 @@ func (u *User) Update(req UserUpdateRequest) {
     if req.ProfilePicture != nil {
         u.ProfilePicture = req.ProfilePicture
     }
+    if req.ArchiveStories != nil {
+        u.ArchiveDisabled = !*req.ArchiveStories
+    }
+    if req.DefaultAllowReactions != nil {
+        u.DefaultAllowReactions = *req.DefaultAllowReactions
+    }
+    if req.DefaultRepliesDisabled != nil {
+        u.DefaultRepliesDisabled = *req.DefaultRepliesDisabled
+    }
+    if req.AnonymousPublicViews != nil {
+        u.AnonymousPublicViews = *req.AnonymousPublicViews
+    }
+    if req.HideActivityStatus != nil {
+        u.HideActivityStatus = *req.HideActivityStatus
+    }
+    if u.DefaultAllowReactions == "" {
+        u.DefaultAllowReactions = ReactionAudienceEveryone
+    }
     u.UpdatedAt = time.Now()
 }
 
@@ -150,10 +206,27 @@ func (u *User) ToResponse() *UserResponse {
         StoryCount:     u.StoryCount,
         CreatedAt:      u.CreatedAt,
         IsFollowing:    u.IsFollowing,
+        IsOnline:       u.IsOnline,
         LastActiveAt:   u.LastActiveAt,
     }
 }
 
+// ToPrivateResponse converts User to UserResponse including the user's own settings
+func (u *User) ToPrivateResponse() *UserResponse {
+    response := u.ToResponse()
+    response.Settings = &UserSettings{
+        ArchiveStories:         !u.ArchiveDisabled,
+        DefaultAllowReactions:  u.DefaultAllowReactions,
+        DefaultRepliesDisabled: u.DefaultRepliesDisabled,
+        AnonymousPublicViews:   u.AnonymousPublicViews,
+        HideActivityStatus:     u.HideActivityStatus,
+    }
+    if response.Settings.DefaultAllowReactions == "" {
+        response.Settings.DefaultAllowReactions = ReactionAudienceEveryone
+    }
+    return response
+}
+
 // hashPassword creates a hash of the password using Argon2
 func hashPassword(password string) (string, error) {
     // Generate a random salt
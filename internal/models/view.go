@@ -14,8 +14,14 @@ type StoryView struct {
     StoryID   uuid.UUID  `json:"story_id" db:"story_id"`
     ViewerID  uuid.UUID  `json:"viewer_id" db:"viewer_id"`
     ViewedAt  time.Time  `json:"viewed_at" db:"viewed_at"`
-    IPAddress *net.IP    `json:"ip_address,omitempty" db:"ip_address"`
-    UserAgent *string    `json:"user_agent,omitempty" db:"user_agent"`
+    
+    // IsAnonymous hides the viewer from the author. It is fixed when the view is
+    // recorded, so later setting or visibility changes don't reveal the viewer.
+    IsAnonymous bool `json:"-" db:"is_anonymous"`
+    
+    // Request metadata is kept for abuse investigation only and never returned by the API
+    IPAddress *net.IP    `json:"-" db:"ip_address"`
+    UserAgent *string    `json:"-" db:"user_agent"`
 }
 
 // StoryViewWithUser represents a story view with user information
@@ -27,6 +33,26 @@ type StoryViewWithUser struct {
     ViewerIsVerified     bool    `json:"viewer_is_verified" db:"viewer_is_verified"`
 }
 
+// SeenByViewer is a named viewer in a "seen by" summary
+type SeenByViewer struct {
+    ViewerID       uuid.UUID `json:"viewer_id" db:"viewer_id"`
+    Username       string    `json:"username" db:"username"`
+    FullName       *string   `json:"full_name" db:"full_name"`
+    ProfilePicture *string   `json:"profile_picture" db:"profile_picture"`
+    IsVerified     bool      `json:"is_verified" db:"is_verified"`
+    IsMutual       bool      `json:"is_mutual" db:"is_mutual"`
+    ViewedAt       time.Time `json:"viewed_at" db:"viewed_at"`
+}
+
+// SeenBySummary is a compact summary of a story's viewers: the total and
+// the first few viewers, mutual follows first
+type SeenBySummary struct {
+    StoryID     uuid.UUID       `json:"story_id"`
+    TotalViews  int             `json:"total_views"`
+    Viewers     []*SeenByViewer `json:"viewers"`
+    OthersCount int             `json:"others_count"`
+}
+
 // StoryViewStats represents viewing statistics for a story
 type StoryViewStats struct {
     StoryID      uuid.UUID `json:"story_id" db:"story_id"`
@@ -69,13 +95,57 @@ type ViewerSummary struct {
     LastViewedAt time.Time `json:"last_viewed_at"`
 }
 
-// NewStoryView creates a new story view
-func NewStoryView(storyID, viewerID uuid.UUID, ipAddress *string, userAgent *string) *StoryView {
+// "Seen by" summary limits
+const (
+    DefaultSeenByViewers = 3
+    MaxSeenByViewers     = 10
+)
+
+// NewSeenBySummary builds a "seen by" summary from the total view count and the named viewers
+func NewSeenBySummary(storyID uuid.UUID, totalViews int, viewers []*SeenByViewer) *SeenBySummary {
+    if viewers == nil {
+        viewers = []*SeenByViewer{}
+    }
+
+    othersCount := totalViews - len(viewers)
+    if othersCount < 0 {
+        othersCount = 0
+    }
+
+    return &SeenBySummary{
+        StoryID:     storyID,
+        TotalViews:  totalViews,
+        Viewers:     viewers,
+        OthersCount: othersCount,
+    }
+}
+
+// ViewCountDelta is a batch of views of one story waiting to be added to its counters
+type ViewCountDelta struct {
+    StoryID     uuid.UUID `json:"story_id"`
+    TotalViews  int64     `json:"total_views"`
+    UniqueViews int64     `json:"unique_views"`
+}
+
+// IsEmpty checks if the delta has nothing to add
+func (d *ViewCountDelta) IsEmpty() bool {
+    return d.TotalViews == 0 && d.UniqueViews == 0
+}
+
+// IsAnonymousViewer checks if the viewer's views of this story are hidden from
+// its author, as they are for public stories when the viewer chose anonymous views
+func (s *Story) IsAnonymousViewer(viewer *User) bool {
+    return s.Visibility == VisibilityPublic && viewer.AnonymousPublicViews
+}
+
+// NewStoryView creates a new view of a story by viewer
+func NewStoryView(story *Story, viewer *User, ipAddress *string, userAgent *string) *StoryView {
     view := &StoryView{
-        ID:       uuid.New(),
-        StoryID:  storyID,
-        ViewerID: viewerID,
-        ViewedAt: time.Now(),
+        ID:          uuid.New(),
+        StoryID:     story.ID,
+        ViewerID:    viewer.ID,
+        ViewedAt:    time.Now(),
+        IsAnonymous: story.IsAnonymousViewer(viewer),
     }
     
     // Parse IP address if provided
@@ -1,13 +1,16 @@
 package realtime
 
 import (
-    "encoding/json"
+    "context"
+    "sync"
     "time"
     
+    "github.com/google/uuid"
     "github.com/gorilla/websocket"
     "go.uber.org/zap"
 
     "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
 )
 
 const (
@@ -22,25 +25,88 @@ const (
 
     // Maximum message size allowed from peer
     maxMessageSize = 512
+
+    // Time allowed to authorize a topic subscription
+    topicAuthorizeTimeout = 5 * time.Second
+
+    // Frames smaller than this aren't worth compressing
+    compressionThreshold = 256
+)
+
+// Transports a client can receive events over
+const (
+    TransportWebSocket = "websocket"
+    TransportSSE       = "sse"
 )
 
 // Client is a middleman between the websocket connection and the hub
 type Client struct {
-    hub    *Hub
-    conn   *websocket.Conn
-    send   chan []byte
-    User   *models.User
-    logger *zap.Logger
+    hub       *Hub
+    conn      *websocket.Conn
+    send      chan []byte
+    transport string
+    encoding  Encoding
+    User      *models.User
+    logger    *zap.Logger
+
+    // Access token the connection was opened with, checked again periodically
+    token string
+
+    // Conversations recently authorized for typing events, only used from ReadPump
+    typingGrants map[uuid.UUID]typingGrant
+
+    // Where the connection came from, recorded with story views
+    ipAddress string
+    userAgent string
+
+    // Low-priority messages waiting to be written, and whether send is closed
+    queueMu  sync.Mutex
+    low      []queuedMessage
+    lowReady chan struct{}
+    closed   bool
+
+    // Closed to disconnect a client that can't keep up or misbehaves
+    done        chan struct{}
+    doneOnce    sync.Once
+    evictReason string
+
+    // Inbound rate limit, only used from ReadPump
+    limiter        *tokenBucket
+    rateViolations int
+
+    // Users this client follows, resolved when it registers
+    following []uuid.UUID
+
+    // Topics this client is subscribed to, owned by the hub
+    topics map[Topic]bool
+
+    // Events held back while missed events are replayed to a resuming client
+    resumeMu sync.Mutex
+    resuming bool
+    held     []*Event
 }
 
 // NewClient creates a new WebSocket client
-func NewClient(hub *Hub, conn *websocket.Conn, user *models.User, logger *zap.Logger) *Client {
+func NewClient(hub *Hub, conn *websocket.Conn, user *models.User, token string, logger *zap.Logger) *Client {
+    // The encoding follows the subprotocol agreed during the upgrade
+    subprotocol := ""
+    if conn != nil {
+        subprotocol = conn.Subprotocol()
+    }
+
     return &Client{
-        hub:    hub,
-        conn:   conn,
-        send:   make(chan []byte, 256),
-        User:   user,
-        logger: logger.With(zap.String("component", "websocket_client")),
+        hub:       hub,
+        conn:      conn,
+        send:      make(chan []byte, hub.sendBuffer),
+        transport: TransportWebSocket,
+        encoding:  EncodingFor(subprotocol),
+        User:      user,
+        logger:    logger.With(zap.String("component", "websocket_client")),
+        token:     token,
+        lowReady:  make(chan struct{}, 1),
+        done:      make(chan struct{}),
+        limiter:   newTokenBucket(hub.inboundRate, hub.inboundBurst),
+        topics:    make(map[Topic]bool),
     }
 }
 
@@ -70,11 +136,37 @@ func (c *Client) ReadPump() {
             break
         }
 
+        if !c.limiter.allow() {
+            c.rateLimited()
+            continue
+        }
+        c.rateViolations = 0
+
         // Handle incoming message
         c.handleMessage(message)
     }
 }
 
+// rateLimited drops a message sent over the inbound rate. The client is told
+// once per burst of violations, and disconnected if it keeps going.
+func (c *Client) rateLimited() {
+    c.hub.recordInboundLimited()
+    c.rateViolations++
+
+    if c.rateViolations == 1 {
+        c.Send(&Event{
+            Type: EventError,
+            Payload: map[string]interface{}{
+                "error":   "rate_limited",
+                "message": "Too many messages, some were dropped",
+            },
+        })
+    }
+    if c.rateViolations >= maxRateViolations {
+        c.evict(evictInboundRate)
+    }
+}
+
 // WritePump pumps messages from the hub to the websocket connection
 func (c *Client) WritePump() {
     ticker := time.NewTicker(pingPeriod)
@@ -93,23 +185,23 @@ func (c *Client) WritePump() {
                 return
             }
 
-            w, err := c.conn.NextWriter(websocket.TextMessage)
-            if err != nil {
+            if err := c.writeFrame(c.drain(message)); err != nil {
                 return
             }
-            w.Write(message)
 
-            // Add queued messages to the current websocket message
-            n := len(c.send)
-            for i := 0; i < n; i++ {
-                w.Write([]byte{'\n'})
-                w.Write(<-c.send)
-            }
-
-            if err := w.Close(); err != nil {
+        case <-c.lowReady:
+            c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+            if err := c.writeFrame(c.drain(nil)); err != nil {
                 return
             }
 
+        case <-c.done:
+            c.conn.WriteControl(websocket.CloseMessage,
+                websocket.FormatCloseMessage(websocket.ClosePolicyViolation, c.evictReason),
+                time.Now().Add(writeWait),
+            )
+            return
+
         case <-ticker.C:
             c.conn.SetWriteDeadline(time.Now().Add(writeWait))
             if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
@@ -119,16 +211,65 @@ func (c *Client) WritePump() {
     }
 }
 
+// writeFrame writes messages to the connection. JSON messages are batched into
+// one text frame separated by newlines; binary encodings send one frame each.
+func (c *Client) writeFrame(messages [][]byte) error {
+    if len(messages) == 0 {
+        return nil
+    }
+
+    if c.encoding.MessageType() == websocket.BinaryMessage {
+        for _, message := range messages {
+            c.conn.EnableWriteCompression(len(message) >= compressionThreshold)
+            if err := c.conn.WriteMessage(websocket.BinaryMessage, message); err != nil {
+                return err
+            }
+        }
+        return nil
+    }
+
+    size := len(messages) - 1
+    for _, message := range messages {
+        size += len(message)
+    }
+    c.conn.EnableWriteCompression(size >= compressionThreshold)
+
+    w, err := c.conn.NextWriter(websocket.TextMessage)
+    if err != nil {
+        return err
+    }
+    for i, message := range messages {
+        if i > 0 {
+            w.Write([]byte{'\n'})
+        }
+        w.Write(message)
+    }
+    return w.Close()
+}
+
 // Send sends an event to the client
 func (c *Client) Send(event *Event) {
+    c.resumeMu.Lock()
+    if c.resuming {
+        c.held = append(c.held, event)
+        c.resumeMu.Unlock()
+        return
+    }
+    c.resumeMu.Unlock()
+
+    c.write(event)
+}
+
+// write queues an event on the client's connection
+func (c *Client) write(event *Event) {
     // Add timestamp if not set
     if event.Timestamp == 0 {
         event.Timestamp = time.Now().Unix()
     }
     
-    data, err := json.Marshal(event)
+    data, err := c.encoding.Encode(event)
     if err != nil {
-        c.logger.Error("Failed to marshal event",
+        c.logger.Error("Failed to encode event",
             zap.String("user_id", c.User.ID.String()),
             zap.String("event_type", string(event.Type)),
             zap.Error(err),
@@ -136,18 +277,183 @@ func (c *Client) Send(event *Event) {
         return
     }
 
+    if event.Priority() == PriorityLow {
+        c.queueLow(event, data)
+        return
+    }
+    c.queue(data)
+}
+
+// writeRaw queues a raw JSON message, converted to the client's encoding
+func (c *Client) writeRaw(message []byte) {
+    data, err := c.encoding.FromJSON(message)
+    if err != nil {
+        c.logger.Error("Failed to encode raw message",
+            zap.String("user_id", c.User.ID.String()),
+            zap.Error(err),
+        )
+        return
+    }
+    c.queue(data)
+}
+
+// queue adds a message to the client's send buffer. A client whose buffer is
+// full can't keep up, so it is disconnected rather than silently missing events.
+func (c *Client) queue(data []byte) {
+    c.queueMu.Lock()
+    defer c.queueMu.Unlock()
+
+    if c.closed {
+        return
+    }
+
     select {
     case c.send <- data:
     default:
+        c.hub.recordDropped(PriorityHigh, evictSendBufferFull)
+        c.evict(evictSendBufferFull)
+    }
+}
+
+// queueLow adds a low-priority message, replacing a queued one it supersedes.
+// When the low-priority buffer is full the message is dropped.
+func (c *Client) queueLow(event *Event, data []byte) {
+    c.queueMu.Lock()
+    defer c.queueMu.Unlock()
+
+    if c.closed {
+        return
+    }
+
+    key := event.coalesceKey()
+    if key != "" {
+        for i := range c.low {
+            if c.low[i].key == key {
+                c.low[i].data = data
+                c.hub.recordCoalesced(event.Type)
+                return
+            }
+        }
+    }
+
+    if len(c.low) >= c.hub.lowPriorityBuffer {
+        c.hub.recordDropped(PriorityLow, dropLowBufferFull)
+        c.logger.Debug("Dropped low priority event",
+            zap.String("user_id", c.User.ID.String()),
+            zap.String("event_type", string(event.Type)),
+        )
+        return
+    }
+    c.low = append(c.low, queuedMessage{key: key, data: data})
+
+    select {
+    case c.lowReady <- struct{}{}:
+    default:
+    }
+}
+
+// drain collects the messages ready to be written: first, then everything in
+// the send buffer, then the low-priority queue
+func (c *Client) drain(first []byte) [][]byte {
+    var messages [][]byte
+    if first != nil {
+        messages = append(messages, first)
+    }
+
+    for n := len(c.send); n > 0; n-- {
+        message, ok := <-c.send
+        if !ok {
+            break
+        }
+        messages = append(messages, message)
+    }
+
+    c.queueMu.Lock()
+    for _, message := range c.low {
+        messages = append(messages, message.data)
+    }
+    c.low = nil
+    c.queueMu.Unlock()
+
+    return messages
+}
+
+// closeSend closes the send buffer once; later messages are discarded. Called
+// by the hub when the client is unregistered.
+func (c *Client) closeSend() {
+    c.queueMu.Lock()
+    defer c.queueMu.Unlock()
+
+    if !c.closed {
+        c.closed = true
         close(c.send)
-        delete(c.hub.clients, c)
     }
 }
 
+// evict disconnects the client. Its writer closes the connection, after which
+// it is unregistered the same way as any other disconnect.
+func (c *Client) evict(reason string) {
+    c.doneOnce.Do(func() {
+        c.evictReason = reason
+        close(c.done)
+
+        c.hub.recordEvicted(reason)
+        c.logger.Warn("Disconnecting realtime client",
+            zap.String("user_id", c.User.ID.String()),
+            zap.String("transport", c.transport),
+            zap.String("reason", reason),
+        )
+    })
+}
+
+// holdEvents makes Send hold events back until releaseEvents is called
+func (c *Client) holdEvents() {
+    c.resumeMu.Lock()
+    defer c.resumeMu.Unlock()
+
+    c.resuming = true
+}
+
+// releaseEvents sends the replayed events, or the resync event, followed by
+// the events held back meanwhile, skipping any that were already replayed.
+// Nothing drains the send buffer until the writer starts, so a replay that
+// doesn't fit in it is replaced by a resync rather than evicting the client.
+func (c *Client) releaseEvents(lastSeq int64, replayed []*Event, resync *Event) {
+    c.resumeMu.Lock()
+    defer c.resumeMu.Unlock()
+
+    if room := cap(c.send) - len(c.send); resync == nil && len(replayed)+len(c.held) > room {
+        c.logger.Info("Replay doesn't fit in send buffer, sending resync",
+            zap.String("user_id", c.User.ID.String()),
+            zap.Int("event_count", len(replayed)),
+            zap.Int("room", room),
+        )
+        replayed = nil
+        resync = ResyncRequiredEvent(lastSeq)
+    }
+
+    if resync != nil {
+        c.write(resync)
+    }
+    for _, event := range replayed {
+        c.write(event)
+        lastSeq = event.Seq
+    }
+    for _, event := range c.held {
+        if event.Seq != 0 && event.Seq <= lastSeq {
+            continue
+        }
+        c.write(event)
+    }
+
+    c.held = nil
+    c.resuming = false
+}
+
 // handleMessage handles incoming messages from the client
 func (c *Client) handleMessage(message []byte) {
     var incomingEvent Event
-    if err := json.Unmarshal(message, &incomingEvent); err != nil {
+    if err := c.encoding.Decode(message, &incomingEvent); err != nil {
         c.logger.Warn("Failed to unmarshal incoming message",
             zap.String("user_id", c.User.ID.String()),
             zap.Error(err),
@@ -178,6 +484,10 @@ func (c *Client) handleMessage(message []byte) {
         c.handleStoryView(incomingEvent.Payload)
     case EventTyping:
         c.handleTyping(incomingEvent.Payload)
+    case EventSubscribe:
+        c.handleSubscription(incomingEvent.Payload, true)
+    case EventUnsubscribe:
+        c.handleSubscription(incomingEvent.Payload, false)
     default:
         c.logger.Warn("Unknown event type received",
             zap.String("user_id", c.User.ID.String()),
@@ -207,25 +517,81 @@ func (c *Client) handlePing() {
     c.Send(pongEvent)
 }
 
-// handleStoryView handles story view events
+// handleStoryView records a story view the same way the REST API does
 func (c *Client) handleStoryView(payload map[string]interface{}) {
-    storyID, ok := payload["story_id"].(string)
-    if !ok {
-        c.logger.Warn("Invalid story_id in story view event",
+    value, _ := payload["story_id"].(string)
+    storyID, err := uuid.Parse(value)
+    if err != nil {
+        c.Send(ErrorEvent("invalid_id", "Invalid story ID"))
+        return
+    }
+
+    if c.hub.live == nil {
+        return
+    }
+
+    if err := c.hub.live.recordView(c, storyID); err != nil {
+        if err == storage.ErrNotFound {
+            c.Send(ErrorEvent("not_found", "Story not found"))
+            return
+        }
+        if err == ErrViewForbidden {
+            c.Send(ErrorEvent("forbidden", "You don't have permission to view this story"))
+            return
+        }
+
+        c.logger.Error("Failed to record story view",
             zap.String("user_id", c.User.ID.String()),
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
         )
+        c.Send(ErrorEvent("view_failed", "Failed to record story view"))
+    }
+}
+
+// handleSubscription handles subscribe and unsubscribe requests. Subscriptions
+// are authorized here so the database lookups don't hold up the hub.
+func (c *Client) handleSubscription(payload map[string]interface{}, subscribe bool) {
+    value, _ := payload["topic"].(string)
+    topic, err := ParseTopic(value)
+    if err != nil {
+        c.Send(subscriptionErrorEvent(Topic(value), "invalid_topic", "Invalid topic: "+value))
         return
     }
 
-    c.logger.Info("Story view event received",
-        zap.String("user_id", c.User.ID.String()),
-        zap.String("story_id", storyID),
-    )
+    if subscribe && c.hub.topics != nil {
+        ctx, cancel := context.WithTimeout(context.Background(), topicAuthorizeTimeout)
+        defer cancel()
+
+        if err := c.hub.topics.AuthorizeTopic(ctx, c.User, topic); err != nil {
+            switch err {
+            case ErrTopicNotFound:
+                c.Send(subscriptionErrorEvent(topic, "not_found", "Topic not found"))
+            case ErrTopicForbidden:
+                c.Send(subscriptionErrorEvent(topic, "forbidden", "You don't have permission to subscribe to this topic"))
+            case ErrInvalidTopic:
+                c.Send(subscriptionErrorEvent(topic, "invalid_topic", "Invalid topic: "+value))
+            default:
+                c.logger.Error("Failed to authorize topic subscription",
+                    zap.String("user_id", c.User.ID.String()),
+                    zap.String("topic", string(topic)),
+                    zap.Error(err),
+                )
+                c.Send(subscriptionErrorEvent(topic, "subscribe_failed", "Failed to subscribe to topic"))
+            }
+            return
+        }
+    }
 
-    // TODO: Process story view (update database, send notifications, etc.)
+    c.hub.subscriptions <- &Subscription{
+        Client:    c,
+        Topic:     topic,
+        Subscribe: subscribe,
+    }
 }
 
-// handleTyping handles typing indicator events
+// handleTyping sends a typing indicator to the other participant of one of the
+// user's conversations
 func (c *Client) handleTyping(payload map[string]interface{}) {
     isTyping, ok := payload["is_typing"].(bool)
     if !ok {
@@ -235,27 +601,71 @@ func (c *Client) handleTyping(payload map[string]interface{}) {
         return
     }
 
+    value, _ := payload["conversation_id"].(string)
+    conversationID, err := uuid.Parse(value)
+    if err != nil {
+        c.Send(ErrorEvent("invalid_id", "Invalid conversation ID"))
+        return
+    }
+
+    recipientID, err := c.typingRecipient(conversationID)
+    if err != nil {
+        switch err {
+        case ErrConversationNotFound:
+            c.Send(ErrorEvent("not_found", "Conversation not found"))
+        case ErrTypingForbidden:
+            c.Send(ErrorEvent("forbidden", "You can't send typing events to this conversation"))
+        default:
+            c.logger.Error("Failed to authorize typing event",
+                zap.String("user_id", c.User.ID.String()),
+                zap.String("conversation_id", conversationID.String()),
+                zap.Error(err),
+            )
+        }
+        return
+    }
+
+    // A recipient_id, if given, has to be the other participant
+    if recipient, ok := payload["recipient_id"].(string); ok && recipient != recipientID.String() {
+        c.Send(ErrorEvent("forbidden", "You can't send typing events to this conversation"))
+        return
+    }
+
     c.logger.Debug("Typing event received",
         zap.String("user_id", c.User.ID.String()),
+        zap.String("conversation_id", conversationID.String()),
         zap.Bool("is_typing", isTyping),
     )
 
-    // Broadcast typing indicator to relevant users
     typingEvent := &Event{
         Type: EventTyping,
         Payload: map[string]interface{}{
-            "user":      c.User.ToResponse(),
-            "is_typing": isTyping,
+            "user":            c.User.ToResponse(),
+            "user_id":         c.User.ID,
+            "conversation_id": conversationID,
+            "is_typing":       isTyping,
         },
     }
-    
-    // TODO: Broadcast to specific users (followers, friends, etc.)
-    c.hub.BroadcastEvent(typingEvent)
+    c.hub.SendToUser(recipientID, typingEvent)
 }
 
-// Close closes the client connection
+// Close closes the client connection. SSE streams end when their request does.
 func (c *Client) Close() {
-    c.conn.Close()
+    if c.conn != nil {
+        c.conn.Close()
+    }
+}
+
+// SetRequestInfo records the address and user agent of the request that opened
+// the connection
+func (c *Client) SetRequestInfo(ipAddress, userAgent string) {
+    c.ipAddress = ipAddress
+    c.userAgent = userAgent
+}
+
+// Transport returns how the client receives events
+func (c *Client) Transport() string {
+    return c.transport
 }
 
 // IsConnected checks if the client is still connected
@@ -0,0 +1,427 @@
+package realtime
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+    "strconv"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/redis/go-redis/v9"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+const (
+    // clusterOutboxSize bounds the messages waiting to be published
+    clusterOutboxSize = 1024
+
+    // presenceQueueSize bounds the presence changes waiting to be written
+    presenceQueueSize = 1024
+)
+
+// Reasons a message isn't relayed to the other instances
+const (
+    relayDropOutboxFull    = "outbox_full"
+    relayDropPublishFailed = "publish_failed"
+    relayDropMarshalFailed = "marshal_failed"
+)
+
+// clusterMessageKind identifies which hub operation a cluster message replays
+type clusterMessageKind string
+
+const (
+    clusterBroadcast     clusterMessageKind = "broadcast"
+    clusterEvent         clusterMessageKind = "event"
+    clusterUserEvent     clusterMessageKind = "user_event"
+    clusterUsersEvent    clusterMessageKind = "users_event"
+    clusterFollowerEvent clusterMessageKind = "follower_event"
+    clusterFollowUpdate  clusterMessageKind = "follow_update"
+    clusterTopicEvent    clusterMessageKind = "topic_event"
+    clusterRecheckTopic  clusterMessageKind = "recheck_topic"
+    clusterRecheckUser   clusterMessageKind = "recheck_user"
+)
+
+// clusterMessage is a hub operation relayed to the other instances
+type clusterMessage struct {
+    Origin     string                 `json:"origin"`
+    Kind       clusterMessageKind     `json:"kind"`
+    UserID     uuid.UUID              `json:"user_id,omitempty"`
+    UserIDs    []uuid.UUID            `json:"user_ids,omitempty"`
+    FolloweeID uuid.UUID              `json:"followee_id,omitempty"`
+    Following  bool                   `json:"following,omitempty"`
+    Visibility models.StoryVisibility `json:"visibility,omitempty"`
+    Topic      Topic                  `json:"topic,omitempty"`
+    Event      *Event                 `json:"event,omitempty"`
+    Raw        []byte                 `json:"raw,omitempty"`
+}
+
+// outboxMessage is an encoded message waiting to be published
+type outboxMessage struct {
+    kind clusterMessageKind
+    data []byte
+}
+
+// presenceChange records a user connecting to or leaving this instance
+type presenceChange struct {
+    userID    uuid.UUID
+    connected bool
+}
+
+// Cluster relays hub traffic between API instances over Redis pub/sub, so every
+// instance can deliver to the clients connected to it, and keeps a registry of
+// which instances each user is connected to.
+//
+// Messages are published from a single queue and Redis delivers a channel's
+// messages in order, so events for a user arrive in the order this instance
+// sent them. There is no delivery order between events sent from different
+// instances; events sent to a user carry a seq from a per-user counter in Redis
+// that is shared by every instance, so clients that need a strict order sort by
+// it. Messages that can't be relayed are dropped, logged and counted in
+// realtime_relay_messages_dropped_total.
+type Cluster struct {
+    hub         *Hub
+    redisClient *storage.RedisClient
+    instanceID  string
+    channel     string
+    presenceTTL time.Duration
+    logger      *zap.Logger
+
+    outbox   chan outboxMessage
+    presence chan presenceChange
+    stopCh   chan struct{}
+}
+
+// NewCluster creates a cluster relay and attaches it to the hub. It must be
+// called before the hub starts running.
+func NewCluster(hub *Hub, redisClient *storage.RedisClient, cfg config.RealtimeConfig, logger *zap.Logger) *Cluster {
+    instanceID := uuid.New().String()[:8]
+    if hostname, err := os.Hostname(); err == nil {
+        instanceID = hostname + "-" + instanceID
+    }
+
+    presenceTTL := cfg.PresenceTTL
+    if presenceTTL <= 0 {
+        presenceTTL = time.Minute
+    }
+
+    cluster := &Cluster{
+        hub:         hub,
+        redisClient: redisClient,
+        instanceID:  instanceID,
+        channel:     cfg.ClusterChannel,
+        presenceTTL: presenceTTL,
+        logger:      logger.With(zap.String("component", "realtime_cluster"), zap.String("instance_id", instanceID)),
+        outbox:      make(chan outboxMessage, clusterOutboxSize),
+        presence:    make(chan presenceChange, presenceQueueSize),
+        stopCh:      make(chan struct{}),
+    }
+    hub.cluster = cluster
+
+    return cluster
+}
+
+// InstanceID returns the ID this instance is known by in the cluster
+func (c *Cluster) InstanceID() string {
+    return c.instanceID
+}
+
+// Start starts relaying messages and maintaining presence
+func (c *Cluster) Start() {
+    c.logger.Info("Starting realtime cluster relay", zap.String("channel", c.channel))
+
+    go c.subscribe()
+    go c.publishLoop()
+    go c.presenceLoop()
+}
+
+// Stop stops the relay and removes this instance's users from the presence registry
+func (c *Cluster) Stop() {
+    c.logger.Info("Stopping realtime cluster relay")
+    close(c.stopCh)
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    pipe := c.redisClient.Pipeline()
+    for _, userID := range c.hub.GetConnectedUsers() {
+        pipe.HDel(ctx, presenceKey(userID), c.instanceID)
+    }
+    if _, err := pipe.Exec(ctx); err != nil {
+        c.logger.Error("Failed to clear presence on stop", zap.Error(err))
+    }
+}
+
+// IsUserOnline checks if a user is connected to any instance
+func (c *Cluster) IsUserOnline(ctx context.Context, userID uuid.UUID) (bool, error) {
+    instances, err := c.redisClient.GetClient().HGetAll(ctx, presenceKey(userID)).Result()
+    if err != nil {
+        return false, fmt.Errorf("failed to get presence: %w", err)
+    }
+
+    return hasLiveInstance(instances, ""), nil
+}
+
+// onlineElsewhere checks which of the given users are connected to an instance
+// other than this one
+func (c *Cluster) onlineElsewhere(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+    online := make(map[uuid.UUID]bool, len(userIDs))
+    if len(userIDs) == 0 {
+        return online, nil
+    }
+
+    pipe := c.redisClient.Pipeline()
+    cmds := make([]*redis.MapStringStringCmd, len(userIDs))
+    for i, userID := range userIDs {
+        cmds[i] = pipe.HGetAll(ctx, presenceKey(userID))
+    }
+    if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+        return nil, fmt.Errorf("failed to get presence: %w", err)
+    }
+
+    for i, cmd := range cmds {
+        if hasLiveInstance(cmd.Val(), c.instanceID) {
+            online[userIDs[i]] = true
+        }
+    }
+
+    return online, nil
+}
+
+// hasLiveInstance reports whether a presence entry has an unexpired instance
+// other than exclude
+func hasLiveInstance(instances map[string]string, exclude string) bool {
+    now := time.Now().Unix()
+    for instanceID, value := range instances {
+        if instanceID == exclude {
+            continue
+        }
+        expiresAt, err := strconv.ParseInt(value, 10, 64)
+        if err == nil && expiresAt > now {
+            return true
+        }
+    }
+    return false
+}
+
+// publish queues a hub operation for the other instances. It never blocks the
+// caller; if Redis can't keep up the message is dropped and logged.
+func (c *Cluster) publish(message *clusterMessage) {
+    message.Origin = c.instanceID
+
+    // Stamp before encoding so every instance sends the same timestamp
+    if message.Event != nil && message.Event.Timestamp == 0 {
+        message.Event.Timestamp = time.Now().Unix()
+    }
+
+    data, err := json.Marshal(message)
+    if err != nil {
+        c.logger.Error("Failed to marshal cluster message",
+            zap.String("kind", string(message.Kind)),
+            zap.Error(err),
+        )
+        c.recordDropped(message.Kind, relayDropMarshalFailed)
+        return
+    }
+
+    select {
+    case c.outbox <- outboxMessage{kind: message.Kind, data: data}:
+    default:
+        c.logger.Warn("Cluster outbox full, dropping message",
+            zap.String("kind", string(message.Kind)),
+        )
+        c.recordDropped(message.Kind, relayDropOutboxFull)
+    }
+}
+
+// recordDropped counts a message the other instances won't get
+func (c *Cluster) recordDropped(kind clusterMessageKind, reason string) {
+    if c.hub.metrics != nil {
+        c.hub.metrics.RealtimeRelayDropped.WithLabelValues(string(kind), reason).Inc()
+    }
+}
+
+// publishLoop publishes queued messages one at a time, preserving their order
+func (c *Cluster) publishLoop() {
+    for {
+        select {
+        case <-c.stopCh:
+            return
+
+        case message := <-c.outbox:
+            ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+            err := c.redisClient.GetClient().Publish(ctx, c.channel, message.data).Err()
+            cancel()
+            if err != nil {
+                c.logger.Error("Failed to publish cluster message",
+                    zap.String("kind", string(message.kind)),
+                    zap.Error(err),
+                )
+                c.recordDropped(message.kind, relayDropPublishFailed)
+            }
+        }
+    }
+}
+
+// subscribe receives other instances' messages and hands them to the local hub
+func (c *Cluster) subscribe() {
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+
+    pubsub := c.redisClient.GetClient().Subscribe(ctx, c.channel)
+    defer pubsub.Close()
+
+    messages := pubsub.Channel()
+    for {
+        select {
+        case <-c.stopCh:
+            return
+
+        case msg, ok := <-messages:
+            if !ok {
+                return
+            }
+
+            var message clusterMessage
+            if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+                c.logger.Warn("Failed to unmarshal cluster message", zap.Error(err))
+                continue
+            }
+
+            // Our own messages were delivered locally when they were sent
+            if message.Origin == c.instanceID {
+                continue
+            }
+
+            c.deliver(&message)
+        }
+    }
+}
+
+// deliver replays a relayed operation against the local hub only
+func (c *Cluster) deliver(message *clusterMessage) {
+    switch message.Kind {
+    case clusterBroadcast:
+        c.hub.broadcast <- message.Raw
+    case clusterEvent:
+        c.hub.eventBroadcast <- message.Event
+    case clusterUserEvent:
+        c.hub.userEvents <- &UserEvent{UserID: message.UserID, Event: message.Event}
+    case clusterUsersEvent:
+        c.hub.usersEvents <- &UsersEvent{UserIDs: message.UserIDs, Event: message.Event}
+    case clusterFollowerEvent:
+        c.hub.followerEvents <- &FollowerEvent{
+            AuthorID:       message.UserID,
+            Visibility:     message.Visibility,
+            Event:          message.Event,
+            ExcludeUserIDs: message.UserIDs,
+        }
+    case clusterFollowUpdate:
+        c.hub.followUpdates <- &FollowUpdate{
+            FollowerID: message.UserID,
+            FolloweeID: message.FolloweeID,
+            Following:  message.Following,
+        }
+    case clusterTopicEvent:
+        c.hub.topicEvents <- &TopicEvent{Topic: message.Topic, Event: message.Event, ExcludeUserIDs: message.UserIDs}
+    case clusterRecheckTopic:
+        go c.hub.recheckTopic(message.Topic)
+    case clusterRecheckUser:
+        go c.hub.recheckUser(message.UserID)
+    default:
+        c.logger.Warn("Unknown cluster message kind", zap.String("kind", string(message.Kind)))
+    }
+}
+
+// trackPresence queues a presence change without blocking the hub. A dropped
+// change is repaired by the next heartbeat or expires on its own.
+func (c *Cluster) trackPresence(userID uuid.UUID, connected bool) {
+    select {
+    case c.presence <- presenceChange{userID: userID, connected: connected}:
+    default:
+        c.logger.Warn("Presence queue full, dropping change",
+            zap.String("user_id", userID.String()),
+        )
+    }
+}
+
+// presenceLoop writes presence changes and refreshes this instance's entries
+// before they expire
+func (c *Cluster) presenceLoop() {
+    ticker := time.NewTicker(c.presenceTTL / 3)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-c.stopCh:
+            return
+
+        case change := <-c.presence:
+            c.writePresence(change)
+
+        case <-ticker.C:
+            c.refreshPresence()
+        }
+    }
+}
+
+// writePresence records or removes this instance in a user's presence entry
+func (c *Cluster) writePresence(change presenceChange) {
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    key := presenceKey(change.userID)
+    pipe := c.redisClient.Pipeline()
+    if change.connected {
+        pipe.HSet(ctx, key, c.instanceID, time.Now().Add(c.presenceTTL).Unix())
+        pipe.Expire(ctx, key, c.presenceTTL)
+    } else {
+        pipe.HDel(ctx, key, c.instanceID)
+    }
+
+    if _, err := pipe.Exec(ctx); err != nil {
+        c.logger.Error("Failed to update presence",
+            zap.String("user_id", change.userID.String()),
+            zap.Bool("connected", change.connected),
+            zap.Error(err),
+        )
+    }
+}
+
+// refreshPresence extends the presence entries of every locally connected user.
+// Entries from an instance that died stop being refreshed and expire.
+func (c *Cluster) refreshPresence() {
+    users := c.hub.GetConnectedUsers()
+    if len(users) == 0 {
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+
+    expiresAt := time.Now().Add(c.presenceTTL).Unix()
+    pipe := c.redisClient.Pipeline()
+    for _, userID := range users {
+        key := presenceKey(userID)
+        pipe.HSet(ctx, key, c.instanceID, expiresAt)
+        pipe.Expire(ctx, key, c.presenceTTL)
+    }
+
+    if _, err := pipe.Exec(ctx); err != nil {
+        c.logger.Error("Failed to refresh presence",
+            zap.Int("users", len(users)),
+            zap.Error(err),
+        )
+    }
+}
+
+// presenceKey is the hash of instance IDs a user is connected to, each mapped
+// to the unix time its entry expires
+func presenceKey(userID uuid.UUID) string {
+    return fmt.Sprintf("realtime:presence:%s", userID.String())
+}
@@ -0,0 +1,286 @@
+package realtime
+
+import (
+    "context"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// eventTimeout bounds how long a test waits for a relayed event
+const eventTimeout = 2 * time.Second
+
+// newTestRedis connects to the Redis at REDIS_TEST_URL, or to an in-process
+// Redis when it isn't set. Keys and channels used by tests are random, so a
+// shared Redis isn't disturbed.
+func newTestRedis(t *testing.T) *storage.RedisClient {
+    t.Helper()
+
+    url := os.Getenv("REDIS_TEST_URL")
+    if url == "" {
+        server := miniredis.RunT(t)
+        url = "redis://" + server.Addr()
+    }
+
+    client, err := storage.NewRedisClient(&config.Config{RedisURL: url}, zap.NewNop())
+    if err != nil {
+        t.Fatalf("failed to connect to test Redis: %v", err)
+    }
+    t.Cleanup(func() { client.Close() })
+
+    return client
+}
+
+// testInstance is one API instance: a running hub relaying through a cluster
+type testInstance struct {
+    hub     *Hub
+    cluster *Cluster
+}
+
+// newTestCluster starts n hubs relaying to each other through one Redis and
+// waits until every instance is subscribed
+func newTestCluster(t *testing.T, n int, followers FollowerResolver) []*testInstance {
+    t.Helper()
+
+    redisClient := newTestRedis(t)
+    cfg := config.RealtimeConfig{
+        ClusterChannel: "test:realtime:" + uuid.New().String(),
+        PresenceTTL:    time.Minute,
+    }
+
+    instances := make([]*testInstance, n)
+    for i := range instances {
+        hub := newTestHub(t, followers, cfg)
+        cluster := NewCluster(hub, redisClient, cfg, zap.NewNop())
+        cluster.Start()
+        go hub.Run()
+        t.Cleanup(cluster.Stop)
+
+        instances[i] = &testInstance{hub: hub, cluster: cluster}
+    }
+
+    // Messages published before an instance subscribes would be lost
+    deadline := time.Now().Add(eventTimeout)
+    for {
+        counts, err := redisClient.GetClient().PubSubNumSub(context.Background(), cfg.ClusterChannel).Result()
+        if err != nil {
+            t.Fatalf("failed to count subscribers: %v", err)
+        }
+        if counts[cfg.ClusterChannel] == int64(n) {
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("only %d of %d instances subscribed", counts[cfg.ClusterChannel], n)
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+
+    return instances
+}
+
+// connectLive registers a client with a running hub and waits for its welcome
+func connectLive(t *testing.T, hub *Hub, user *models.User) *Client {
+    t.Helper()
+
+    client := NewClient(hub, nil, user, "", zap.NewNop())
+    hub.Register(client)
+    waitForEvent(t, client, EventWelcome)
+
+    return client
+}
+
+// waitForEvent waits for the next event sent to a client and checks its type
+func waitForEvent(t *testing.T, client *Client, eventType EventType) *Event {
+    t.Helper()
+
+    select {
+    case data := <-client.send:
+        event, err := FromJSON(data)
+        if err != nil {
+            t.Fatalf("failed to decode event: %v", err)
+        }
+        if event.Type != eventType {
+            t.Fatalf("got %s event, want %s", event.Type, eventType)
+        }
+        return event
+    case <-time.After(eventTimeout):
+        t.Fatalf("timed out waiting for %s event", eventType)
+        return nil
+    }
+}
+
+// expectNoEvent checks that nothing more is sent to a client for a while
+func expectNoEvent(t *testing.T, client *Client) {
+    t.Helper()
+
+    select {
+    case data := <-client.send:
+        t.Fatalf("unexpected event: %s", data)
+    case <-time.After(100 * time.Millisecond):
+    }
+}
+
+func TestClusterRelaysUserEvents(t *testing.T) {
+    instances := newTestCluster(t, 2, nil)
+    user := newTestUser("user")
+
+    local := connectLive(t, instances[0].hub, user)
+    remote := connectLive(t, instances[1].hub, user)
+
+    instances[0].hub.SendToUser(user.ID, NewEvent(EventNotification, map[string]interface{}{"n": 1}))
+
+    waitForEvent(t, local, EventNotification)
+    waitForEvent(t, remote, EventNotification)
+
+    // The sender doesn't deliver its own relayed message a second time
+    expectNoEvent(t, local)
+    expectNoEvent(t, remote)
+}
+
+func TestClusterRelaysToManyUsers(t *testing.T) {
+    instances := newTestCluster(t, 3, nil)
+    first := newTestUser("first")
+    second := newTestUser("second")
+    other := newTestUser("other")
+
+    firstClient := connectLive(t, instances[1].hub, first)
+    secondClient := connectLive(t, instances[2].hub, second)
+    otherClient := connectLive(t, instances[2].hub, other)
+
+    instances[0].hub.SendToUsers([]uuid.UUID{first.ID, second.ID}, NewEvent(EventNotification, nil))
+
+    waitForEvent(t, firstClient, EventNotification)
+    waitForEvent(t, secondClient, EventNotification)
+    expectNoEvent(t, otherClient)
+}
+
+func TestClusterRelaysFollowerEvents(t *testing.T) {
+    author := newTestUser("author")
+    follower := newTestUser("follower")
+    stranger := newTestUser("stranger")
+
+    instances := newTestCluster(t, 2, staticFollowers{follower.ID: {author.ID}})
+
+    followerClient := connectLive(t, instances[1].hub, follower)
+    strangerClient := connectLive(t, instances[1].hub, stranger)
+
+    instances[0].hub.BroadcastToFollowers(author.ID, models.VisibilityFriends, NewEvent(EventStoryCreated, nil))
+
+    waitForEvent(t, followerClient, EventStoryCreated)
+    expectNoEvent(t, strangerClient)
+
+    // Exclusions are relayed with the event
+    instances[0].hub.BroadcastToFollowersExcept(author.ID, models.VisibilityFriends, NewEvent(EventStoryCreated, nil), []uuid.UUID{follower.ID})
+    expectNoEvent(t, followerClient)
+}
+
+func TestClusterRelaysFollowUpdates(t *testing.T) {
+    author := newTestUser("author")
+    fan := newTestUser("fan")
+
+    instances := newTestCluster(t, 2, staticFollowers{})
+    fanClient := connectLive(t, instances[1].hub, fan)
+
+    // The follow is made through the other instance
+    instances[0].hub.UpdateFollow(fan.ID, author.ID, true)
+    instances[0].hub.BroadcastToFollowers(author.ID, models.VisibilityFriends, NewEvent(EventStoryCreated, nil))
+
+    waitForEvent(t, fanClient, EventStoryCreated)
+}
+
+func TestClusterRelaysTopicEvents(t *testing.T) {
+    instances := newTestCluster(t, 2, nil)
+    subscriber := newTestUser("subscriber")
+    topic := HashtagTopic("golang")
+
+    client := connectLive(t, instances[1].hub, subscriber)
+    instances[1].hub.subscriptions <- &Subscription{Client: client, Topic: topic, Subscribe: true}
+    waitForEvent(t, client, EventSubscribed)
+
+    instances[0].hub.PublishToTopic(topic, NewEvent(EventStoryCreated, nil))
+
+    waitForEvent(t, client, EventStoryCreated)
+}
+
+func TestClusterKeepsOrderFromOneInstance(t *testing.T) {
+    instances := newTestCluster(t, 2, nil)
+    user := newTestUser("user")
+    client := connectLive(t, instances[1].hub, user)
+
+    const count = 50
+    for i := 0; i < count; i++ {
+        instances[0].hub.SendToUser(user.ID, NewEvent(EventNotification, map[string]interface{}{"n": i}))
+    }
+
+    for i := 0; i < count; i++ {
+        event := waitForEvent(t, client, EventNotification)
+        if n, _ := event.Payload["n"].(float64); int(n) != i {
+            t.Fatalf("event %d arrived in position %d", int(n), i)
+        }
+    }
+}
+
+func TestClusterPresence(t *testing.T) {
+    instances := newTestCluster(t, 2, nil)
+    user := newTestUser("user")
+    connectLive(t, instances[1].hub, user)
+
+    ctx := context.Background()
+    deadline := time.Now().Add(eventTimeout)
+    for {
+        online, err := instances[0].cluster.IsUserOnline(ctx, user.ID)
+        if err != nil {
+            t.Fatalf("failed to check presence: %v", err)
+        }
+        if online {
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatal("user connected to another instance isn't online")
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+
+    elsewhere, err := instances[0].cluster.onlineElsewhere(ctx, []uuid.UUID{user.ID})
+    if err != nil {
+        t.Fatalf("failed to check presence: %v", err)
+    }
+    if !elsewhere[user.ID] {
+        t.Error("user isn't online elsewhere from the other instance")
+    }
+
+    elsewhere, err = instances[1].cluster.onlineElsewhere(ctx, []uuid.UUID{user.ID})
+    if err != nil {
+        t.Fatalf("failed to check presence: %v", err)
+    }
+    if elsewhere[user.ID] {
+        t.Error("user is online elsewhere from the instance they are connected to")
+    }
+}
+
+func TestClusterCountsDroppedMessages(t *testing.T) {
+    hub := newTestHub(t, nil, config.RealtimeConfig{})
+    hub.metrics = testCollector()
+    cluster := NewCluster(hub, newTestRedis(t), config.RealtimeConfig{ClusterChannel: "test:unused"}, zap.NewNop())
+
+    // Without the publish loop running nothing leaves the outbox
+    for i := 0; i < clusterOutboxSize; i++ {
+        cluster.publish(&clusterMessage{Kind: clusterBroadcast})
+    }
+
+    counter := hub.metrics.RealtimeRelayDropped.WithLabelValues(string(clusterBroadcast), relayDropOutboxFull)
+    before := counterValue(t, counter)
+    cluster.publish(&clusterMessage{Kind: clusterBroadcast})
+
+    if got := counterValue(t, counter) - before; got != 1 {
+        t.Errorf("dropped message counted %v times, want 1", got)
+    }
+}
@@ -0,0 +1,152 @@
+package realtime
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "reflect"
+
+    "github.com/gorilla/websocket"
+    "github.com/ugorji/go/codec"
+)
+
+// Websocket subprotocols a client can ask for in Sec-WebSocket-Protocol.
+// Clients that ask for neither get JSON.
+const (
+    SubprotocolJSON    = "stories.json"
+    SubprotocolMsgPack = "stories.msgpack"
+)
+
+// Subprotocols lists the supported subprotocols in order of preference
+var Subprotocols = []string{SubprotocolMsgPack, SubprotocolJSON}
+
+// Encoding converts events to and from a client's wire format
+type Encoding interface {
+    // Encode encodes an event for the client
+    Encode(event *Event) ([]byte, error)
+
+    // Decode decodes a message from the client
+    Decode(data []byte, event *Event) error
+
+    // FromJSON re-encodes a JSON message, such as a raw broadcast
+    FromJSON(data []byte) ([]byte, error)
+
+    // MessageType is the websocket message type frames are sent as
+    MessageType() int
+}
+
+// EncodingFor returns the encoding for a negotiated subprotocol
+func EncodingFor(subprotocol string) Encoding {
+    if subprotocol == SubprotocolMsgPack {
+        return msgpackEncoding{}
+    }
+    return jsonEncoding{}
+}
+
+// jsonEncoding sends events as JSON text frames
+type jsonEncoding struct{}
+
+// Encode encodes an event as JSON
+func (jsonEncoding) Encode(event *Event) ([]byte, error) {
+    return json.Marshal(event)
+}
+
+// Decode decodes a JSON message
+func (jsonEncoding) Decode(data []byte, event *Event) error {
+    return json.Unmarshal(data, event)
+}
+
+// FromJSON returns the message unchanged
+func (jsonEncoding) FromJSON(data []byte) ([]byte, error) {
+    return data, nil
+}
+
+// MessageType returns the text message type
+func (jsonEncoding) MessageType() int {
+    return websocket.TextMessage
+}
+
+// msgpackHandle is shared by every MessagePack client; it is safe for
+// concurrent use once configured
+var msgpackHandle = newMsgpackHandle()
+
+// newMsgpackHandle configures MessagePack with separate string and binary types
+func newMsgpackHandle() *codec.MsgpackHandle {
+    handle := &codec.MsgpackHandle{}
+    handle.WriteExt = true
+    handle.RawToString = true
+    handle.MapType = reflect.TypeOf(map[string]interface{}(nil))
+    return handle
+}
+
+// msgpackEncoding sends events as MessagePack binary frames. Events go through
+// JSON first, so fields, IDs and times have the same names and forms as they do
+// for JSON clients and both share one event model.
+type msgpackEncoding struct{}
+
+// Encode encodes an event as MessagePack
+func (e msgpackEncoding) Encode(event *Event) ([]byte, error) {
+    data, err := json.Marshal(event)
+    if err != nil {
+        return nil, err
+    }
+    return e.FromJSON(data)
+}
+
+// Decode decodes a MessagePack message
+func (msgpackEncoding) Decode(data []byte, event *Event) error {
+    var value interface{}
+    if err := codec.NewDecoderBytes(data, msgpackHandle).Decode(&value); err != nil {
+        return fmt.Errorf("failed to decode msgpack message: %w", err)
+    }
+
+    data, err := json.Marshal(value)
+    if err != nil {
+        return fmt.Errorf("failed to convert msgpack message: %w", err)
+    }
+    return json.Unmarshal(data, event)
+}
+
+// FromJSON converts a JSON message to MessagePack
+func (msgpackEncoding) FromJSON(data []byte) ([]byte, error) {
+    decoder := json.NewDecoder(bytes.NewReader(data))
+    decoder.UseNumber()
+
+    var value interface{}
+    if err := decoder.Decode(&value); err != nil {
+        return nil, fmt.Errorf("failed to decode json message: %w", err)
+    }
+
+    var out []byte
+    if err := codec.NewEncoderBytes(&out, msgpackHandle).Encode(compactNumbers(value)); err != nil {
+        return nil, fmt.Errorf("failed to encode msgpack message: %w", err)
+    }
+    return out, nil
+}
+
+// MessageType returns the binary message type
+func (msgpackEncoding) MessageType() int {
+    return websocket.BinaryMessage
+}
+
+// compactNumbers replaces JSON numbers with integers where they are whole, so
+// counters and timestamps aren't sent as 9-byte floats
+func compactNumbers(value interface{}) interface{} {
+    switch v := value.(type) {
+    case json.Number:
+        if i, err := v.Int64(); err == nil {
+            return i
+        }
+        f, _ := v.Float64()
+        return f
+    case map[string]interface{}:
+        for key, item := range v {
+            v[key] = compactNumbers(item)
+        }
+    case []interface{}:
+        for i, item := range v {
+            v[i] = compactNumbers(item)
+        }
+    }
+    return value
+}
@@ -0,0 +1,220 @@
+package realtime
+
+import (
+    "math"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/gorilla/websocket"
+    "github.com/ugorji/go/codec"
+)
+
+var (
+    testEventID   = uuid.MustParse("6f1c1a58-3a8e-4d55-9a51-0f6e4a1d2b7c")
+    testEventTime = time.Date(2024, 5, 17, 9, 30, 15, 123000000, time.UTC)
+)
+
+// testEvent has a payload with every kind of value events carry
+func testEvent() *Event {
+    return &Event{
+        Type:      EventStoryCounters,
+        ID:        "event-1",
+        Seq:       42,
+        Timestamp: 1715938215,
+        Payload: map[string]interface{}{
+            "story_id":   testEventID,
+            "created_at": testEventTime,
+            "views":      7,
+            "whole":      3.0,
+            "ratio":      2.5,
+            "negative":   -12,
+            "large":      int64(math.MaxInt32) * 4,
+            "text":       "hello",
+            "flag":       true,
+            "missing":    nil,
+            "author": map[string]interface{}{
+                "id":       testEventID,
+                "username": "alice",
+                "stats": map[string]interface{}{
+                    "followers": 10,
+                    "score":     0.75,
+                },
+            },
+            "tags": []interface{}{"go", 1, 1.5},
+        },
+    }
+}
+
+// wantPayload is the payload any client decodes from testEvent: IDs and times
+// become strings, and numbers become float64 as they do for JSON
+func wantPayload() map[string]interface{} {
+    return map[string]interface{}{
+        "story_id":   testEventID.String(),
+        "created_at": testEventTime.Format(time.RFC3339Nano),
+        "views":      float64(7),
+        "whole":      float64(3),
+        "ratio":      2.5,
+        "negative":   float64(-12),
+        "large":      float64(int64(math.MaxInt32) * 4),
+        "text":       "hello",
+        "flag":       true,
+        "missing":    nil,
+        "author": map[string]interface{}{
+            "id":       testEventID.String(),
+            "username": "alice",
+            "stats": map[string]interface{}{
+                "followers": float64(10),
+                "score":     0.75,
+            },
+        },
+        "tags": []interface{}{"go", float64(1), 1.5},
+    }
+}
+
+// checkDecoded checks an event decoded from testEvent
+func checkDecoded(t *testing.T, got *Event) {
+    t.Helper()
+
+    want := testEvent()
+    if got.Type != want.Type || got.ID != want.ID || got.Seq != want.Seq || got.Timestamp != want.Timestamp {
+        t.Errorf("decoded %s/%s/%d/%d, want %s/%s/%d/%d",
+            got.Type, got.ID, got.Seq, got.Timestamp,
+            want.Type, want.ID, want.Seq, want.Timestamp)
+    }
+    if !reflect.DeepEqual(got.Payload, wantPayload()) {
+        t.Errorf("decoded payload %#v, want %#v", got.Payload, wantPayload())
+    }
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+    tests := []struct {
+        name        string
+        encoding    Encoding
+        messageType int
+    }{
+        {name: "json", encoding: jsonEncoding{}, messageType: websocket.TextMessage},
+        {name: "msgpack", encoding: msgpackEncoding{}, messageType: websocket.BinaryMessage},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := tt.encoding.MessageType(); got != tt.messageType {
+                t.Errorf("message type = %d, want %d", got, tt.messageType)
+            }
+
+            data, err := tt.encoding.Encode(testEvent())
+            if err != nil {
+                t.Fatalf("failed to encode: %v", err)
+            }
+
+            var decoded Event
+            if err := tt.encoding.Decode(data, &decoded); err != nil {
+                t.Fatalf("failed to decode: %v", err)
+            }
+            checkDecoded(t, &decoded)
+        })
+    }
+}
+
+func TestEncodingFromJSON(t *testing.T) {
+    raw, err := testEvent().ToJSON()
+    if err != nil {
+        t.Fatalf("failed to marshal event: %v", err)
+    }
+
+    for _, encoding := range []Encoding{jsonEncoding{}, msgpackEncoding{}} {
+        data, err := encoding.FromJSON(raw)
+        if err != nil {
+            t.Fatalf("%T: failed to convert: %v", encoding, err)
+        }
+
+        // A relayed raw message decodes the same as the event encoded directly
+        var decoded Event
+        if err := encoding.Decode(data, &decoded); err != nil {
+            t.Fatalf("%T: failed to decode: %v", encoding, err)
+        }
+        checkDecoded(t, &decoded)
+    }
+
+    if _, err := (msgpackEncoding{}).FromJSON([]byte("{not json")); err == nil {
+        t.Error("msgpack converted invalid JSON")
+    }
+}
+
+func TestMsgpackWireTypes(t *testing.T) {
+    data, err := msgpackEncoding{}.Encode(testEvent())
+    if err != nil {
+        t.Fatalf("failed to encode: %v", err)
+    }
+
+    var message map[string]interface{}
+    if err := codec.NewDecoderBytes(data, msgpackHandle).Decode(&message); err != nil {
+        t.Fatalf("failed to decode msgpack: %v", err)
+    }
+    payload := message["payload"].(map[string]interface{})
+    stats := payload["author"].(map[string]interface{})["stats"].(map[string]interface{})
+
+    // Whole numbers are sent as integers, fractional ones as floats
+    integers := map[string]interface{}{
+        "seq":       message["seq"],
+        "timestamp": message["timestamp"],
+        "views":     payload["views"],
+        "whole":     payload["whole"],
+        "negative":  payload["negative"],
+        "large":     payload["large"],
+        "followers": stats["followers"],
+    }
+    want := map[string]int64{
+        "seq":       42,
+        "timestamp": 1715938215,
+        "views":     7,
+        "whole":     3,
+        "negative":  -12,
+        "large":     int64(math.MaxInt32) * 4,
+        "followers": 10,
+    }
+    for name, value := range integers {
+        got, ok := wireInt(value)
+        if !ok {
+            t.Errorf("%s sent as %T, want an integer", name, value)
+            continue
+        }
+        if got != want[name] {
+            t.Errorf("%s = %d, want %d", name, got, want[name])
+        }
+    }
+
+    for name, value := range map[string]interface{}{"ratio": payload["ratio"], "score": stats["score"]} {
+        if _, ok := value.(float64); !ok {
+            t.Errorf("%s sent as %T, want float64", name, value)
+        }
+    }
+
+    // IDs and times are strings, as in JSON
+    if id, ok := payload["story_id"].(string); !ok || id != testEventID.String() {
+        t.Errorf("story_id sent as %#v", payload["story_id"])
+    }
+    if at, ok := payload["created_at"].(string); !ok || at != testEventTime.Format(time.RFC3339Nano) {
+        t.Errorf("created_at sent as %#v", payload["created_at"])
+    }
+}
+
+func TestMsgpackDecodeInvalid(t *testing.T) {
+    var event Event
+    if err := (msgpackEncoding{}).Decode([]byte{0xc1}, &event); err == nil {
+        t.Error("decoded an invalid msgpack message")
+    }
+}
+
+// wireInt returns a msgpack integer, which decodes as signed or unsigned
+func wireInt(value interface{}) (int64, bool) {
+    switch v := value.(type) {
+    case int64:
+        return v, true
+    case uint64:
+        return int64(v), true
+    }
+    return 0, false
+}
@@ -3,6 +3,8 @@ package realtime
 import (
     "encoding/json"
     "time"
+
+    "github.com/google/uuid"
 )
 
 // EventType represents different types of real-time events
@@ -16,17 +18,25 @@ const (
     EventPong         EventType = "pong"
     
     // Story events
-    EventStoryCreated EventType = "story_created"
-    EventStoryUpdated EventType = "story_updated"
-    EventStoryDeleted EventType = "story_deleted"
-    EventStoryViewed  EventType = "story_viewed"
-    EventStoryExpired EventType = "story_expired"
+    EventStoryCreated  EventType = "story_created"
+    EventStoryUpdated  EventType = "story_updated"
+    EventStoryDeleted  EventType = "story_deleted"
+    EventStoryViewed   EventType = "story_viewed"
+    EventStoryExpired  EventType = "story_expired"
+    EventStoryCounters EventType = "story_counters"
     
     // Reaction events
     EventStoryReaction        EventType = "story_reaction"
     EventStoryReactionUpdated EventType = "story_reaction_updated"
     EventStoryReactionRemoved EventType = "story_reaction_removed"
     
+    // Sticker events
+    EventStickerResults EventType = "sticker_results"
+    
+    // Message events
+    EventMessageNew  EventType = "message_new"
+    EventMessageRead EventType = "message_read"
+    
     // User events
     EventUserFollowed   EventType = "user_followed"
     EventUserUnfollowed EventType = "user_unfollowed"
@@ -34,6 +44,12 @@ const (
     EventUserOnline     EventType = "user_online"
     EventUserOffline    EventType = "user_offline"
     
+    // Topic subscription events
+    EventSubscribe    EventType = "subscribe"
+    EventUnsubscribe  EventType = "unsubscribe"
+    EventSubscribed   EventType = "subscribed"
+    EventUnsubscribed EventType = "unsubscribed"
+    
     // Typing events
     EventTyping EventType = "typing"
     
@@ -43,6 +59,9 @@ const (
     // Activity events
     EventActivityUpdate EventType = "activity_update"
     
+    // Session events
+    EventResyncRequired EventType = "resync_required"
+    
     // System events
     EventSystemMaintenance EventType = "system_maintenance"
     EventSystemAnnouncement EventType = "system_announcement"
@@ -54,6 +73,10 @@ type Event struct {
     Payload   map[string]interface{} `json:"payload"`
     Timestamp int64                  `json:"timestamp"`
     ID        string                 `json:"id,omitempty"`
+    
+    // Seq orders the events sent to one user, so a reconnecting client can ask
+    // for what it missed. Only events sent to a specific user are sequenced.
+    Seq int64 `json:"seq,omitempty"`
 }
 
 // NewEvent creates a new event with timestamp
@@ -103,6 +126,16 @@ func StoryViewedEvent(storyID string, viewer interface{}) *Event {
     })
 }
 
+// StoryCountersEvent creates a story counters event
+func StoryCountersEvent(storyID uuid.UUID, counters *StoryCounters) *Event {
+    return NewEvent(EventStoryCounters, map[string]interface{}{
+        "story_id":     storyID,
+        "views":        counters.Views,
+        "unique_views": counters.UniqueViews,
+        "reactions":    counters.Reactions,
+    })
+}
+
 // StoryReactionEvent creates a story reaction event
 func StoryReactionEvent(storyID string, reaction interface{}, user interface{}) *Event {
     return NewEvent(EventStoryReaction, map[string]interface{}{
@@ -214,9 +247,11 @@ func (e *Event) IsStoryEvent() bool {
         EventStoryDeleted,
         EventStoryViewed,
         EventStoryExpired,
+        EventStoryCounters,
         EventStoryReaction,
         EventStoryReactionUpdated,
         EventStoryReactionRemoved,
+        EventStickerResults,
     }
     
     for _, eventType := range storyEvents {
@@ -319,6 +354,7 @@ func (e *Event) Clone() *Event {
         Type:      e.Type,
         Payload:   payloadCopy,
         Timestamp: e.Timestamp,
+        Seq:       e.Seq,
     }
 }
 
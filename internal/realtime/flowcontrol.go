@@ -0,0 +1,164 @@
+package realtime
+
+import (
+    "fmt"
+    "time"
+)
+
+const (
+    defaultSendBuffer        = 256
+    defaultLowPriorityBuffer = 64
+
+    // maxRateViolations is how many messages in a row a client can send over
+    // its inbound rate before it is disconnected
+    maxRateViolations = 50
+)
+
+// Reasons a client loses events or its connection
+const (
+    evictSendBufferFull = "send_buffer_full"
+    evictInboundRate    = "inbound_rate_exceeded"
+    dropLowBufferFull   = "low_priority_buffer_full"
+)
+
+// EventPriority decides which events a slow client loses first
+type EventPriority int
+
+const (
+    // PriorityHigh events are never dropped; a client that can't keep up with
+    // them is disconnected
+    PriorityHigh EventPriority = iota
+
+    // PriorityLow events are superseded by newer ones, so they are coalesced
+    // while queued and dropped when the client falls behind
+    PriorityLow
+)
+
+// String returns the priority's metric label
+func (p EventPriority) String() string {
+    if p == PriorityLow {
+        return "low"
+    }
+    return "high"
+}
+
+// Priority returns the event's priority. Typing indicators, live counters and
+// online status only matter until the next one arrives.
+func (e *Event) Priority() EventPriority {
+    switch e.Type {
+    case EventTyping, EventStoryCounters, EventUserOnline, EventUserOffline:
+        return PriorityLow
+    }
+    return PriorityHigh
+}
+
+// coalesceKey identifies low-priority events that supersede each other. Events
+// without a key are never coalesced.
+func (e *Event) coalesceKey() string {
+    switch e.Type {
+    case EventStoryCounters:
+        return coalesceKeyOf(e.Type, e.Payload["story_id"])
+    case EventTyping:
+        return coalesceKeyOf(e.Type, e.Payload["user_id"], e.Payload["conversation_id"])
+    case EventUserOnline, EventUserOffline:
+        // A user going offline supersedes them coming online and vice versa
+        if user, ok := e.Payload["user"].(map[string]interface{}); ok {
+            return coalesceKeyOf("presence", user["id"])
+        }
+    }
+    return ""
+}
+
+// coalesceKeyOf builds a key from the kind and IDs of an event. IDs are
+// formatted so a uuid.UUID and its relayed string form give the same key.
+func coalesceKeyOf(kind EventType, id interface{}, scope ...interface{}) string {
+    if id == nil {
+        return ""
+    }
+    key := fmt.Sprintf("%s:%v", kind, id)
+    for _, part := range scope {
+        key += fmt.Sprintf(":%v", part)
+    }
+    return key
+}
+
+// queuedMessage is a low-priority message waiting to be written
+type queuedMessage struct {
+    key  string
+    data []byte
+}
+
+// tokenBucket limits the rate of a client's inbound messages. It is only used
+// from the client's read goroutine.
+type tokenBucket struct {
+    rate   float64
+    burst  float64
+    tokens float64
+    last   time.Time
+}
+
+// newTokenBucket creates a bucket allowing rate messages a second with bursts
+// of up to burst. It returns nil, meaning no limit, if rate isn't positive.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+    if rate <= 0 {
+        return nil
+    }
+    if burst < 1 {
+        burst = 1
+    }
+
+    return &tokenBucket{
+        rate:   rate,
+        burst:  float64(burst),
+        tokens: float64(burst),
+        last:   time.Now(),
+    }
+}
+
+// allow takes a token if one is available
+func (b *tokenBucket) allow() bool {
+    if b == nil {
+        return true
+    }
+
+    now := time.Now()
+    b.tokens += now.Sub(b.last).Seconds() * b.rate
+    if b.tokens > b.burst {
+        b.tokens = b.burst
+    }
+    b.last = now
+
+    if b.tokens < 1 {
+        return false
+    }
+    b.tokens--
+    return true
+}
+
+// recordDropped counts an event a client didn't get
+func (h *Hub) recordDropped(priority EventPriority, reason string) {
+    if h.metrics != nil {
+        h.metrics.RealtimeEventsDropped.WithLabelValues(priority.String(), reason).Inc()
+    }
+}
+
+// recordCoalesced counts a queued event replaced by a newer one
+func (h *Hub) recordCoalesced(eventType EventType) {
+    if h.metrics != nil {
+        h.metrics.RealtimeEventsCoalesced.WithLabelValues(string(eventType)).Inc()
+    }
+}
+
+// recordEvicted counts a client disconnected by flow control
+func (h *Hub) recordEvicted(reason string) {
+    if h.metrics != nil {
+        h.metrics.RealtimeClientsEvicted.WithLabelValues(reason).Inc()
+    }
+}
+
+// recordInboundLimited counts a client message rejected by the rate limit
+func (h *Hub) recordInboundLimited() {
+    if h.metrics != nil {
+        h.metrics.RealtimeInboundLimited.Inc()
+    }
+}
@@ -0,0 +1,178 @@
+package realtime
+
+import (
+    "context"
+    "time"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+// followResolveTimeout bounds the follow lookup done when a client connects
+const followResolveTimeout = 5 * time.Second
+
+// FollowerResolver looks up who a user follows, so the hub knows which
+// connected users should receive an author's story events
+type FollowerResolver interface {
+    GetFollowingIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// FollowerEvent represents an event for the followers of an author. Excluded
+// users don't get it even if they follow the author, e.g. the ones the author
+// blocked.
+type FollowerEvent struct {
+    AuthorID       uuid.UUID
+    Visibility     models.StoryVisibility
+    Event          *Event
+    ExcludeUserIDs []uuid.UUID
+}
+
+// FollowUpdate represents a follow or unfollow made while the hub is running
+type FollowUpdate struct {
+    FollowerID uuid.UUID
+    FolloweeID uuid.UUID
+    Following  bool
+}
+
+// resolveFollowing loads the users a connecting client follows. On failure the
+// client gets no follower events rather than events it may not be allowed to see.
+func (h *Hub) resolveFollowing(client *Client) {
+    if h.followers == nil {
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), followResolveTimeout)
+    defer cancel()
+
+    following, err := h.followers.GetFollowingIDs(ctx, client.User.ID)
+    if err != nil {
+        h.logger.Error("Failed to resolve following for client",
+            zap.String("user_id", client.User.ID.String()),
+            zap.Error(err),
+        )
+        return
+    }
+
+    client.following = following
+}
+
+// indexFollowing adds a newly connected user to the follower index of everyone
+// they follow. Must be called with the mutex held.
+func (h *Hub) indexFollowing(userID uuid.UUID, following []uuid.UUID) {
+    followees := make(map[uuid.UUID]bool, len(following))
+    for _, followeeID := range following {
+        followees[followeeID] = true
+        h.addToFollowerIndex(followeeID, userID)
+    }
+    h.following[userID] = followees
+}
+
+// unindexFollowing removes a disconnected user from the follower index. Must be
+// called with the mutex held.
+func (h *Hub) unindexFollowing(userID uuid.UUID) {
+    for followeeID := range h.following[userID] {
+        h.removeFromFollowerIndex(followeeID, userID)
+    }
+    delete(h.following, userID)
+}
+
+// addToFollowerIndex records a connected follower of an author
+func (h *Hub) addToFollowerIndex(authorID, followerID uuid.UUID) {
+    followers, exists := h.followerIndex[authorID]
+    if !exists {
+        followers = make(map[uuid.UUID]bool)
+        h.followerIndex[authorID] = followers
+    }
+    followers[followerID] = true
+}
+
+// removeFromFollowerIndex forgets a connected follower of an author
+func (h *Hub) removeFromFollowerIndex(authorID, followerID uuid.UUID) {
+    followers, exists := h.followerIndex[authorID]
+    if !exists {
+        return
+    }
+
+    delete(followers, followerID)
+    if len(followers) == 0 {
+        delete(h.followerIndex, authorID)
+    }
+}
+
+// updateFollow keeps the index in step with follows made by connected users
+func (h *Hub) updateFollow(update *FollowUpdate) {
+    h.mutex.Lock()
+    defer h.mutex.Unlock()
+
+    followees, connected := h.following[update.FollowerID]
+    if !connected {
+        // The follow is picked up from the database when they connect
+        return
+    }
+
+    if update.Following {
+        followees[update.FolloweeID] = true
+        h.addToFollowerIndex(update.FolloweeID, update.FollowerID)
+    } else {
+        delete(followees, update.FolloweeID)
+        h.removeFromFollowerIndex(update.FolloweeID, update.FollowerID)
+    }
+}
+
+// broadcastToFollowers sends an event to the connected users allowed to see a
+// story with the given visibility. Friends-only stories go to followers, the
+// same rule the feed uses; private stories only reach the author's own sessions.
+// Subscribers to the author's topic get the stories they would see in the feed.
+// Excluded users get nothing, however they would have been reached.
+func (h *Hub) broadcastToFollowers(followerEvent *FollowerEvent) {
+    h.mutex.RLock()
+    defer h.mutex.RUnlock()
+
+    authorID := followerEvent.AuthorID
+    visibility := followerEvent.Visibility
+
+    excluded := make(map[uuid.UUID]bool, len(followerEvent.ExcludeUserIDs))
+    for _, userID := range followerEvent.ExcludeUserIDs {
+        excluded[userID] = true
+    }
+
+    // A client can be both a follower and a subscriber but gets the event once
+    recipients := make(map[*Client]bool)
+    for _, client := range h.userClients[authorID] {
+        recipients[client] = true
+    }
+
+    if visibility == models.VisibilityPublic || visibility == models.VisibilityFriends {
+        for followerID := range h.followerIndex[authorID] {
+            if excluded[followerID] {
+                continue
+            }
+            for _, client := range h.userClients[followerID] {
+                recipients[client] = true
+            }
+        }
+
+        for client := range h.topicClients[UserTopic(authorID)] {
+            if excluded[client.User.ID] {
+                continue
+            }
+            if visibility == models.VisibilityFriends && !h.following[client.User.ID][authorID] {
+                continue
+            }
+            recipients[client] = true
+        }
+    }
+
+    for client := range recipients {
+        client.Send(followerEvent.Event)
+    }
+
+    h.logger.Debug("Sent event to followers",
+        zap.String("author_id", authorID.String()),
+        zap.String("visibility", string(visibility)),
+        zap.String("event_type", string(followerEvent.Event.Type)),
+        zap.Int("client_count", len(recipients)),
+    )
+}
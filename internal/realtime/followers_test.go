@@ -0,0 +1,176 @@
+package realtime
+
+import (
+    "testing"
+
+    "github.com/google/uuid"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+func TestBroadcastToFollowersByVisibility(t *testing.T) {
+    // Friends-only stories are the followers-only visibility: they go to the
+    // author's followers, the same rule the feed uses
+    tests := []struct {
+        name       string
+        visibility models.StoryVisibility
+        want       map[string]bool
+    }{
+        {
+            name:       "public",
+            visibility: models.VisibilityPublic,
+            want: map[string]bool{
+                "author":              true,
+                "author_other_device": true,
+                "follower":            true,
+                "follower_subscriber": true,
+                "subscriber":          true,
+                "stranger":            false,
+            },
+        },
+        {
+            name:       "friends only",
+            visibility: models.VisibilityFriends,
+            want: map[string]bool{
+                "author":              true,
+                "author_other_device": true,
+                "follower":            true,
+                "follower_subscriber": true,
+                "subscriber":          false,
+                "stranger":            false,
+            },
+        },
+        {
+            name:       "private",
+            visibility: models.VisibilityPrivate,
+            want: map[string]bool{
+                "author":              true,
+                "author_other_device": true,
+                "follower":            false,
+                "follower_subscriber": false,
+                "subscriber":          false,
+                "stranger":            false,
+            },
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            author := newTestUser("author")
+            follower := newTestUser("follower")
+            followerSubscriber := newTestUser("follower_subscriber")
+            subscriber := newTestUser("subscriber")
+            stranger := newTestUser("stranger")
+
+            hub := newTestHub(t, staticFollowers{
+                follower.ID:           {author.ID},
+                followerSubscriber.ID: {author.ID},
+            }, config.RealtimeConfig{})
+
+            clients := map[string]*Client{
+                "author":              connect(t, hub, author),
+                "author_other_device": connect(t, hub, author),
+                "follower":            connect(t, hub, follower),
+                "follower_subscriber": connect(t, hub, followerSubscriber),
+                "subscriber":          connect(t, hub, subscriber),
+                "stranger":            connect(t, hub, stranger),
+            }
+
+            for _, name := range []string{"follower_subscriber", "subscriber"} {
+                hub.updateSubscription(&Subscription{
+                    Client:    clients[name],
+                    Topic:     UserTopic(author.ID),
+                    Subscribe: true,
+                })
+                received(t, clients[name])
+            }
+
+            hub.broadcastToFollowers(&FollowerEvent{
+                AuthorID:   author.ID,
+                Visibility: tt.visibility,
+                Event:      NewEvent(EventStoryCreated, map[string]interface{}{"story_id": "story"}),
+            })
+
+            for name, client := range clients {
+                types := receivedTypes(t, client)
+                if len(types) > 1 {
+                    t.Errorf("%s received the event %d times", name, len(types))
+                }
+                got := len(types) == 1 && types[0] == EventStoryCreated
+                if got != tt.want[name] {
+                    t.Errorf("%s received event = %v, want %v", name, got, tt.want[name])
+                }
+            }
+        })
+    }
+}
+
+func TestBroadcastToFollowersFollowsWhileConnected(t *testing.T) {
+    author := newTestUser("author")
+    fan := newTestUser("fan")
+
+    hub := newTestHub(t, staticFollowers{}, config.RealtimeConfig{})
+    client := connect(t, hub, fan)
+
+    event := &FollowerEvent{
+        AuthorID:   author.ID,
+        Visibility: models.VisibilityFriends,
+        Event:      NewEvent(EventStoryCreated, nil),
+    }
+
+    hub.broadcastToFollowers(event)
+    if types := receivedTypes(t, client); len(types) != 0 {
+        t.Fatalf("non-follower received %v", types)
+    }
+
+    hub.updateFollow(&FollowUpdate{FollowerID: fan.ID, FolloweeID: author.ID, Following: true})
+    hub.broadcastToFollowers(event)
+    if types := receivedTypes(t, client); len(types) != 1 {
+        t.Fatalf("new follower received %v, want one event", types)
+    }
+
+    hub.updateFollow(&FollowUpdate{FollowerID: fan.ID, FolloweeID: author.ID, Following: false})
+    hub.broadcastToFollowers(event)
+    if types := receivedTypes(t, client); len(types) != 0 {
+        t.Fatalf("unfollowed user received %v", types)
+    }
+}
+
+func TestBroadcastToFollowersSkipsExcludedUsers(t *testing.T) {
+    author := newTestUser("author")
+    follower := newTestUser("follower")
+    blockedFollower := newTestUser("blocked_follower")
+    blockedSubscriber := newTestUser("blocked_subscriber")
+
+    hub := newTestHub(t, staticFollowers{
+        follower.ID:        {author.ID},
+        blockedFollower.ID: {author.ID},
+    }, config.RealtimeConfig{})
+
+    followerClient := connect(t, hub, follower)
+    blockedClients := []*Client{
+        connect(t, hub, blockedFollower),
+        connect(t, hub, blockedFollower),
+        connect(t, hub, blockedSubscriber),
+    }
+    hub.updateSubscription(&Subscription{Client: blockedClients[2], Topic: UserTopic(author.ID), Subscribe: true})
+    received(t, blockedClients[2])
+
+    // Blocking doesn't remove the follow, so the block is applied at fan-out
+    hub.broadcastToFollowers(&FollowerEvent{
+        AuthorID:       author.ID,
+        Visibility:     models.VisibilityPublic,
+        Event:          NewEvent(EventStoryCreated, nil),
+        ExcludeUserIDs: []uuid.UUID{blockedFollower.ID, blockedSubscriber.ID},
+    })
+
+    if types := receivedTypes(t, followerClient); len(types) != 1 {
+        t.Errorf("follower received %v, want one event", types)
+    }
+    for _, client := range blockedClients {
+        if types := receivedTypes(t, client); len(types) != 0 {
+            t.Errorf("excluded user %s received %v", client.User.Username, types)
+        }
+    }
+}
@@ -1,10 +1,15 @@
 package realtime
 
 import (
+    "context"
     "sync"
 
     "github.com/google/uuid"
     "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+    "github.com/Abhiro0p/stories-backend/pkg/metrics"
 )
 
 // Hub maintains the set of active clients and broadcasts messages to the clients
@@ -30,6 +35,66 @@ type Hub struct {
     // Targeted events to specific users
     userEvents chan *UserEvent
 
+    // Events for a list of users, used when replay is disabled
+    usersEvents chan *UsersEvent
+
+    // Events for the followers of an author
+    followerEvents chan *FollowerEvent
+
+    // Follow changes made by connected users
+    followUpdates chan *FollowUpdate
+
+    // Follow lookup for connecting users
+    followers FollowerResolver
+
+    // Connected user ID to the IDs of users they follow
+    following map[uuid.UUID]map[uuid.UUID]bool
+
+    // Author ID to the IDs of their connected followers
+    followerIndex map[uuid.UUID]map[uuid.UUID]bool
+
+    // Events for the subscribers of a topic
+    topicEvents chan *TopicEvent
+
+    // Subscribe and unsubscribe requests from clients
+    subscriptions chan *Subscription
+
+    // Subscription checks for client topic requests
+    topics TopicAuthorizer
+
+    // Conversation checks for client typing events
+    typing TypingAuthorizer
+
+    // Topic to subscribed clients mapping
+    topicClients map[Topic]map[*Client]bool
+
+    // Maximum topics per connection
+    maxSubscriptions int
+
+    // Per-connection flow control
+    sendBuffer        int
+    lowPriorityBuffer int
+    inboundRate       float64
+    inboundBurst      int
+
+    // Dropped event and eviction metrics, nil when not collected
+    metrics *metrics.Collector
+
+    // Sequencing and replay of events sent to users, nil when disabled
+    replay *ReplayBuffer
+
+    // Relay to the other API instances, nil when running standalone
+    cluster *Cluster
+
+    // Online status and last active tracking, nil when disabled
+    presence *Presence
+
+    // Periodic token checks for connected clients, nil when disabled
+    revalidator *Revalidator
+
+    // Websocket story views and live counter updates, nil when disabled
+    live *LiveStories
+
     // Logger
     logger *zap.Logger
 
@@ -43,17 +108,56 @@ type UserEvent struct {
     Event  *Event
 }
 
+// UsersEvent represents an event targeted to several users
+type UsersEvent struct {
+    UserIDs []uuid.UUID
+    Event   *Event
+}
+
 // NewHub creates a new WebSocket hub
-func NewHub(logger *zap.Logger) *Hub {
+func NewHub(followers FollowerResolver, topics TopicAuthorizer, typing TypingAuthorizer, replay *ReplayBuffer, collector *metrics.Collector, cfg config.RealtimeConfig, logger *zap.Logger) *Hub {
+    maxSubscriptions := cfg.MaxSubscriptions
+    if maxSubscriptions <= 0 {
+        maxSubscriptions = DefaultMaxSubscriptions
+    }
+
+    sendBuffer := cfg.SendBuffer
+    if sendBuffer <= 0 {
+        sendBuffer = defaultSendBuffer
+    }
+
+    lowPriorityBuffer := cfg.LowPriorityBuffer
+    if lowPriorityBuffer <= 0 {
+        lowPriorityBuffer = defaultLowPriorityBuffer
+    }
+
     return &Hub{
-        clients:        make(map[*Client]bool),
-        userClients:    make(map[uuid.UUID][]*Client),
-        broadcast:      make(chan []byte),
-        register:       make(chan *Client),
-        unregister:     make(chan *Client),
-        eventBroadcast: make(chan *Event),
-        userEvents:     make(chan *UserEvent),
-        logger:         logger.With(zap.String("component", "websocket_hub")),
+        clients:           make(map[*Client]bool),
+        userClients:       make(map[uuid.UUID][]*Client),
+        broadcast:         make(chan []byte),
+        register:          make(chan *Client),
+        unregister:        make(chan *Client),
+        eventBroadcast:    make(chan *Event),
+        userEvents:        make(chan *UserEvent),
+        usersEvents:       make(chan *UsersEvent),
+        followerEvents:    make(chan *FollowerEvent),
+        followUpdates:     make(chan *FollowUpdate),
+        followers:         followers,
+        following:         make(map[uuid.UUID]map[uuid.UUID]bool),
+        followerIndex:     make(map[uuid.UUID]map[uuid.UUID]bool),
+        topicEvents:       make(chan *TopicEvent),
+        subscriptions:     make(chan *Subscription),
+        topics:            topics,
+        typing:            typing,
+        topicClients:      make(map[Topic]map[*Client]bool),
+        maxSubscriptions:  maxSubscriptions,
+        sendBuffer:        sendBuffer,
+        lowPriorityBuffer: lowPriorityBuffer,
+        inboundRate:       cfg.InboundRate,
+        inboundBurst:      cfg.InboundBurst,
+        metrics:           collector,
+        replay:            replay,
+        logger:            logger.With(zap.String("component", "websocket_hub")),
     }
 }
 
@@ -77,12 +181,29 @@ func (h *Hub) Run() {
 
         case userEvent := <-h.userEvents:
             h.sendToUser(userEvent.UserID, userEvent.Event)
+
+        case usersEvent := <-h.usersEvents:
+            h.sendToUsers(usersEvent.UserIDs, usersEvent.Event)
+
+        case followerEvent := <-h.followerEvents:
+            h.broadcastToFollowers(followerEvent)
+
+        case update := <-h.followUpdates:
+            h.updateFollow(update)
+
+        case topicEvent := <-h.topicEvents:
+            h.sendToTopic(topicEvent)
+
+        case subscription := <-h.subscriptions:
+            h.updateSubscription(subscription)
         }
     }
 }
 
 // Register registers a new client
 func (h *Hub) Register(client *Client) {
+    // Resolved here so the database lookup doesn't hold up the hub loop
+    h.resolveFollowing(client)
     h.register <- client
 }
 
@@ -93,28 +214,120 @@ func (h *Hub) Unregister(client *Client) {
 
 // Broadcast sends a message to all connected clients
 func (h *Hub) Broadcast(message []byte) {
+    h.publish(&clusterMessage{Kind: clusterBroadcast, Raw: message})
     h.broadcast <- message
 }
 
 // BroadcastEvent sends an event to all connected clients
 func (h *Hub) BroadcastEvent(event *Event) {
+    h.publish(&clusterMessage{Kind: clusterEvent, Event: event})
     h.eventBroadcast <- event
 }
 
-// SendToUser sends an event to a specific user
+// SendToUser sends an event to a specific user. Events other than low-priority
+// ones are numbered and kept for replay.
 func (h *Hub) SendToUser(userID uuid.UUID, event *Event) {
+    event = h.sequence(userID, event)
+    h.publish(&clusterMessage{Kind: clusterUserEvent, UserID: userID, Event: event})
     h.userEvents <- &UserEvent{
         UserID: userID,
         Event:  event,
     }
 }
 
-// BroadcastToFollowers sends an event to all followers of a user
-func (h *Hub) BroadcastToFollowers(userID uuid.UUID, event *Event) {
-    // This would typically require a database lookup to get followers
-    // For now, we'll broadcast to all clients
-    // TODO: Implement proper follower lookup and targeted broadcasting
-    h.BroadcastEvent(event)
+// SendToUsers sends an event to several users. As with SendToUser, each
+// user's copy is numbered and kept for replay unless it is low priority.
+func (h *Hub) SendToUsers(userIDs []uuid.UUID, event *Event) {
+    if len(userIDs) == 0 {
+        return
+    }
+
+    // Without replay every user gets the same event, so one relay does
+    if h.replay == nil || !event.replayable() {
+        h.sendLive(userIDs, event)
+        return
+    }
+
+    for i, stamped := range h.sequenceMany(userIDs, event) {
+        h.publish(&clusterMessage{Kind: clusterUserEvent, UserID: userIDs[i], Event: stamped})
+        h.userEvents <- &UserEvent{
+            UserID: userIDs[i],
+            Event:  stamped,
+        }
+    }
+}
+
+// sendLive sends the same event to several users without numbering it or
+// keeping it for replay, for snapshots like counters that the next one replaces
+func (h *Hub) sendLive(userIDs []uuid.UUID, event *Event) {
+    h.publish(&clusterMessage{Kind: clusterUsersEvent, UserIDs: userIDs, Event: event})
+    h.usersEvents <- &UsersEvent{
+        UserIDs: userIDs,
+        Event:   event,
+    }
+}
+
+// BroadcastToFollowers sends a story event to the author's connected followers
+// that the story's visibility allows to see it
+func (h *Hub) BroadcastToFollowers(userID uuid.UUID, visibility models.StoryVisibility, event *Event) {
+    h.BroadcastToFollowersExcept(userID, visibility, event, nil)
+}
+
+// BroadcastToFollowersExcept sends a story event to the author's connected
+// followers, except those of the excluded users
+func (h *Hub) BroadcastToFollowersExcept(userID uuid.UUID, visibility models.StoryVisibility, event *Event, excludeUserIDs []uuid.UUID) {
+    h.markFanout(event, authorFanoutStream(userID))
+    h.publish(&clusterMessage{Kind: clusterFollowerEvent, UserID: userID, UserIDs: excludeUserIDs, Visibility: visibility, Event: event})
+    h.followerEvents <- &FollowerEvent{
+        AuthorID:       userID,
+        Visibility:     visibility,
+        Event:          event,
+        ExcludeUserIDs: excludeUserIDs,
+    }
+}
+
+// PublishToTopic sends an event to the clients subscribed to a topic
+func (h *Hub) PublishToTopic(topic Topic, event *Event) {
+    h.PublishToTopicExcept(topic, event, nil)
+}
+
+// PublishToTopicExcept sends an event to the clients subscribed to a topic,
+// except those of the excluded users
+func (h *Hub) PublishToTopicExcept(topic Topic, event *Event, excludeUserIDs []uuid.UUID) {
+    h.markFanout(event, topicFanoutStream(topic))
+    h.publish(&clusterMessage{Kind: clusterTopicEvent, Topic: topic, UserIDs: excludeUserIDs, Event: event})
+    h.topicEvents <- &TopicEvent{
+        Topic:          topic,
+        Event:          event,
+        ExcludeUserIDs: excludeUserIDs,
+    }
+}
+
+// StoryCountersChanged tells the hub a story's views or reactions changed, so
+// its author and topic subscribers get the new counts
+func (h *Hub) StoryCountersChanged(storyID uuid.UUID) {
+    if h.live != nil {
+        h.live.CountersChanged(storyID)
+    }
+}
+
+// UpdateFollow tells the hub a user followed or unfollowed someone, so their
+// open connections start or stop getting that user's story events
+func (h *Hub) UpdateFollow(followerID, followeeID uuid.UUID, following bool) {
+    h.publish(&clusterMessage{Kind: clusterFollowUpdate, UserID: followerID, FolloweeID: followeeID, Following: following})
+    h.followUpdates <- &FollowUpdate{
+        FollowerID: followerID,
+        FolloweeID: followeeID,
+        Following:  following,
+    }
+}
+
+// publish relays an operation to the other instances when clustered. It runs
+// before the local delivery so the event is encoded before any client touches it.
+func (h *Hub) publish(message *clusterMessage) {
+    if h.cluster != nil {
+        h.cluster.publish(message)
+    }
 }
 
 // registerClient registers a new client
@@ -126,11 +339,21 @@ func (h *Hub) registerClient(client *Client) {
 
     // Add to user clients mapping
     userID := client.User.ID
+    if _, connected := h.following[userID]; !connected {
+        h.indexFollowing(userID, client.following)
+        if h.cluster != nil {
+            h.cluster.trackPresence(userID, true)
+        }
+        if h.presence != nil {
+            h.presence.statusChanged(client.User, true)
+        }
+    }
     h.userClients[userID] = append(h.userClients[userID], client)
 
     h.logger.Info("Client registered",
         zap.String("user_id", userID.String()),
         zap.String("username", client.User.Username),
+        zap.String("transport", client.transport),
         zap.Int("total_clients", len(h.clients)),
         zap.Int("user_clients", len(h.userClients[userID])),
     )
@@ -143,7 +366,8 @@ func (h *Hub) registerClient(client *Client) {
             "user":    client.User.ToResponse(),
         },
     }
-    client.Send(welcomeEvent)
+    // Written directly so it goes out ahead of any replayed events
+    client.write(welcomeEvent)
 }
 
 // unregisterClient unregisters a client
@@ -153,7 +377,8 @@ func (h *Hub) unregisterClient(client *Client) {
 
     if _, ok := h.clients[client]; ok {
         delete(h.clients, client)
-        close(client.send)
+        client.closeSend()
+        h.unsubscribeAll(client)
 
         // Remove from user clients mapping
         userID := client.User.ID
@@ -168,6 +393,13 @@ func (h *Hub) unregisterClient(client *Client) {
         // Clean up empty user client list
         if len(h.userClients[userID]) == 0 {
             delete(h.userClients, userID)
+            h.unindexFollowing(userID)
+            if h.cluster != nil {
+                h.cluster.trackPresence(userID, false)
+            }
+            if h.presence != nil {
+                h.presence.statusChanged(client.User, false)
+            }
         }
 
         h.logger.Info("Client unregistered",
@@ -185,12 +417,7 @@ func (h *Hub) broadcastMessage(message []byte) {
     defer h.mutex.RUnlock()
 
     for client := range h.clients {
-        select {
-        case client.send <- message:
-        default:
-            close(client.send)
-            delete(h.clients, client)
-        }
+        client.writeRaw(message)
     }
 
     h.logger.Debug("Broadcasted message to all clients",
@@ -239,16 +466,42 @@ func (h *Hub) sendToUser(userID uuid.UUID, event *Event) {
     )
 }
 
+// sendToUsers sends an event to each of the given users connected here
+func (h *Hub) sendToUsers(userIDs []uuid.UUID, event *Event) {
+    h.mutex.RLock()
+    defer h.mutex.RUnlock()
+
+    clientCount := 0
+    for _, userID := range userIDs {
+        for _, client := range h.userClients[userID] {
+            client.Send(event)
+            clientCount++
+        }
+    }
+
+    h.logger.Debug("Sent event to users",
+        zap.String("event_type", string(event.Type)),
+        zap.Int("user_count", len(userIDs)),
+        zap.Int("client_count", clientCount),
+    )
+}
+
 // GetStats returns hub statistics
 func (h *Hub) GetStats() map[string]interface{} {
     h.mutex.RLock()
     defer h.mutex.RUnlock()
 
-    return map[string]interface{}{
+    stats := map[string]interface{}{
         "total_clients":     len(h.clients),
         "connected_users":   len(h.userClients),
         "clients_per_user":  h.getClientsPerUser(),
+        "topics":            len(h.topicClients),
     }
+    if h.cluster != nil {
+        stats["instance_id"] = h.cluster.InstanceID()
+    }
+
+    return stats
 }
 
 // getClientsPerUser returns the distribution of clients per user
@@ -263,6 +516,19 @@ func (h *Hub) getClientsPerUser() map[string]int {
     return distribution
 }
 
+// snapshotClients returns the registered clients, for work done outside the lock
+func (h *Hub) snapshotClients() []*Client {
+    h.mutex.RLock()
+    defer h.mutex.RUnlock()
+
+    clients := make([]*Client, 0, len(h.clients))
+    for client := range h.clients {
+        clients = append(clients, client)
+    }
+
+    return clients
+}
+
 // GetConnectedUsers returns a list of connected user IDs
 func (h *Hub) GetConnectedUsers() []uuid.UUID {
     h.mutex.RLock()
@@ -285,6 +551,61 @@ func (h *Hub) IsUserConnected(userID uuid.UUID) bool {
     return exists && len(clients) > 0
 }
 
+// IsUserOnline checks if a user is connected to this or any other instance
+func (h *Hub) IsUserOnline(ctx context.Context, userID uuid.UUID) bool {
+    if h.IsUserConnected(userID) {
+        return true
+    }
+    if h.cluster == nil {
+        return false
+    }
+
+    online, err := h.cluster.IsUserOnline(ctx, userID)
+    if err != nil {
+        h.logger.Warn("Failed to check cluster presence",
+            zap.String("user_id", userID.String()),
+            zap.Error(err),
+        )
+        return false
+    }
+    return online
+}
+
+// OnlineUsers checks which of the given users are connected to this or any
+// other instance
+func (h *Hub) OnlineUsers(ctx context.Context, userIDs []uuid.UUID) map[uuid.UUID]bool {
+    online := make(map[uuid.UUID]bool, len(userIDs))
+    var remote []uuid.UUID
+
+    h.mutex.RLock()
+    for _, userID := range userIDs {
+        if len(h.userClients[userID]) > 0 {
+            online[userID] = true
+        } else {
+            remote = append(remote, userID)
+        }
+    }
+    h.mutex.RUnlock()
+
+    if h.cluster == nil || len(remote) == 0 {
+        return online
+    }
+
+    elsewhere, err := h.cluster.onlineElsewhere(ctx, remote)
+    if err != nil {
+        h.logger.Warn("Failed to check cluster presence",
+            zap.Int("users", len(remote)),
+            zap.Error(err),
+        )
+        return online
+    }
+    for userID := range elsewhere {
+        online[userID] = true
+    }
+
+    return online
+}
+
 // GetUserClientCount returns the number of clients for a user
 func (h *Hub) GetUserClientCount(userID uuid.UUID) int {
     h.mutex.RLock()
@@ -301,17 +622,33 @@ func (h *Hub) GetUserClientCount(userID uuid.UUID) int {
 func (h *Hub) Shutdown() {
     h.logger.Info("Shutting down WebSocket hub")
 
+    if h.live != nil {
+        h.live.Stop()
+    }
+    if h.revalidator != nil {
+        h.revalidator.Stop()
+    }
+    if h.presence != nil {
+        h.presence.Stop()
+    }
+    if h.cluster != nil {
+        h.cluster.Stop()
+    }
+
     h.mutex.Lock()
     defer h.mutex.Unlock()
 
     // Close all client connections
     for client := range h.clients {
-        close(client.send)
+        client.closeSend()
     }
 
     // Clear all mappings
     h.clients = make(map[*Client]bool)
     h.userClients = make(map[uuid.UUID][]*Client)
+    h.following = make(map[uuid.UUID]map[uuid.UUID]bool)
+    h.followerIndex = make(map[uuid.UUID]map[uuid.UUID]bool)
+    h.topicClients = make(map[Topic]map[*Client]bool)
 
     h.logger.Info("WebSocket hub shutdown complete")
 }
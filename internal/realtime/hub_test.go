@@ -0,0 +1,365 @@
+package realtime
+
+import (
+    "context"
+    "encoding/json"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/testutil"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+    "github.com/Abhiro0p/stories-backend/pkg/metrics"
+)
+
+var (
+    collectorOnce sync.Once
+    collector     *metrics.Collector
+)
+
+// testCollector returns the package's metrics collector. Metrics register
+// globally, so every test shares one; compare counters before and after.
+func testCollector() *metrics.Collector {
+    collectorOnce.Do(func() {
+        collector = metrics.NewCollector()
+    })
+    return collector
+}
+
+// counterValue reads a counter's current value
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+    t.Helper()
+
+    return testutil.ToFloat64(counter)
+}
+
+// staticFollowers resolves follows from a fixed map
+type staticFollowers map[uuid.UUID][]uuid.UUID
+
+func (f staticFollowers) GetFollowingIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+    return f[userID], nil
+}
+
+// newTestHub creates a hub with no Redis, database or cluster behind it. Tests
+// call the hub's handlers directly instead of running its loop.
+func newTestHub(t *testing.T, followers FollowerResolver, cfg config.RealtimeConfig) *Hub {
+    t.Helper()
+
+    return NewHub(followers, nil, nil, nil, nil, cfg, zap.NewNop())
+}
+
+// newTestUser creates a user with a random ID
+func newTestUser(name string) *models.User {
+    return &models.User{
+        ID:       uuid.New(),
+        Username: name,
+    }
+}
+
+// connect registers a client for the user and discards its welcome event
+func connect(t *testing.T, hub *Hub, user *models.User) *Client {
+    t.Helper()
+
+    client := NewClient(hub, nil, user, "", zap.NewNop())
+    hub.resolveFollowing(client)
+    hub.registerClient(client)
+    received(t, client)
+
+    return client
+}
+
+// received returns the events waiting in a client's send buffer
+func received(t *testing.T, client *Client) []*Event {
+    t.Helper()
+
+    var events []*Event
+    for {
+        select {
+        case data := <-client.send:
+            var event Event
+            if err := json.Unmarshal(data, &event); err != nil {
+                t.Fatalf("failed to decode queued event: %v", err)
+            }
+            events = append(events, &event)
+        default:
+            return events
+        }
+    }
+}
+
+// receivedTypes returns the types of the events waiting for a client
+func receivedTypes(t *testing.T, client *Client) []EventType {
+    t.Helper()
+
+    var types []EventType
+    for _, event := range received(t, client) {
+        types = append(types, event.Type)
+    }
+    return types
+}
+
+// receivedLow returns the low-priority events queued for a client, which wait
+// outside its send buffer
+func receivedLow(t *testing.T, client *Client) []*Event {
+    t.Helper()
+
+    client.queueMu.Lock()
+    queued := client.low
+    client.low = nil
+    client.queueMu.Unlock()
+
+    var events []*Event
+    for _, message := range queued {
+        var event Event
+        if err := json.Unmarshal(message.data, &event); err != nil {
+            t.Fatalf("failed to decode queued event: %v", err)
+        }
+        events = append(events, &event)
+    }
+    return events
+}
+
+// newFlowControlHub creates a test hub that records flow control metrics
+func newFlowControlHub(cfg config.RealtimeConfig) *Hub {
+    return NewHub(nil, nil, nil, nil, testCollector(), cfg, zap.NewNop())
+}
+
+// typingTestEvent is a typing event as a client relays it
+func typingTestEvent(userID, conversationID uuid.UUID, isTyping bool) *Event {
+    return NewEvent(EventTyping, map[string]interface{}{
+        "user_id":         userID,
+        "conversation_id": conversationID,
+        "is_typing":       isTyping,
+    })
+}
+
+// evicted returns why a client was disconnected, or "" if it wasn't
+func evicted(client *Client) string {
+    select {
+    case <-client.done:
+        return client.evictReason
+    default:
+        return ""
+    }
+}
+
+func TestTokenBucket(t *testing.T) {
+    if bucket := newTokenBucket(0, 10); bucket != nil || !bucket.allow() {
+        t.Fatal("a bucket without a rate should allow everything")
+    }
+
+    bucket := newTokenBucket(10, 3)
+    for i := 0; i < 3; i++ {
+        if !bucket.allow() {
+            t.Fatalf("message %d of the burst was limited", i+1)
+        }
+    }
+    if bucket.allow() {
+        t.Fatal("message over the burst was allowed")
+    }
+
+    // 200ms at 10 a second refills two tokens
+    bucket.last = bucket.last.Add(-200 * time.Millisecond)
+    for i := 0; i < 2; i++ {
+        if !bucket.allow() {
+            t.Fatalf("refilled message %d was limited", i+1)
+        }
+    }
+    if bucket.allow() {
+        t.Fatal("message over the refill was allowed")
+    }
+
+    // Tokens never build up past the burst
+    bucket.last = bucket.last.Add(-time.Hour)
+    allowed := 0
+    for bucket.allow() {
+        allowed++
+    }
+    if allowed != 3 {
+        t.Errorf("allowed %d messages after a long pause, want the burst of 3", allowed)
+    }
+}
+
+func TestRateLimitedClientIsWarnedThenEvicted(t *testing.T) {
+    hub := newFlowControlHub(config.RealtimeConfig{InboundRate: 1, InboundBurst: 1})
+    client := connect(t, hub, newTestUser("user"))
+
+    limited := counterValue(t, testCollector().RealtimeInboundLimited)
+    evictions := counterValue(t, testCollector().RealtimeClientsEvicted.WithLabelValues(evictInboundRate))
+
+    for i := 0; i < maxRateViolations-1; i++ {
+        client.rateLimited()
+    }
+
+    // The client is told once, not for every dropped message
+    events := received(t, client)
+    if len(events) != 1 || events[0].Type != EventError || events[0].Payload["error"] != "rate_limited" {
+        t.Fatalf("got %v, want one rate_limited error", events)
+    }
+    if reason := evicted(client); reason != "" {
+        t.Fatalf("client evicted for %s before the violation limit", reason)
+    }
+
+    client.rateLimited()
+    if reason := evicted(client); reason != evictInboundRate {
+        t.Errorf("evicted for %q, want %s", reason, evictInboundRate)
+    }
+
+    if got := counterValue(t, testCollector().RealtimeInboundLimited) - limited; got != maxRateViolations {
+        t.Errorf("counted %v limited messages, want %d", got, maxRateViolations)
+    }
+    if got := counterValue(t, testCollector().RealtimeClientsEvicted.WithLabelValues(evictInboundRate)) - evictions; got != 1 {
+        t.Errorf("counted %v evictions, want 1", got)
+    }
+}
+
+func TestLowPriorityEventsCoalesce(t *testing.T) {
+    hub := newFlowControlHub(config.RealtimeConfig{})
+    client := connect(t, hub, newTestUser("user"))
+
+    story := uuid.New()
+    otherStory := uuid.New()
+    typist := uuid.New()
+    conversation := uuid.New()
+    friend := map[string]interface{}{"id": uuid.NewString()}
+
+    coalesced := counterValue(t, testCollector().RealtimeEventsCoalesced.WithLabelValues(string(EventStoryCounters)))
+
+    client.Send(StoryCountersEvent(story, &StoryCounters{Views: 1}))
+    client.Send(StoryCountersEvent(otherStory, &StoryCounters{Views: 7}))
+    client.Send(StoryCountersEvent(story, &StoryCounters{Views: 2}))
+    client.Send(typingTestEvent(typist, conversation, true))
+    client.Send(typingTestEvent(typist, conversation, false))
+    client.Send(UserOnlineEvent(friend))
+    client.Send(UserOfflineEvent(friend))
+
+    // Only the latest of each is left, in the place of the first
+    low := receivedLow(t, client)
+    if len(low) != 4 {
+        t.Fatalf("got %d queued events, want 4", len(low))
+    }
+    want := []struct {
+        eventType EventType
+        key       string
+        value     interface{}
+    }{
+        {EventStoryCounters, "views", float64(2)},
+        {EventStoryCounters, "views", float64(7)},
+        {EventTyping, "is_typing", false},
+        {EventUserOffline, "", nil},
+    }
+    for i, w := range want {
+        if low[i].Type != w.eventType {
+            t.Errorf("event %d is %s, want %s", i, low[i].Type, w.eventType)
+            continue
+        }
+        if w.key != "" && low[i].Payload[w.key] != w.value {
+            t.Errorf("event %d %s = %v, want %v", i, w.key, low[i].Payload[w.key], w.value)
+        }
+    }
+
+    if got := counterValue(t, testCollector().RealtimeEventsCoalesced.WithLabelValues(string(EventStoryCounters))) - coalesced; got != 1 {
+        t.Errorf("counted %v coalesced counters, want 1", got)
+    }
+
+    // High-priority events are never coalesced
+    client.Send(NewEvent(EventNotification, map[string]interface{}{"story_id": story}))
+    client.Send(NewEvent(EventNotification, map[string]interface{}{"story_id": story}))
+    if types := receivedTypes(t, client); len(types) != 2 {
+        t.Errorf("got %v, want both notifications", types)
+    }
+}
+
+func TestLowPriorityEventsDroppedWhenQueueFull(t *testing.T) {
+    hub := newFlowControlHub(config.RealtimeConfig{LowPriorityBuffer: 2})
+    client := connect(t, hub, newTestUser("user"))
+
+    dropped := counterValue(t, testCollector().RealtimeEventsDropped.WithLabelValues(PriorityLow.String(), dropLowBufferFull))
+
+    for i := 0; i < 3; i++ {
+        client.Send(typingTestEvent(uuid.New(), uuid.New(), true))
+    }
+
+    if low := receivedLow(t, client); len(low) != 2 {
+        t.Errorf("got %d queued events, want 2", len(low))
+    }
+    if got := counterValue(t, testCollector().RealtimeEventsDropped.WithLabelValues(PriorityLow.String(), dropLowBufferFull)) - dropped; got != 1 {
+        t.Errorf("counted %v dropped events, want 1", got)
+    }
+
+    // Falling behind on low-priority events isn't a reason to disconnect
+    if reason := evicted(client); reason != "" {
+        t.Errorf("client evicted for %s", reason)
+    }
+}
+
+func TestFullSendBufferEvictsClient(t *testing.T) {
+    hub := newFlowControlHub(config.RealtimeConfig{SendBuffer: 2})
+    client := connect(t, hub, newTestUser("user"))
+
+    dropped := counterValue(t, testCollector().RealtimeEventsDropped.WithLabelValues(PriorityHigh.String(), evictSendBufferFull))
+    evictions := counterValue(t, testCollector().RealtimeClientsEvicted.WithLabelValues(evictSendBufferFull))
+
+    for i := 0; i < 2; i++ {
+        client.Send(NewEvent(EventNotification, nil))
+    }
+    if reason := evicted(client); reason != "" {
+        t.Fatalf("client evicted for %s with room in its buffer", reason)
+    }
+
+    // High-priority events aren't dropped quietly: the client reconnects and resumes
+    client.Send(NewEvent(EventNotification, nil))
+    client.Send(NewEvent(EventNotification, nil))
+    if reason := evicted(client); reason != evictSendBufferFull {
+        t.Errorf("evicted for %q, want %s", reason, evictSendBufferFull)
+    }
+
+    if got := counterValue(t, testCollector().RealtimeEventsDropped.WithLabelValues(PriorityHigh.String(), evictSendBufferFull)) - dropped; got != 2 {
+        t.Errorf("counted %v dropped events, want 2", got)
+    }
+    if got := counterValue(t, testCollector().RealtimeClientsEvicted.WithLabelValues(evictSendBufferFull)) - evictions; got != 1 {
+        t.Errorf("counted %v evictions, want 1", got)
+    }
+}
+
+func TestEvictionRacesWithSendsAndUnregister(t *testing.T) {
+    // Run with -race: sends, evictions and the hub unregistering the client
+    // used to race on closing the send buffer
+    hub := newFlowControlHub(config.RealtimeConfig{SendBuffer: 4, LowPriorityBuffer: 4})
+    user := newTestUser("user")
+    client := connect(t, hub, user)
+
+    var wg sync.WaitGroup
+    for i := 0; i < 8; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for j := 0; j < 50; j++ {
+                client.Send(NewEvent(EventNotification, nil))
+                client.Send(typingTestEvent(user.ID, uuid.New(), true))
+            }
+        }()
+    }
+    for i := 0; i < 4; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            client.evict(evictSendBufferFull)
+            hub.unregisterClient(client)
+        }()
+    }
+    wg.Wait()
+
+    if reason := evicted(client); reason != evictSendBufferFull {
+        t.Errorf("evicted for %q, want %s", reason, evictSendBufferFull)
+    }
+
+    // Sends after the client is gone are discarded rather than panicking
+    client.Send(NewEvent(EventNotification, nil))
+    client.Send(typingTestEvent(user.ID, uuid.New(), true))
+}
@@ -0,0 +1,149 @@
+package realtime
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "time"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+const (
+    // counterInterval spaces out the counter updates sent for one story
+    counterInterval = time.Second
+
+    // liveTimeout bounds the database calls for one view or counter update
+    liveTimeout = 5 * time.Second
+)
+
+// StoryCounters are a story's live view and reaction counts
+type StoryCounters struct {
+    AuthorID    uuid.UUID
+    Views       int
+    UniqueViews int
+    Reactions   int
+}
+
+// ErrViewForbidden is returned by StoryActivity.RecordView when the viewer may
+// not see the story
+var ErrViewForbidden = errors.New("not allowed to view this story")
+
+// StoryActivity records story views and loads story counters with the same
+// rules and dedup the REST API uses
+type StoryActivity interface {
+    RecordView(ctx context.Context, viewer *models.User, storyID uuid.UUID, ipAddress, userAgent string) error
+    StoryCounters(ctx context.Context, storyID uuid.UUID) (*StoryCounters, error)
+}
+
+// LiveStories records story views sent over websockets and pushes counter
+// updates to story authors and story topic subscribers.
+//
+// Views and reactions only mark a story's counters as changed; changed
+// counters are sent at most once per counterInterval per story, so a burst of
+// views becomes a single update.
+type LiveStories struct {
+    hub      *Hub
+    activity StoryActivity
+    logger   *zap.Logger
+
+    changedMu sync.Mutex
+    changed   map[uuid.UUID]struct{}
+
+    stopCh chan struct{}
+}
+
+// NewLiveStories creates the live story service and attaches it to the hub. It
+// must be called before connections are accepted.
+func NewLiveStories(hub *Hub, activity StoryActivity, logger *zap.Logger) *LiveStories {
+    live := &LiveStories{
+        hub:      hub,
+        activity: activity,
+        logger:   logger.With(zap.String("component", "realtime_live_stories")),
+        changed:  make(map[uuid.UUID]struct{}),
+        stopCh:   make(chan struct{}),
+    }
+    hub.live = live
+
+    return live
+}
+
+// Start starts sending counter updates
+func (l *LiveStories) Start() {
+    l.logger.Info("Starting live story counters", zap.Duration("interval", counterInterval))
+
+    go l.run()
+}
+
+// Stop stops sending counter updates
+func (l *LiveStories) Stop() {
+    close(l.stopCh)
+}
+
+// CountersChanged marks a story's counters as changed
+func (l *LiveStories) CountersChanged(storyID uuid.UUID) {
+    l.changedMu.Lock()
+    defer l.changedMu.Unlock()
+
+    l.changed[storyID] = struct{}{}
+}
+
+// recordView records a story view sent by a client
+func (l *LiveStories) recordView(client *Client, storyID uuid.UUID) error {
+    ctx, cancel := context.WithTimeout(context.Background(), liveTimeout)
+    defer cancel()
+
+    return l.activity.RecordView(ctx, client.User, storyID, client.ipAddress, client.userAgent)
+}
+
+// run sends the changed counters once per interval
+func (l *LiveStories) run() {
+    ticker := time.NewTicker(counterInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-l.stopCh:
+            return
+
+        case <-ticker.C:
+            l.flush()
+        }
+    }
+}
+
+// flush sends the current counters of every story changed since the last flush
+func (l *LiveStories) flush() {
+    l.changedMu.Lock()
+    changed := l.changed
+    l.changed = make(map[uuid.UUID]struct{}, len(changed))
+    l.changedMu.Unlock()
+
+    for storyID := range changed {
+        l.send(storyID)
+    }
+}
+
+// send pushes a story's counters to its author and its topic subscribers
+func (l *LiveStories) send(storyID uuid.UUID) {
+    ctx, cancel := context.WithTimeout(context.Background(), liveTimeout)
+    defer cancel()
+
+    counters, err := l.activity.StoryCounters(ctx, storyID)
+    if err != nil {
+        l.logger.Warn("Failed to get live story counters",
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
+        return
+    }
+
+    // Counters are a snapshot, so they aren't kept for replay; a client that
+    // reconnects gets the next update
+    event := StoryCountersEvent(storyID, counters)
+    l.hub.sendLive([]uuid.UUID{counters.AuthorID}, event)
+    l.hub.PublishToTopic(StoryTopic(storyID), event)
+}
@@ -0,0 +1,61 @@
+package realtime
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// fakeActivity returns fixed counters for every story
+type fakeActivity struct {
+    counters StoryCounters
+}
+
+func (f *fakeActivity) RecordView(ctx context.Context, viewer *models.User, storyID uuid.UUID, ipAddress, userAgent string) error {
+    return nil
+}
+
+func (f *fakeActivity) StoryCounters(ctx context.Context, storyID uuid.UUID) (*StoryCounters, error) {
+    counters := f.counters
+    return &counters, nil
+}
+
+func TestLiveCountersAreNotReplayed(t *testing.T) {
+    hub := newReplayHub(t, nil, config.RealtimeConfig{})
+    author := newTestUser("author")
+    client := connectLive(t, hub, author)
+    live := NewLiveStories(hub, &fakeActivity{counters: StoryCounters{AuthorID: author.ID, Views: 3}}, zap.NewNop())
+
+    // More updates than the replay buffer holds
+    storyID := uuid.New()
+    for i := 0; i < 150; i++ {
+        live.send(storyID)
+    }
+
+    var counters []*Event
+    deadline := time.Now().Add(eventTimeout)
+    for len(counters) == 0 && time.Now().Before(deadline) {
+        counters = receivedLow(t, client)
+        time.Sleep(10 * time.Millisecond)
+    }
+    if len(counters) == 0 {
+        t.Fatal("author got no counters")
+    }
+    if event := counters[0]; event.Type != EventStoryCounters || event.Seq != 0 {
+        t.Errorf("got %s event with seq %d, want unsequenced counters", event.Type, event.Seq)
+    }
+
+    events, _, err := hub.replay.Since(context.Background(), author.ID, 0)
+    if err != nil {
+        t.Fatalf("failed to get replay: %v", err)
+    }
+    if len(events) != 0 {
+        t.Errorf("replay holds %d events, want none", len(events))
+    }
+}
@@ -0,0 +1,343 @@
+package realtime
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+const (
+    defaultActivityInterval = time.Minute
+
+    // statusQueueSize bounds the online and offline changes waiting to be announced
+    statusQueueSize = 1024
+
+    // presenceTimeout bounds the Redis and database calls for one change or lookup
+    presenceTimeout = 5 * time.Second
+)
+
+// statusChange records a user's first connection to or last disconnection from
+// this instance
+type statusChange struct {
+    user   *models.User
+    online bool
+    at     time.Time
+}
+
+// Presence records when users were last active and tells their mutual follows
+// when they come online or go offline.
+//
+// Activity comes from websocket connections and authenticated API requests.
+// Both online status and last active time are only shown to the user themselves
+// and to mutual follows, and never for users who hide their activity status.
+type Presence struct {
+    hub           *Hub
+    presenceStore storage.PresenceStore
+    followStore   storage.FollowStore
+    userStore     storage.UserStore
+    interval      time.Duration
+    logger        *zap.Logger
+
+    touchedMu sync.Mutex
+    touched   map[uuid.UUID]time.Time
+
+    changes chan statusChange
+    stopCh  chan struct{}
+}
+
+// NewPresence creates a presence service and attaches it to the hub. It must
+// be called before the hub starts running.
+func NewPresence(hub *Hub, presenceStore storage.PresenceStore, followStore storage.FollowStore, userStore storage.UserStore, cfg config.RealtimeConfig, logger *zap.Logger) *Presence {
+    interval := cfg.ActivityInterval
+    if interval <= 0 {
+        interval = defaultActivityInterval
+    }
+
+    presence := &Presence{
+        hub:           hub,
+        presenceStore: presenceStore,
+        followStore:   followStore,
+        userStore:     userStore,
+        interval:      interval,
+        logger:        logger.With(zap.String("component", "realtime_presence")),
+        touched:       make(map[uuid.UUID]time.Time),
+        changes:       make(chan statusChange, statusQueueSize),
+        stopCh:        make(chan struct{}),
+    }
+    hub.presence = presence
+
+    return presence
+}
+
+// Start starts announcing online and offline changes
+func (p *Presence) Start() {
+    p.logger.Info("Starting presence service", zap.Duration("activity_interval", p.interval))
+
+    go p.run()
+}
+
+// Stop stops announcing online and offline changes
+func (p *Presence) Stop() {
+    p.logger.Info("Stopping presence service")
+    close(p.stopCh)
+}
+
+// Touch records that a user is active. Writes are spaced at least the activity
+// interval apart per user, so it is cheap enough to call on every request.
+func (p *Presence) Touch(userID uuid.UUID) {
+    now := time.Now()
+    if !p.shouldTouch(userID, now) {
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+    defer cancel()
+
+    p.writeLastActive(ctx, userID, now)
+}
+
+// Lookup gets the presence of the users the viewer is allowed to see. Users
+// whose presence is hidden from the viewer are left out of the result.
+func (p *Presence) Lookup(ctx context.Context, viewer *models.User, users []*models.User) (map[uuid.UUID]*models.UserPresence, error) {
+    ids, err := p.visibleTo(ctx, viewer, users)
+    if err != nil {
+        return nil, err
+    }
+
+    presences := make(map[uuid.UUID]*models.UserPresence, len(ids))
+    if len(ids) == 0 {
+        return presences, nil
+    }
+
+    lastActive, err := p.presenceStore.GetLastActive(ctx, ids)
+    if err != nil {
+        return nil, err
+    }
+    online := p.hub.OnlineUsers(ctx, ids)
+
+    now := time.Now()
+    for _, userID := range ids {
+        presence := &models.UserPresence{
+            UserID:   userID,
+            IsOnline: online[userID],
+        }
+        if presence.IsOnline {
+            presence.LastActiveAt = &now
+        } else if at, ok := lastActive[userID]; ok {
+            presence.LastActiveAt = &at
+        }
+        presences[userID] = presence
+    }
+
+    return presences, nil
+}
+
+// Fill sets the online status and last active time of the users the viewer is
+// allowed to see. Failures are logged and leave the users unchanged.
+func (p *Presence) Fill(ctx context.Context, viewer *models.User, users ...*models.User) {
+    presences, err := p.Lookup(ctx, viewer, users)
+    if err != nil {
+        p.logger.Warn("Failed to look up presence",
+            zap.String("viewer_id", viewer.ID.String()),
+            zap.Int("users", len(users)),
+            zap.Error(err),
+        )
+        return
+    }
+
+    for _, user := range users {
+        if presence, ok := presences[user.ID]; ok {
+            user.IsOnline = presence.IsOnline
+            user.LastActiveAt = presence.LastActiveAt
+        }
+    }
+}
+
+// visibleTo returns the IDs of the users whose presence the viewer may see
+func (p *Presence) visibleTo(ctx context.Context, viewer *models.User, users []*models.User) ([]uuid.UUID, error) {
+    var ids []uuid.UUID
+    var mutuals map[uuid.UUID]bool
+
+    for _, user := range users {
+        if user.ID == viewer.ID {
+            ids = append(ids, user.ID)
+            continue
+        }
+        if user.HideActivityStatus {
+            continue
+        }
+
+        // Only loaded once someone else's presence is asked for
+        if mutuals == nil {
+            mutualIDs, err := p.followStore.GetMutualFollowIDs(ctx, viewer.ID)
+            if err != nil {
+                return nil, err
+            }
+            mutuals = make(map[uuid.UUID]bool, len(mutualIDs))
+            for _, mutualID := range mutualIDs {
+                mutuals[mutualID] = true
+            }
+        }
+        if mutuals[user.ID] {
+            ids = append(ids, user.ID)
+        }
+    }
+
+    return ids, nil
+}
+
+// statusChanged queues an online or offline change without blocking the hub.
+// A dropped change only means one announcement is missed.
+func (p *Presence) statusChanged(user *models.User, online bool) {
+    select {
+    case p.changes <- statusChange{user: user, online: online, at: time.Now()}:
+    default:
+        p.logger.Warn("Presence queue full, dropping status change",
+            zap.String("user_id", user.ID.String()),
+            zap.Bool("online", online),
+        )
+    }
+}
+
+// run announces status changes and forgets old activity writes
+func (p *Presence) run() {
+    ticker := time.NewTicker(p.interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-p.stopCh:
+            return
+
+        case change := <-p.changes:
+            p.announce(change)
+
+        case <-ticker.C:
+            p.pruneTouched()
+        }
+    }
+}
+
+// announce records a connect or disconnect as activity and tells the user's
+// mutual follows, unless the user is still online somewhere else
+func (p *Presence) announce(change statusChange) {
+    ctx, cancel := context.WithTimeout(context.Background(), presenceTimeout)
+    defer cancel()
+
+    p.markTouched(change.user.ID, change.at)
+    p.writeLastActive(ctx, change.user.ID, change.at)
+
+    // The client's copy of the user is from when it connected, and the user may
+    // have hidden their activity status since. Nothing is announced if the
+    // current settings can't be loaded.
+    user, err := p.userStore.GetByID(ctx, change.user.ID)
+    if err != nil {
+        p.logger.Warn("Failed to load user for presence",
+            zap.String("user_id", change.user.ID.String()),
+            zap.Error(err),
+        )
+        return
+    }
+    if user.HideActivityStatus {
+        return
+    }
+
+    // The user may have reconnected or left again while this was queued
+    if p.hub.IsUserConnected(user.ID) != change.online {
+        return
+    }
+
+    // Another instance has already announced them and still has them connected
+    if p.hub.cluster != nil {
+        elsewhere, err := p.hub.cluster.onlineElsewhere(ctx, []uuid.UUID{user.ID})
+        if err != nil {
+            p.logger.Warn("Failed to check cluster presence",
+                zap.String("user_id", user.ID.String()),
+                zap.Error(err),
+            )
+        } else if elsewhere[user.ID] {
+            return
+        }
+    }
+
+    mutualIDs, err := p.followStore.GetMutualFollowIDs(ctx, user.ID)
+    if err != nil {
+        p.logger.Error("Failed to get mutual follows for presence",
+            zap.String("user_id", user.ID.String()),
+            zap.Error(err),
+        )
+        return
+    }
+
+    summary := map[string]interface{}{
+        "id":              user.ID,
+        "username":        user.Username,
+        "profile_picture": user.ProfilePicture,
+    }
+
+    var event *Event
+    if change.online {
+        event = UserOnlineEvent(summary)
+    } else {
+        summary["last_active_at"] = change.at
+        event = UserOfflineEvent(summary)
+    }
+    p.hub.SendToUsers(mutualIDs, event)
+
+    p.logger.Debug("Announced presence change",
+        zap.String("user_id", user.ID.String()),
+        zap.Bool("online", change.online),
+        zap.Int("mutual_follows", len(mutualIDs)),
+    )
+}
+
+// writeLastActive stores a user's last active time
+func (p *Presence) writeLastActive(ctx context.Context, userID uuid.UUID, at time.Time) {
+    if err := p.presenceStore.Touch(ctx, userID, at); err != nil {
+        p.logger.Warn("Failed to record last active time",
+            zap.String("user_id", userID.String()),
+            zap.Error(err),
+        )
+    }
+}
+
+// shouldTouch reports whether a user's last write is older than the activity
+// interval, recording a new write if so
+func (p *Presence) shouldTouch(userID uuid.UUID, now time.Time) bool {
+    p.touchedMu.Lock()
+    defer p.touchedMu.Unlock()
+
+    if last, ok := p.touched[userID]; ok && now.Sub(last) < p.interval {
+        return false
+    }
+    p.touched[userID] = now
+    return true
+}
+
+// markTouched records a write made outside Touch
+func (p *Presence) markTouched(userID uuid.UUID, at time.Time) {
+    p.touchedMu.Lock()
+    defer p.touchedMu.Unlock()
+
+    p.touched[userID] = at
+}
+
+// pruneTouched forgets writes old enough that the next Touch writes anyway
+func (p *Presence) pruneTouched() {
+    p.touchedMu.Lock()
+    defer p.touchedMu.Unlock()
+
+    cutoff := time.Now().Add(-p.interval)
+    for userID, at := range p.touched {
+        if at.Before(cutoff) {
+            delete(p.touched, userID)
+        }
+    }
+}
@@ -0,0 +1,205 @@
+package realtime
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// fakePresenceStore has no recorded activity
+type fakePresenceStore struct{}
+
+func (fakePresenceStore) Touch(ctx context.Context, userID uuid.UUID, at time.Time) error {
+    return nil
+}
+
+func (fakePresenceStore) GetLastActive(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+    return map[uuid.UUID]time.Time{}, nil
+}
+
+// fakeMutuals resolves mutual follows from a fixed map. Methods a test doesn't
+// use panic through the embedded nil interface.
+type fakeMutuals struct {
+    storage.FollowStore
+
+    mutuals map[uuid.UUID][]uuid.UUID
+}
+
+func (f *fakeMutuals) GetMutualFollowIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+    return f.mutuals[userID], nil
+}
+
+// befriend makes two users follow each other
+func (f *fakeMutuals) befriend(a, b uuid.UUID) {
+    if f.mutuals == nil {
+        f.mutuals = make(map[uuid.UUID][]uuid.UUID)
+    }
+    f.mutuals[a] = append(f.mutuals[a], b)
+    f.mutuals[b] = append(f.mutuals[b], a)
+}
+
+// fakeUsers returns copies of stored users, as the database would
+type fakeUsers struct {
+    storage.UserStore
+
+    users map[uuid.UUID]*models.User
+}
+
+func (f *fakeUsers) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+    user, ok := f.users[id]
+    if !ok {
+        return nil, storage.ErrNotFound
+    }
+    stored := *user
+    return &stored, nil
+}
+
+// add stores copies of users
+func (f *fakeUsers) add(users ...*models.User) {
+    if f.users == nil {
+        f.users = make(map[uuid.UUID]*models.User)
+    }
+    for _, user := range users {
+        stored := *user
+        f.users[user.ID] = &stored
+    }
+}
+
+// waitForLow waits for low-priority events to be queued for a client
+func waitForLow(t *testing.T, client *Client) []*Event {
+    t.Helper()
+
+    deadline := time.Now().Add(eventTimeout)
+    for time.Now().Before(deadline) {
+        if events := receivedLow(t, client); len(events) > 0 {
+            return events
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    t.Fatal("timed out waiting for low-priority events")
+    return nil
+}
+
+// announcedUser returns the ID of the user in a presence event
+func announcedUser(t *testing.T, event *Event) string {
+    t.Helper()
+
+    user, ok := event.Payload["user"].(map[string]interface{})
+    if !ok {
+        t.Fatalf("%s event has no user: %v", event.Type, event.Payload)
+    }
+    id, _ := user["id"].(string)
+    return id
+}
+
+func TestPresenceLookupShowsMutualsOnly(t *testing.T) {
+    hub := newTestHub(t, nil, config.RealtimeConfig{})
+    follows := &fakeMutuals{}
+    presence := NewPresence(hub, fakePresenceStore{}, follows, &fakeUsers{}, config.RealtimeConfig{}, zap.NewNop())
+
+    viewer := newTestUser("viewer")
+    mutual := newTestUser("mutual")
+    stranger := newTestUser("stranger")
+    hidden := newTestUser("hidden")
+    viewer.HideActivityStatus = true
+    hidden.HideActivityStatus = true
+    follows.befriend(viewer.ID, mutual.ID)
+    follows.befriend(viewer.ID, hidden.ID)
+
+    for _, user := range []*models.User{viewer, mutual, stranger, hidden} {
+        connect(t, hub, user)
+    }
+
+    presences, err := presence.Lookup(context.Background(), viewer, []*models.User{viewer, mutual, stranger, hidden})
+    if err != nil {
+        t.Fatalf("failed to look up presence: %v", err)
+    }
+
+    // Users always see their own presence, even when they hide it from others
+    for _, user := range []*models.User{viewer, mutual} {
+        if got, ok := presences[user.ID]; !ok || !got.IsOnline {
+            t.Errorf("%s: want visible and online, got %+v", user.Username, got)
+        }
+    }
+    for _, user := range []*models.User{stranger, hidden} {
+        if got, ok := presences[user.ID]; ok {
+            t.Errorf("%s: want hidden, got %+v", user.Username, got)
+        }
+    }
+}
+
+func TestPresenceAnnouncesToMutualsOnly(t *testing.T) {
+    hub := newTestHub(t, nil, config.RealtimeConfig{})
+    follows := &fakeMutuals{}
+    users := &fakeUsers{}
+    presence := NewPresence(hub, fakePresenceStore{}, follows, users, config.RealtimeConfig{}, zap.NewNop())
+    go hub.Run()
+
+    user := newTestUser("user")
+    mutual := newTestUser("mutual")
+    stranger := newTestUser("stranger")
+    users.add(user, mutual, stranger)
+    follows.befriend(user.ID, mutual.ID)
+
+    mutualClient := connectLive(t, hub, mutual)
+    strangerClient := connectLive(t, hub, stranger)
+    connectLive(t, hub, user)
+
+    presence.announce(statusChange{user: user, online: true, at: time.Now()})
+
+    events := waitForLow(t, mutualClient)
+    if len(events) != 1 || events[0].Type != EventUserOnline || announcedUser(t, events[0]) != user.ID.String() {
+        t.Fatalf("mutual got %d events, want the user coming online", len(events))
+    }
+
+    // Both clients were sent to in the same pass
+    if events := receivedLow(t, strangerClient); len(events) != 0 {
+        t.Errorf("non-mutual got %d events, want none", len(events))
+    }
+}
+
+func TestPresenceHiddenWhileConnected(t *testing.T) {
+    hub := newTestHub(t, nil, config.RealtimeConfig{})
+    follows := &fakeMutuals{}
+    users := &fakeUsers{}
+    presence := NewPresence(hub, fakePresenceStore{}, follows, users, config.RealtimeConfig{}, zap.NewNop())
+    go hub.Run()
+
+    viewer := newTestUser("viewer")
+    hiding := newTestUser("hiding")
+    visible := newTestUser("visible")
+    follows.befriend(viewer.ID, hiding.ID)
+    follows.befriend(viewer.ID, visible.ID)
+
+    viewerClient := connectLive(t, hub, viewer)
+    connectLive(t, hub, hiding)
+    connectLive(t, hub, visible)
+
+    // The user hides their activity status after connecting, so the client
+    // still holds the old settings
+    hidden := *hiding
+    hidden.HideActivityStatus = true
+    users.add(viewer, &hidden, visible)
+
+    presence.announce(statusChange{user: hiding, online: true, at: time.Now()})
+    presence.announce(statusChange{user: visible, online: true, at: time.Now()})
+
+    // The hub sends in order, so the visible user's announcement arriving means
+    // the hidden user's would have arrived first
+    events := waitForLow(t, viewerClient)
+    for _, event := range events {
+        if announcedUser(t, event) == hiding.ID.String() {
+            t.Errorf("announced %s for a user who hid their activity status", event.Type)
+        }
+    }
+    if len(events) != 1 || announcedUser(t, events[0]) != visible.ID.String() {
+        t.Errorf("got %d events, want only the visible user coming online", len(events))
+    }
+}
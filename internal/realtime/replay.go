@@ -0,0 +1,414 @@
+package realtime
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/redis/go-redis/v9"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+const (
+    defaultReplaySize = 100
+    defaultReplayTTL  = 10 * time.Minute
+
+    // maxReplaySize bounds the events sent to a resuming client at once. A
+    // replay larger than the client's send buffer is replaced by a resync.
+    maxReplaySize = 200
+
+    // replaySeqTTL is how long a user's sequence survives without events. When
+    // it lapses the sequence restarts and old clients are told to resync.
+    replaySeqTTL = 7 * 24 * time.Hour
+
+    // replayTimeout bounds the Redis calls made for one event or one resume
+    replayTimeout = 2 * time.Second
+)
+
+// ReplayBuffer numbers the events sent to each user and keeps the most recent
+// ones in Redis, so a client that reconnects can be sent what it missed.
+//
+// Events fanned out to an author's followers or a topic's subscribers aren't
+// numbered, as most recipients aren't known when they are sent. Instead each
+// fan-out marks its author or topic with the time it was sent, and a client
+// that resumes after a mark newer than its last event is told to resync.
+type ReplayBuffer struct {
+    redisClient *storage.RedisClient
+    size        int
+    ttl         time.Duration
+    logger      *zap.Logger
+}
+
+// NewReplayBuffer creates a new replay buffer
+func NewReplayBuffer(redisClient *storage.RedisClient, cfg config.RealtimeConfig, logger *zap.Logger) *ReplayBuffer {
+    size := cfg.ReplaySize
+    if size <= 0 {
+        size = defaultReplaySize
+    }
+    if size > maxReplaySize {
+        size = maxReplaySize
+    }
+
+    ttl := cfg.ReplayTTL
+    if ttl <= 0 {
+        ttl = defaultReplayTTL
+    }
+
+    return &ReplayBuffer{
+        redisClient: redisClient,
+        size:        size,
+        ttl:         ttl,
+        logger:      logger.With(zap.String("component", "realtime_replay")),
+    }
+}
+
+// Append assigns the user's next sequence number to a copy of the event and
+// stores it for replay
+func (r *ReplayBuffer) Append(ctx context.Context, userID uuid.UUID, event *Event) (*Event, error) {
+    stamped, err := r.AppendMany(ctx, []uuid.UUID{userID}, event)
+    if err != nil {
+        return nil, err
+    }
+    return stamped[0], nil
+}
+
+// AppendMany assigns each user's next sequence number to their own copy of the
+// event and stores the copies for replay. The copies are in the order of userIDs.
+func (r *ReplayBuffer) AppendMany(ctx context.Context, userIDs []uuid.UUID, event *Event) ([]*Event, error) {
+    pipe := r.redisClient.Pipeline()
+    incrs := make([]*redis.IntCmd, len(userIDs))
+    for i, userID := range userIDs {
+        incrs[i] = pipe.Incr(ctx, replaySeqKey(userID))
+    }
+    if _, err := pipe.Exec(ctx); err != nil {
+        return nil, fmt.Errorf("failed to get event sequence: %w", err)
+    }
+
+    now := time.Now().Unix()
+    stamped := make([]*Event, len(userIDs))
+    pipe = r.redisClient.Pipeline()
+    for i, userID := range userIDs {
+        userEvent := event.Clone()
+        userEvent.Seq = incrs[i].Val()
+        if userEvent.Timestamp == 0 {
+            userEvent.Timestamp = now
+        }
+        stamped[i] = userEvent
+
+        data, err := json.Marshal(userEvent)
+        if err != nil {
+            return nil, fmt.Errorf("failed to marshal event: %w", err)
+        }
+
+        // Sequence numbers are the scores, so concurrent appends still replay in order
+        key := replayKey(userID)
+        pipe.ZAdd(ctx, key, redis.Z{Score: float64(userEvent.Seq), Member: data})
+        pipe.ZRemRangeByRank(ctx, key, 0, int64(-r.size-1))
+        pipe.Expire(ctx, key, r.ttl)
+        pipe.Expire(ctx, replaySeqKey(userID), replaySeqTTL)
+    }
+    if _, err := pipe.Exec(ctx); err != nil {
+        // The events still go out live, they just can't be replayed
+        r.logger.Warn("Failed to store events for replay",
+            zap.Int("user_count", len(userIDs)),
+            zap.String("event_type", string(event.Type)),
+            zap.Error(err),
+        )
+    }
+
+    return stamped, nil
+}
+
+// MarkFanout records that an unsequenced event was just sent to the followers
+// or subscribers of each stream. Marks last as long as the replay buffer.
+func (r *ReplayBuffer) MarkFanout(ctx context.Context, streams ...string) error {
+    now := time.Now().UnixMilli()
+
+    pipe := r.redisClient.Pipeline()
+    for _, stream := range streams {
+        pipe.Set(ctx, fanoutKey(stream), now, r.ttl)
+    }
+    if _, err := pipe.Exec(ctx); err != nil {
+        return fmt.Errorf("failed to mark fan-out: %w", err)
+    }
+    return nil
+}
+
+// MissedFanout reports whether any of the streams may have sent an event the
+// user's client missed after it received the event numbered lastSeq. When that
+// event's time is unknown, or older than marks are kept, it assumes it did.
+func (r *ReplayBuffer) MissedFanout(ctx context.Context, userID uuid.UUID, lastSeq int64, streams []string) (bool, error) {
+    if len(streams) == 0 {
+        return false, nil
+    }
+
+    // A client without a sequenced event has no position to compare against
+    if lastSeq <= 0 {
+        return true, nil
+    }
+
+    members, err := r.redisClient.GetClient().ZRangeByScore(ctx, replayKey(userID), &redis.ZRangeBy{
+        Min: strconv.FormatInt(lastSeq, 10),
+        Max: strconv.FormatInt(lastSeq, 10),
+    }).Result()
+    if err != nil {
+        return false, fmt.Errorf("failed to get last event: %w", err)
+    }
+    if len(members) == 0 {
+        return true, nil
+    }
+
+    var last Event
+    if err := json.Unmarshal([]byte(members[0]), &last); err != nil {
+        return false, fmt.Errorf("failed to unmarshal last event: %w", err)
+    }
+
+    // Timestamps are in seconds, so fan-outs in the same second count as missed
+    since := time.Unix(last.Timestamp, 0)
+    if time.Since(since) > r.ttl {
+        return true, nil
+    }
+
+    keys := make([]string, len(streams))
+    for i, stream := range streams {
+        keys[i] = fanoutKey(stream)
+    }
+    marks, err := r.redisClient.GetClient().MGet(ctx, keys...).Result()
+    if err != nil {
+        return false, fmt.Errorf("failed to get fan-out marks: %w", err)
+    }
+
+    for _, mark := range marks {
+        value, ok := mark.(string)
+        if !ok {
+            continue
+        }
+        sentAt, err := strconv.ParseInt(value, 10, 64)
+        if err != nil || sentAt >= since.UnixMilli() {
+            return true, nil
+        }
+    }
+
+    return false, nil
+}
+
+// Since gets the events sent to a user after lastSeq. It returns false if some
+// of them are no longer buffered and the client has to resync instead.
+func (r *ReplayBuffer) Since(ctx context.Context, userID uuid.UUID, lastSeq int64) ([]*Event, bool, error) {
+    current, err := r.redisClient.GetClient().Get(ctx, replaySeqKey(userID)).Int64()
+    if err != nil && err != redis.Nil {
+        return nil, false, fmt.Errorf("failed to get event sequence: %w", err)
+    }
+
+    if lastSeq == current {
+        return nil, true, nil
+    }
+    if lastSeq > current {
+        // The sequence restarted since the client last connected
+        return nil, false, nil
+    }
+
+    members, err := r.redisClient.GetClient().ZRangeByScore(ctx, replayKey(userID), &redis.ZRangeBy{
+        Min: "(" + strconv.FormatInt(lastSeq, 10),
+        Max: "+inf",
+    }).Result()
+    if err != nil {
+        return nil, false, fmt.Errorf("failed to get replay events: %w", err)
+    }
+
+    // Every missed event has to be there; anything trimmed, expired or never
+    // stored leaves a gap
+    if len(members) == 0 {
+        return nil, false, nil
+    }
+
+    events := make([]*Event, 0, len(members))
+    for i, member := range members {
+        var event Event
+        if err := json.Unmarshal([]byte(member), &event); err != nil {
+            return nil, false, fmt.Errorf("failed to unmarshal replay event: %w", err)
+        }
+        if event.Seq != lastSeq+int64(i)+1 {
+            return nil, false, nil
+        }
+        events = append(events, &event)
+    }
+
+    return events, true, nil
+}
+
+// Resume registers a reconnecting client and replays the events sent to its
+// user after lastSeq. If they are no longer buffered, or the authors it follows
+// or the topics it subscribes to with the connection sent events it missed, it
+// is told to resync.
+func (h *Hub) Resume(client *Client, lastSeq int64, topics ...Topic) {
+    if h.replay == nil {
+        h.Register(client)
+        return
+    }
+
+    // Live events are held from registration on, so none fall between the
+    // replay and the live stream
+    client.holdEvents()
+    h.Register(client)
+
+    ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+    defer cancel()
+
+    events, complete, err := h.replay.Since(ctx, client.User.ID, lastSeq)
+    if err != nil {
+        h.logger.Error("Failed to get events for replay",
+            zap.String("user_id", client.User.ID.String()),
+            zap.Int64("last_event_id", lastSeq),
+            zap.Error(err),
+        )
+    }
+
+    if err == nil && complete {
+        // Registration resolved who the client follows
+        streams := make([]string, 0, len(client.following)+len(topics))
+        for _, authorID := range client.following {
+            streams = append(streams, authorFanoutStream(authorID))
+        }
+        for _, topic := range topics {
+            streams = append(streams, topicFanoutStream(topic))
+        }
+
+        missed, missedErr := h.replay.MissedFanout(ctx, client.User.ID, lastSeq, streams)
+        if missedErr != nil {
+            h.logger.Error("Failed to check for missed fan-out events",
+                zap.String("user_id", client.User.ID.String()),
+                zap.Int64("last_event_id", lastSeq),
+                zap.Error(missedErr),
+            )
+        }
+        complete = !missed && missedErr == nil
+    }
+
+    if err != nil || !complete {
+        client.releaseEvents(lastSeq, nil, ResyncRequiredEvent(lastSeq))
+        return
+    }
+
+    h.logger.Debug("Replaying missed events",
+        zap.String("user_id", client.User.ID.String()),
+        zap.Int64("last_event_id", lastSeq),
+        zap.Int("event_count", len(events)),
+    )
+    client.releaseEvents(lastSeq, events, nil)
+}
+
+// replayable reports whether an event is numbered and kept for replay.
+// Low-priority events are superseded by the next one, so they are only
+// delivered live: replaying them would send stale state and push the events
+// replay exists to recover out of the buffer.
+func (e *Event) replayable() bool {
+    return e.Priority() != PriorityLow
+}
+
+// sequence numbers an event for its user and stores it for replay. If Redis is
+// unavailable the event is still sent, just without a sequence number.
+func (h *Hub) sequence(userID uuid.UUID, event *Event) *Event {
+    if h.replay == nil || !event.replayable() {
+        return event
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+    defer cancel()
+
+    stamped, err := h.replay.Append(ctx, userID, event)
+    if err != nil {
+        h.logger.Warn("Failed to sequence event",
+            zap.String("user_id", userID.String()),
+            zap.String("event_type", string(event.Type)),
+            zap.Error(err),
+        )
+        return event
+    }
+    return stamped
+}
+
+// sequenceMany numbers an event for each user. If Redis is unavailable every
+// user gets the event without a sequence number.
+func (h *Hub) sequenceMany(userIDs []uuid.UUID, event *Event) []*Event {
+    events := make([]*Event, len(userIDs))
+    for i := range events {
+        events[i] = event
+    }
+    if h.replay == nil || !event.replayable() {
+        return events
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+    defer cancel()
+
+    stamped, err := h.replay.AppendMany(ctx, userIDs, event)
+    if err != nil {
+        h.logger.Warn("Failed to sequence event",
+            zap.Int("user_count", len(userIDs)),
+            zap.String("event_type", string(event.Type)),
+            zap.Error(err),
+        )
+        return events
+    }
+    return stamped
+}
+
+// markFanout marks the streams an unsequenced event was fanned out on. If
+// Redis is unavailable, clients that miss the event aren't told to resync.
+// Missing a low-priority event is never a reason to resync.
+func (h *Hub) markFanout(event *Event, streams ...string) {
+    if h.replay == nil || !event.replayable() {
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+    defer cancel()
+
+    if err := h.replay.MarkFanout(ctx, streams...); err != nil {
+        h.logger.Warn("Failed to mark fan-out",
+            zap.String("event_type", string(event.Type)),
+            zap.Error(err),
+        )
+    }
+}
+
+// authorFanoutStream names the events sent to an author's followers
+func authorFanoutStream(authorID uuid.UUID) string {
+    return "author:" + authorID.String()
+}
+
+// topicFanoutStream names the events sent to a topic's subscribers
+func topicFanoutStream(topic Topic) string {
+    return "topic:" + string(topic)
+}
+
+// fanoutKey holds the time in milliseconds of a stream's last fan-out
+func fanoutKey(stream string) string {
+    return "realtime:fanout:" + stream
+}
+
+// replayKey is the sorted set of a user's recent events, scored by sequence
+func replayKey(userID uuid.UUID) string {
+    return fmt.Sprintf("realtime:replay:%s", userID.String())
+}
+
+// replaySeqKey holds the last sequence number given to a user's events
+func replaySeqKey(userID uuid.UUID) string {
+    return fmt.Sprintf("realtime:seq:%s", userID.String())
+}
+
+// ResyncRequiredEvent tells a client its missed events can't be replayed, so it
+// should reload its state over the REST API
+func ResyncRequiredEvent(lastSeq int64) *Event {
+    return NewEvent(EventResyncRequired, map[string]interface{}{
+        "last_event_id": lastSeq,
+        "message":       "Missed events are no longer available, please refresh",
+    })
+}
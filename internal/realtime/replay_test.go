@@ -0,0 +1,255 @@
+package realtime
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// newReplayHub starts a hub that sequences events in a test Redis
+func newReplayHub(t *testing.T, followers FollowerResolver, cfg config.RealtimeConfig) *Hub {
+    t.Helper()
+
+    replay := NewReplayBuffer(newTestRedis(t), cfg, zap.NewNop())
+    hub := NewHub(followers, nil, nil, replay, nil, cfg, zap.NewNop())
+    go hub.Run()
+
+    return hub
+}
+
+func TestSendToUsersSequencesEachUser(t *testing.T) {
+    hub := newReplayHub(t, nil, config.RealtimeConfig{})
+    first := newTestUser("first")
+    second := newTestUser("second")
+
+    // The first user already has an event, so their sequences differ
+    hub.SendToUser(first.ID, NewEvent(EventNotification, nil))
+
+    firstClient := connectLive(t, hub, first)
+    secondClient := connectLive(t, hub, second)
+    hub.SendToUsers([]uuid.UUID{first.ID, second.ID}, NewEvent(EventNotification, nil))
+
+    if seq := waitForEvent(t, firstClient, EventNotification).Seq; seq != 2 {
+        t.Errorf("first user's event seq = %d, want 2", seq)
+    }
+    if seq := waitForEvent(t, secondClient, EventNotification).Seq; seq != 1 {
+        t.Errorf("second user's event seq = %d, want 1", seq)
+    }
+
+    events, complete, err := hub.replay.Since(context.Background(), second.ID, 0)
+    if err != nil {
+        t.Fatalf("failed to get replay: %v", err)
+    }
+    if !complete || len(events) != 1 {
+        t.Errorf("second user's replay = %d events, complete %v, want 1 complete", len(events), complete)
+    }
+}
+
+func TestResumeAfterFanout(t *testing.T) {
+    author := newTestUser("author")
+    follower := newTestUser("follower")
+    topic := HashtagTopic("golang")
+
+    tests := []struct {
+        name       string
+        fanout     func(hub *Hub)
+        topics     []Topic
+        wantResync bool
+    }{
+        {
+            name:   "nothing missed",
+            fanout: func(hub *Hub) {},
+        },
+        {
+            name: "missed follower event",
+            fanout: func(hub *Hub) {
+                hub.BroadcastToFollowers(author.ID, models.VisibilityPublic, NewEvent(EventStoryCreated, nil))
+            },
+            wantResync: true,
+        },
+        {
+            name: "missed topic event",
+            fanout: func(hub *Hub) {
+                hub.PublishToTopic(topic, NewEvent(EventStoryCreated, nil))
+            },
+            topics:     []Topic{topic},
+            wantResync: true,
+        },
+        {
+            name: "topic not resumed",
+            fanout: func(hub *Hub) {
+                hub.PublishToTopic(topic, NewEvent(EventStoryCreated, nil))
+            },
+        },
+        {
+            name: "author not followed",
+            fanout: func(hub *Hub) {
+                hub.BroadcastToFollowers(uuid.New(), models.VisibilityPublic, NewEvent(EventStoryCreated, nil))
+            },
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            hub := newReplayHub(t, staticFollowers{follower.ID: {author.ID}}, config.RealtimeConfig{})
+
+            // Timestamps are whole seconds, so the last event is dated after
+            // anything sent in this second to keep the check exact
+            last := NewEvent(EventNotification, nil)
+            last.Timestamp = time.Now().Add(-time.Second).Unix()
+            hub.SendToUser(follower.ID, last)
+            tt.fanout(hub)
+
+            client := NewClient(hub, nil, follower, "", zap.NewNop())
+            hub.Resume(client, 1, tt.topics...)
+            waitForEvent(t, client, EventWelcome)
+
+            if tt.wantResync {
+                waitForEvent(t, client, EventResyncRequired)
+            } else {
+                expectNoEvent(t, client)
+            }
+        })
+    }
+}
+
+func TestMissedFanoutWithoutPosition(t *testing.T) {
+    replay := NewReplayBuffer(newTestRedis(t), config.RealtimeConfig{}, zap.NewNop())
+    ctx := context.Background()
+    userID := uuid.New()
+    streams := []string{authorFanoutStream(uuid.New())}
+
+    tests := []struct {
+        name    string
+        lastSeq int64
+        streams []string
+        want    bool
+    }{
+        {name: "no streams", lastSeq: 0, streams: nil, want: false},
+        {name: "no sequenced event", lastSeq: 0, streams: streams, want: true},
+        {name: "last event not buffered", lastSeq: 5, streams: streams, want: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            missed, err := replay.MissedFanout(ctx, userID, tt.lastSeq, tt.streams)
+            if err != nil {
+                t.Fatalf("failed to check fan-out: %v", err)
+            }
+            if missed != tt.want {
+                t.Errorf("missed = %v, want %v", missed, tt.want)
+            }
+        })
+    }
+}
+
+func TestResumeReplayLargerThanSendBuffer(t *testing.T) {
+    tests := []struct {
+        name       string
+        missed     int
+        wantResync bool
+    }{
+        {name: "fits", missed: 3},
+        {name: "too large", missed: 10, wantResync: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            // The welcome event takes one of the five slots
+            hub := newReplayHub(t, nil, config.RealtimeConfig{SendBuffer: 5})
+            user := newTestUser("user")
+
+            hub.SendToUser(user.ID, NewEvent(EventNotification, nil))
+            for i := 0; i < tt.missed; i++ {
+                hub.SendToUser(user.ID, NewEvent(EventNotification, nil))
+            }
+
+            client := NewClient(hub, nil, user, "", zap.NewNop())
+            hub.Resume(client, 1)
+            waitForEvent(t, client, EventWelcome)
+
+            select {
+            case <-client.done:
+                t.Fatalf("client was evicted: %s", client.evictReason)
+            default:
+            }
+
+            if tt.wantResync {
+                waitForEvent(t, client, EventResyncRequired)
+                expectNoEvent(t, client)
+                return
+            }
+            for i := 0; i < tt.missed; i++ {
+                if seq := waitForEvent(t, client, EventNotification).Seq; seq != int64(i+2) {
+                    t.Fatalf("replayed seq %d, want %d", seq, i+2)
+                }
+            }
+        })
+    }
+}
+
+func TestLowPriorityEventsAreNotReplayed(t *testing.T) {
+    hub := newReplayHub(t, nil, config.RealtimeConfig{})
+    user := newTestUser("user")
+    other := newTestUser("other")
+    storyID := uuid.New()
+    client := connectLive(t, hub, user)
+
+    hub.SendToUser(user.ID, NewEvent(EventTyping, map[string]interface{}{"conversation_id": uuid.NewString()}))
+    hub.SendToUsers([]uuid.UUID{user.ID, other.ID}, StoryCountersEvent(storyID, &StoryCounters{AuthorID: user.ID}))
+    hub.SendToUser(user.ID, NewEvent(EventNotification, nil))
+
+    // The notification is the first event kept for replay
+    if seq := waitForEvent(t, client, EventNotification).Seq; seq != 1 {
+        t.Errorf("notification seq = %d, want 1", seq)
+    }
+
+    // The counters go through a different hub channel, so may come later
+    var low []*Event
+    deadline := time.Now().Add(eventTimeout)
+    for len(low) < 2 && time.Now().Before(deadline) {
+        low = append(low, receivedLow(t, client)...)
+        time.Sleep(10 * time.Millisecond)
+    }
+    if len(low) != 2 {
+        t.Fatalf("got %d low priority events, want 2", len(low))
+    }
+    for _, event := range low {
+        if event.Seq != 0 {
+            t.Errorf("%s event has seq %d, want none", event.Type, event.Seq)
+        }
+    }
+
+    events, complete, err := hub.replay.Since(context.Background(), user.ID, 0)
+    if err != nil {
+        t.Fatalf("failed to get replay: %v", err)
+    }
+    if !complete || len(events) != 1 || events[0].Type != EventNotification {
+        t.Errorf("replay = %d events, complete %v, want only the notification", len(events), complete)
+    }
+}
+
+func TestResumeIgnoresMissedLowPriorityFanout(t *testing.T) {
+    hub := newReplayHub(t, nil, config.RealtimeConfig{})
+    user := newTestUser("user")
+    topic := StoryTopic(uuid.New())
+
+    last := NewEvent(EventNotification, nil)
+    last.Timestamp = time.Now().Add(-time.Second).Unix()
+    hub.SendToUser(user.ID, last)
+
+    // A missed counters update is replaced by the next one, so it is no
+    // reason to reload
+    hub.PublishToTopic(topic, StoryCountersEvent(uuid.New(), &StoryCounters{}))
+
+    client := NewClient(hub, nil, user, "", zap.NewNop())
+    hub.Resume(client, 1, topic)
+    waitForEvent(t, client, EventWelcome)
+    expectNoEvent(t, client)
+}
@@ -0,0 +1,123 @@
+package realtime
+
+import (
+    "errors"
+    "time"
+
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+const (
+    defaultTokenRecheckInterval = 5 * time.Minute
+
+    // evictTokenInvalid disconnects a client whose token no longer validates
+    evictTokenInvalid = "token_invalid"
+)
+
+// TokenValidator checks an access token and returns its user
+type TokenValidator interface {
+    ValidateToken(token string) (*models.User, error)
+}
+
+// Revalidator periodically validates the tokens of connected clients again, so
+// users whose tokens expire or are revoked, or whose accounts are disabled,
+// don't keep receiving events on connections opened earlier. It also authorizes
+// topic subscriptions again, which catches changes the API doesn't announce to
+// the hub, such as stories archived by the worker.
+type Revalidator struct {
+    hub       *Hub
+    validator TokenValidator
+    interval  time.Duration
+    logger    *zap.Logger
+    stopCh    chan struct{}
+}
+
+// NewRevalidator creates a token revalidator and attaches it to the hub
+func NewRevalidator(hub *Hub, validator TokenValidator, cfg config.RealtimeConfig, logger *zap.Logger) *Revalidator {
+    interval := cfg.TokenRecheckInterval
+    if interval <= 0 {
+        interval = defaultTokenRecheckInterval
+    }
+
+    revalidator := &Revalidator{
+        hub:       hub,
+        validator: validator,
+        interval:  interval,
+        logger:    logger.With(zap.String("component", "realtime_revalidator")),
+        stopCh:    make(chan struct{}),
+    }
+    hub.revalidator = revalidator
+
+    return revalidator
+}
+
+// Start starts checking tokens
+func (r *Revalidator) Start() {
+    r.logger.Info("Starting token revalidation", zap.Duration("interval", r.interval))
+
+    go r.run()
+}
+
+// Stop stops checking tokens
+func (r *Revalidator) Stop() {
+    close(r.stopCh)
+}
+
+// run checks every connected client's token once per interval
+func (r *Revalidator) run() {
+    ticker := time.NewTicker(r.interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-r.stopCh:
+            return
+
+        case <-ticker.C:
+            r.sweep()
+        }
+    }
+}
+
+// sweep disconnects the clients whose tokens no longer validate. A client is
+// kept if its user couldn't be loaded, so an outage doesn't drop everyone.
+func (r *Revalidator) sweep() {
+    clients := r.hub.snapshotClients()
+
+    evicted := 0
+    for _, client := range clients {
+        if client.token == "" {
+            continue
+        }
+
+        _, err := r.validator.ValidateToken(client.token)
+        if err == nil {
+            continue
+        }
+        if errors.Is(err, auth.ErrUserLookupFailed) {
+            r.logger.Warn("Failed to revalidate client token",
+                zap.String("user_id", client.User.ID.String()),
+                zap.Error(err),
+            )
+            continue
+        }
+
+        r.logger.Info("Disconnecting client with invalid token",
+            zap.String("user_id", client.User.ID.String()),
+            zap.Error(err),
+        )
+        client.evict(evictTokenInvalid)
+        evicted++
+    }
+
+    r.logger.Debug("Revalidated client tokens",
+        zap.Int("client_count", len(clients)),
+        zap.Int("evicted", evicted),
+    )
+
+    r.hub.recheckSubscriptions(clients, nil)
+}
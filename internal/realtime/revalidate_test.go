@@ -0,0 +1,116 @@
+package realtime
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/golang-jwt/jwt/v5"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/auth"
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// fakeValidator fails the tokens it has an error for and accepts the rest
+type fakeValidator map[string]error
+
+func (f fakeValidator) ValidateToken(token string) (*models.User, error) {
+    if err := f[token]; err != nil {
+        return nil, err
+    }
+    return &models.User{}, nil
+}
+
+// connectWithToken registers a client opened with an access token
+func connectWithToken(t *testing.T, hub *Hub, user *models.User, token string) *Client {
+    t.Helper()
+
+    client := NewClient(hub, nil, user, token, zap.NewNop())
+    hub.registerClient(client)
+    received(t, client)
+
+    return client
+}
+
+func TestRevalidatorEvictsInvalidTokens(t *testing.T) {
+    hub := newTestHub(t, nil, config.RealtimeConfig{})
+    validator := fakeValidator{
+        "revoked":     errors.New("token has been revoked"),
+        "lookup-down": fmt.Errorf("%w: connection refused", auth.ErrUserLookupFailed),
+    }
+    revalidator := NewRevalidator(hub, validator, config.RealtimeConfig{}, zap.NewNop())
+
+    valid := connectWithToken(t, hub, newTestUser("valid"), "valid")
+    revoked := connectWithToken(t, hub, newTestUser("revoked"), "revoked")
+    lookupDown := connectWithToken(t, hub, newTestUser("lookup"), "lookup-down")
+    tokenless := connectWithToken(t, hub, newTestUser("tokenless"), "")
+
+    revalidator.sweep()
+
+    if reason := evicted(revoked); reason != evictTokenInvalid {
+        t.Errorf("revoked token: evicted with %q, want %q", reason, evictTokenInvalid)
+    }
+
+    // A failed user lookup says nothing about the token, so the client stays
+    for name, client := range map[string]*Client{"valid": valid, "lookup failed": lookupDown, "no token": tokenless} {
+        if reason := evicted(client); reason != "" {
+            t.Errorf("%s: evicted with %q, want connected", name, reason)
+        }
+    }
+}
+
+func TestRevalidatorEvictsLoggedOutClient(t *testing.T) {
+    redisClient := newTestRedis(t)
+    cfg := &config.Config{JWTSecret: "test-secret", JWTRefreshExpiryDays: 7}
+    user := newTestUser("user")
+    user.IsActive = true
+    users := &fakeUsers{}
+    users.add(user)
+    authService := auth.NewService(cfg, users, redisClient, zap.NewNop())
+
+    hub := newTestHub(t, nil, config.RealtimeConfig{})
+    revalidator := NewRevalidator(hub, authService, config.RealtimeConfig{}, zap.NewNop())
+
+    // Two sessions of the same user
+    tokenIDs := []string{uuid.NewString(), uuid.NewString()}
+    var clients []*Client
+    for _, tokenID := range tokenIDs {
+        claims := &models.TokenClaims{
+            UserID:  user.ID,
+            TokenID: tokenID,
+            RegisteredClaims: jwt.RegisteredClaims{
+                IssuedAt:  jwt.NewNumericDate(time.Now()),
+                ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+            },
+        }
+        token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.JWTSecret))
+        if err != nil {
+            t.Fatalf("failed to sign token: %v", err)
+        }
+        clients = append(clients, connectWithToken(t, hub, user, token))
+    }
+
+    revalidator.sweep()
+    for i, client := range clients {
+        if reason := evicted(client); reason != "" {
+            t.Fatalf("session %d evicted with %q before logging out", i, reason)
+        }
+    }
+
+    if err := authService.Logout(context.Background(), user.ID, tokenIDs[0]); err != nil {
+        t.Fatalf("failed to log out: %v", err)
+    }
+    revalidator.sweep()
+
+    if reason := evicted(clients[0]); reason != evictTokenInvalid {
+        t.Errorf("logged out session: evicted with %q, want %q", reason, evictTokenInvalid)
+    }
+    if reason := evicted(clients[1]); reason != "" {
+        t.Errorf("other session: evicted with %q, want connected", reason)
+    }
+}
@@ -0,0 +1,143 @@
+package realtime
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "time"
+
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+const (
+    // sseHeartbeatPeriod keeps proxies from closing an idle stream
+    sseHeartbeatPeriod = 25 * time.Second
+
+    // sseRetry is how long a browser waits before reconnecting a dropped stream
+    sseRetry = 3 * time.Second
+)
+
+// NewSSEClient creates a client that receives events as Server-Sent Events.
+// The stream only goes one way, so topics are subscribed when it connects.
+func NewSSEClient(hub *Hub, user *models.User, token string, logger *zap.Logger) *Client {
+    client := NewClient(hub, nil, user, token, logger)
+    client.transport = TransportSSE
+    return client
+}
+
+// SubscribeTopics subscribes the client to each topic. Topics that are refused
+// get an error event on the stream, as they would over a websocket.
+func (c *Client) SubscribeTopics(topics []string) {
+    for _, topic := range topics {
+        c.handleSubscription(map[string]interface{}{"topic": topic}, true)
+    }
+}
+
+// StreamSSE writes the client's events to w until the request ends or the hub
+// drops the client, then unregisters it. Events carry the same JSON as over a
+// websocket, and sequenced events use their seq as the SSE id so a reconnecting
+// browser sends it back as Last-Event-ID.
+func (c *Client) StreamSSE(ctx context.Context, w http.ResponseWriter) {
+    defer c.hub.Unregister(c)
+
+    flusher, ok := w.(http.Flusher)
+    if !ok {
+        c.logger.Error("Response writer does not support streaming",
+            zap.String("user_id", c.User.ID.String()),
+        )
+        return
+    }
+    controller := http.NewResponseController(w)
+
+    ticker := time.NewTicker(sseHeartbeatPeriod)
+    defer ticker.Stop()
+
+    // The server's write timeout would otherwise end the stream, so each write
+    // gets its own deadline instead
+    write := func(frame func() error) bool {
+        controller.SetWriteDeadline(time.Now().Add(writeWait))
+        if err := frame(); err != nil {
+            return false
+        }
+        flusher.Flush()
+        return true
+    }
+
+    if !write(func() error {
+        _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+        return err
+    }) {
+        return
+    }
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+
+        case message, ok := <-c.send:
+            if !ok {
+                // The hub closed the channel
+                return
+            }
+            if !write(func() error { return writeSSEEvents(w, c.drain(message)) }) {
+                return
+            }
+
+        case <-c.lowReady:
+            if !write(func() error { return writeSSEEvents(w, c.drain(nil)) }) {
+                return
+            }
+
+        case <-c.done:
+            return
+
+        case <-ticker.C:
+            if !write(func() error {
+                _, err := io.WriteString(w, ": heartbeat\n\n")
+                return err
+            }) {
+                return
+            }
+        }
+    }
+}
+
+// writeSSEEvents writes queued messages as one SSE event each
+func writeSSEEvents(w io.Writer, messages [][]byte) error {
+    for _, message := range messages {
+        if err := writeSSEEvent(w, message); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// writeSSEEvent writes one queued message as an SSE event. Every event uses the
+// default message type, so clients handle them all in onmessage.
+func writeSSEEvent(w io.Writer, message []byte) error {
+    var frame bytes.Buffer
+
+    var meta struct {
+        Seq int64 `json:"seq"`
+    }
+    if err := json.Unmarshal(message, &meta); err == nil && meta.Seq > 0 {
+        fmt.Fprintf(&frame, "id: %d\n", meta.Seq)
+    }
+
+    // Raw broadcasts aren't necessarily single-line JSON
+    for _, line := range bytes.Split(message, []byte{'\n'}) {
+        frame.WriteString("data: ")
+        frame.Write(line)
+        frame.WriteByte('\n')
+    }
+    frame.WriteByte('\n')
+
+    _, err := w.Write(frame.Bytes())
+    return err
+}
@@ -0,0 +1,239 @@
+package realtime
+
+import (
+    "context"
+    "time"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+)
+
+// recheckTimeout bounds the lookups that authorize one subscription again
+const recheckTimeout = 5 * time.Second
+
+// RecheckTopic authorizes the subscribers of a topic again and unsubscribes the
+// ones who may no longer subscribe, e.g. after a story is made private or deleted
+func (h *Hub) RecheckTopic(topic Topic) {
+    h.publish(&clusterMessage{Kind: clusterRecheckTopic, Topic: topic})
+    go h.recheckTopic(topic)
+}
+
+// RecheckUserSubscriptions authorizes a user's subscriptions again, e.g. after
+// someone blocks them or they unfollow an author
+func (h *Hub) RecheckUserSubscriptions(userID uuid.UUID) {
+    h.publish(&clusterMessage{Kind: clusterRecheckUser, UserID: userID})
+    go h.recheckUser(userID)
+}
+
+// recheckTopic rechecks the local subscribers of a topic
+func (h *Hub) recheckTopic(topic Topic) {
+    h.mutex.RLock()
+    clients := make([]*Client, 0, len(h.topicClients[topic]))
+    for client := range h.topicClients[topic] {
+        clients = append(clients, client)
+    }
+    h.mutex.RUnlock()
+
+    h.recheckSubscriptions(clients, func(t Topic) bool { return t == topic })
+}
+
+// recheckUser rechecks every subscription of a user's local clients
+func (h *Hub) recheckUser(userID uuid.UUID) {
+    h.mutex.RLock()
+    clients := append([]*Client(nil), h.userClients[userID]...)
+    h.mutex.RUnlock()
+
+    h.recheckSubscriptions(clients, nil)
+}
+
+// recheckSubscriptions authorizes the clients' subscriptions that match again
+// and revokes the ones that are now forbidden or gone. Each user and topic is
+// checked once however many connections share it. A subscription is kept when
+// the check fails for another reason, so an outage doesn't unsubscribe everyone.
+func (h *Hub) recheckSubscriptions(clients []*Client, match func(Topic) bool) {
+    if h.topics == nil {
+        return
+    }
+
+    type grant struct {
+        userID uuid.UUID
+        topic  Topic
+    }
+
+    // Hashtag topics are open to everyone, so there is nothing to check
+    byGrant := make(map[grant][]*Client)
+    h.mutex.RLock()
+    for _, client := range clients {
+        for topic := range client.topics {
+            if topic.Kind() == TopicKindHashtag || (match != nil && !match(topic)) {
+                continue
+            }
+            key := grant{userID: client.User.ID, topic: topic}
+            byGrant[key] = append(byGrant[key], client)
+        }
+    }
+    h.mutex.RUnlock()
+
+    for key, grantClients := range byGrant {
+        ctx, cancel := context.WithTimeout(context.Background(), recheckTimeout)
+        err := h.topics.AuthorizeTopic(ctx, grantClients[0].User, key.topic)
+        cancel()
+
+        reason := ""
+        switch err {
+        case nil:
+            continue
+        case ErrTopicNotFound:
+            reason = "not_found"
+        case ErrTopicForbidden, ErrInvalidTopic:
+            reason = "forbidden"
+        default:
+            h.logger.Warn("Failed to recheck topic subscription",
+                zap.String("user_id", key.userID.String()),
+                zap.String("topic", string(key.topic)),
+                zap.Error(err),
+            )
+            continue
+        }
+
+        for _, client := range grantClients {
+            h.revokeSubscription(client, key.topic, reason)
+        }
+    }
+}
+
+// revokeSubscription unsubscribes a client that may no longer receive a topic
+// and tells it why
+func (h *Hub) revokeSubscription(client *Client, topic Topic, reason string) {
+    h.mutex.Lock()
+    defer h.mutex.Unlock()
+
+    if !client.topics[topic] {
+        return
+    }
+    h.removeSubscription(client, topic)
+
+    h.logger.Debug("Revoked topic subscription",
+        zap.String("user_id", client.User.ID.String()),
+        zap.String("topic", string(topic)),
+        zap.String("reason", reason),
+    )
+
+    client.Send(&Event{
+        Type: EventUnsubscribed,
+        Payload: map[string]interface{}{
+            "topic":  topic,
+            "reason": reason,
+        },
+    })
+}
+
+// updateSubscription adds or removes a client's topic subscription and
+// acknowledges it to the client
+func (h *Hub) updateSubscription(subscription *Subscription) {
+    h.mutex.Lock()
+    defer h.mutex.Unlock()
+
+    client := subscription.Client
+    topic := subscription.Topic
+
+    // The client may have disconnected while the subscription was authorized
+    if _, ok := h.clients[client]; !ok {
+        return
+    }
+
+    if !subscription.Subscribe {
+        h.removeSubscription(client, topic)
+        client.Send(&Event{
+            Type:    EventUnsubscribed,
+            Payload: map[string]interface{}{"topic": topic},
+        })
+        return
+    }
+
+    if !client.topics[topic] && len(client.topics) >= h.maxSubscriptions {
+        client.Send(subscriptionErrorEvent(topic, "subscription_limit", "Too many subscriptions on this connection"))
+        return
+    }
+
+    client.topics[topic] = true
+    subscribers, exists := h.topicClients[topic]
+    if !exists {
+        subscribers = make(map[*Client]bool)
+        h.topicClients[topic] = subscribers
+    }
+    subscribers[client] = true
+
+    h.logger.Debug("Client subscribed to topic",
+        zap.String("user_id", client.User.ID.String()),
+        zap.String("topic", string(topic)),
+        zap.Int("client_subscriptions", len(client.topics)),
+    )
+
+    client.Send(&Event{
+        Type:    EventSubscribed,
+        Payload: map[string]interface{}{"topic": topic},
+    })
+}
+
+// removeSubscription removes one of a client's subscriptions. Must be called
+// with the mutex held.
+func (h *Hub) removeSubscription(client *Client, topic Topic) {
+    delete(client.topics, topic)
+
+    subscribers, exists := h.topicClients[topic]
+    if !exists {
+        return
+    }
+
+    delete(subscribers, client)
+    if len(subscribers) == 0 {
+        delete(h.topicClients, topic)
+    }
+}
+
+// unsubscribeAll removes every subscription of a disconnecting client. Must be
+// called with the mutex held.
+func (h *Hub) unsubscribeAll(client *Client) {
+    for topic := range client.topics {
+        h.removeSubscription(client, topic)
+    }
+}
+
+// sendToTopic sends an event to the clients subscribed to a topic, skipping
+// the excluded users
+func (h *Hub) sendToTopic(topicEvent *TopicEvent) {
+    h.mutex.RLock()
+    defer h.mutex.RUnlock()
+
+    excluded := make(map[uuid.UUID]bool, len(topicEvent.ExcludeUserIDs))
+    for _, userID := range topicEvent.ExcludeUserIDs {
+        excluded[userID] = true
+    }
+
+    subscribers := h.topicClients[topicEvent.Topic]
+    for client := range subscribers {
+        if excluded[client.User.ID] {
+            continue
+        }
+        client.Send(topicEvent.Event)
+    }
+
+    h.logger.Debug("Sent event to topic",
+        zap.String("topic", string(topicEvent.Topic)),
+        zap.String("event_type", string(topicEvent.Event.Type)),
+        zap.Int("client_count", len(subscribers)),
+    )
+}
+
+// subscriptionErrorEvent creates an error event for a rejected subscription
+func subscriptionErrorEvent(topic Topic, code, message string) *Event {
+    return &Event{
+        Type: EventError,
+        Payload: map[string]interface{}{
+            "error":   code,
+            "message": message,
+            "topic":   topic,
+        },
+    }
+}
@@ -0,0 +1,167 @@
+package realtime
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// fakeTopics authorizes every subscription except the ones given an error
+type fakeTopics struct {
+    mu     sync.Mutex
+    errors map[uuid.UUID]map[Topic]error
+}
+
+func (f *fakeTopics) AuthorizeTopic(ctx context.Context, user *models.User, topic Topic) error {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    return f.errors[user.ID][topic]
+}
+
+// fail makes the user's later checks of the topic return err
+func (f *fakeTopics) fail(userID uuid.UUID, topic Topic, err error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+
+    if f.errors == nil {
+        f.errors = make(map[uuid.UUID]map[Topic]error)
+    }
+    if f.errors[userID] == nil {
+        f.errors[userID] = make(map[Topic]error)
+    }
+    f.errors[userID][topic] = err
+}
+
+// subscribe subscribes a client to a topic and discards the acknowledgement
+func subscribe(t *testing.T, hub *Hub, client *Client, topic Topic) {
+    t.Helper()
+
+    hub.updateSubscription(&Subscription{Client: client, Topic: topic, Subscribe: true})
+    if types := receivedTypes(t, client); len(types) != 1 || types[0] != EventSubscribed {
+        t.Fatalf("subscribing to %s got %v", topic, types)
+    }
+}
+
+func TestRecheckTopicRevokesForbiddenSubscribers(t *testing.T) {
+    topics := &fakeTopics{}
+    hub := NewHub(nil, topics, nil, nil, nil, config.RealtimeConfig{}, zap.NewNop())
+
+    allowed := newTestUser("allowed")
+    revoked := newTestUser("revoked")
+    topic := StoryTopic(uuid.New())
+
+    allowedClient := connect(t, hub, allowed)
+    revokedClient := connect(t, hub, revoked)
+    revokedOtherDevice := connect(t, hub, revoked)
+    for _, client := range []*Client{allowedClient, revokedClient, revokedOtherDevice} {
+        subscribe(t, hub, client, topic)
+    }
+
+    topics.fail(revoked.ID, topic, ErrTopicForbidden)
+    hub.recheckTopic(topic)
+
+    for _, client := range []*Client{revokedClient, revokedOtherDevice} {
+        events := received(t, client)
+        if len(events) != 1 || events[0].Type != EventUnsubscribed {
+            t.Fatalf("revoked client got %v, want one unsubscribed event", events)
+        }
+        if reason := events[0].Payload["reason"]; reason != "forbidden" {
+            t.Errorf("reason = %v, want forbidden", reason)
+        }
+    }
+    if types := receivedTypes(t, allowedClient); len(types) != 0 {
+        t.Errorf("allowed client got %v", types)
+    }
+
+    hub.sendToTopic(&TopicEvent{Topic: topic, Event: NewEvent(EventStoryUpdated, nil)})
+
+    if types := receivedTypes(t, allowedClient); len(types) != 1 {
+        t.Errorf("allowed client got %v, want one event", types)
+    }
+    for _, client := range []*Client{revokedClient, revokedOtherDevice} {
+        if types := receivedTypes(t, client); len(types) != 0 {
+            t.Errorf("revoked client still got %v", types)
+        }
+    }
+}
+
+func TestRecheckUserRevokesOnlyFailingTopics(t *testing.T) {
+    topics := &fakeTopics{}
+    hub := NewHub(nil, topics, nil, nil, nil, config.RealtimeConfig{}, zap.NewNop())
+
+    blocked := newTestUser("blocked")
+    blocker := newTestUser("blocker")
+    blockerTopic := UserTopic(blocker.ID)
+    deletedStory := StoryTopic(uuid.New())
+    otherTopic := UserTopic(uuid.New())
+    hashtag := HashtagTopic("golang")
+
+    client := connect(t, hub, blocked)
+    for _, topic := range []Topic{blockerTopic, deletedStory, otherTopic, hashtag} {
+        subscribe(t, hub, client, topic)
+    }
+
+    topics.fail(blocked.ID, blockerTopic, ErrTopicForbidden)
+    topics.fail(blocked.ID, deletedStory, ErrTopicNotFound)
+    // A failed lookup keeps the subscription
+    topics.fail(blocked.ID, otherTopic, errors.New("database unavailable"))
+    hub.recheckUser(blocked.ID)
+
+    reasons := make(map[Topic]interface{})
+    for _, event := range received(t, client) {
+        if event.Type != EventUnsubscribed {
+            t.Fatalf("got %s event, want unsubscribed", event.Type)
+        }
+        topic, _ := event.Payload["topic"].(string)
+        reasons[Topic(topic)] = event.Payload["reason"]
+    }
+
+    want := map[Topic]interface{}{blockerTopic: "forbidden", deletedStory: "not_found"}
+    if len(reasons) != len(want) {
+        t.Fatalf("revoked %v, want %v", reasons, want)
+    }
+    for topic, reason := range want {
+        if reasons[topic] != reason {
+            t.Errorf("%s revoked with %v, want %v", topic, reasons[topic], reason)
+        }
+    }
+
+    for _, topic := range []Topic{otherTopic, hashtag} {
+        if !client.topics[topic] {
+            t.Errorf("%s subscription was removed", topic)
+        }
+    }
+}
+
+func TestSendToTopicSkipsExcludedUsers(t *testing.T) {
+    hub := newTestHub(t, nil, config.RealtimeConfig{})
+    topic := HashtagTopic("golang")
+
+    subscriber := newTestUser("subscriber")
+    blocked := newTestUser("blocked")
+    subscriberClient := connect(t, hub, subscriber)
+    blockedClient := connect(t, hub, blocked)
+    subscribe(t, hub, subscriberClient, topic)
+    subscribe(t, hub, blockedClient, topic)
+
+    hub.sendToTopic(&TopicEvent{
+        Topic:          topic,
+        Event:          NewEvent(EventStoryCreated, nil),
+        ExcludeUserIDs: []uuid.UUID{blocked.ID},
+    })
+
+    if types := receivedTypes(t, subscriberClient); len(types) != 1 {
+        t.Errorf("subscriber got %v, want one event", types)
+    }
+    if types := receivedTypes(t, blockedClient); len(types) != 0 {
+        t.Errorf("excluded user got %v", types)
+    }
+}
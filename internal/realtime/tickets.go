@@ -0,0 +1,89 @@
+package realtime
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/redis/go-redis/v9"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+)
+
+// TicketTTL is how long a ticket can be used to open a connection
+const TicketTTL = 30 * time.Second
+
+// ErrInvalidTicket means a ticket doesn't exist, has expired or was already used
+var ErrInvalidTicket = errors.New("invalid or expired ticket")
+
+// TicketStore issues single-use tickets for opening a realtime connection, so
+// the access token itself never has to go in a URL
+type TicketStore struct {
+    redisClient *storage.RedisClient
+    logger      *zap.Logger
+}
+
+// ticket is what a ticket stands for until it is redeemed
+type ticket struct {
+    UserID uuid.UUID `json:"user_id"`
+    Token  string    `json:"token"`
+}
+
+// NewTicketStore creates a new ticket store
+func NewTicketStore(redisClient *storage.RedisClient, logger *zap.Logger) *TicketStore {
+    return &TicketStore{
+        redisClient: redisClient,
+        logger:      logger.With(zap.String("component", "realtime_tickets")),
+    }
+}
+
+// Issue creates a ticket for a user's validated access token. The token is
+// kept so the connection can be checked against it again later.
+func (s *TicketStore) Issue(ctx context.Context, userID uuid.UUID, token string) (string, error) {
+    id := make([]byte, 32)
+    if _, err := rand.Read(id); err != nil {
+        return "", fmt.Errorf("failed to generate ticket: %w", err)
+    }
+    value := hex.EncodeToString(id)
+
+    data, err := json.Marshal(&ticket{UserID: userID, Token: token})
+    if err != nil {
+        return "", fmt.Errorf("failed to marshal ticket: %w", err)
+    }
+
+    if err := s.redisClient.GetClient().Set(ctx, ticketKey(value), data, TicketTTL).Err(); err != nil {
+        return "", fmt.Errorf("failed to store ticket: %w", err)
+    }
+
+    return value, nil
+}
+
+// Redeem uses up a ticket and returns the access token it was issued for
+func (s *TicketStore) Redeem(ctx context.Context, value string) (string, error) {
+    // GETDEL makes the ticket single-use even when redeemed concurrently
+    data, err := s.redisClient.GetClient().GetDel(ctx, ticketKey(value)).Bytes()
+    if err != nil {
+        if err == redis.Nil {
+            return "", ErrInvalidTicket
+        }
+        return "", fmt.Errorf("failed to redeem ticket: %w", err)
+    }
+
+    var t ticket
+    if err := json.Unmarshal(data, &t); err != nil {
+        return "", fmt.Errorf("failed to unmarshal ticket: %w", err)
+    }
+
+    return t.Token, nil
+}
+
+// ticketKey holds an unredeemed ticket
+func ticketKey(value string) string {
+    return fmt.Sprintf("realtime:ticket:%s", value)
+}
@@ -0,0 +1,106 @@
+package realtime
+
+import (
+    "context"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// newTestTickets creates a ticket store on an in-process Redis whose clock the
+// test controls
+func newTestTickets(t *testing.T) (*TicketStore, *miniredis.Miniredis) {
+    t.Helper()
+
+    server := miniredis.RunT(t)
+    redisClient, err := storage.NewRedisClient(&config.Config{RedisURL: "redis://" + server.Addr()}, zap.NewNop())
+    if err != nil {
+        t.Fatalf("failed to connect to test Redis: %v", err)
+    }
+    t.Cleanup(func() { redisClient.Close() })
+
+    return NewTicketStore(redisClient, zap.NewNop()), server
+}
+
+func TestTicketIsSingleUse(t *testing.T) {
+    tickets, _ := newTestTickets(t)
+    ctx := context.Background()
+
+    ticket, err := tickets.Issue(ctx, uuid.New(), "access-token")
+    if err != nil {
+        t.Fatalf("failed to issue ticket: %v", err)
+    }
+
+    token, err := tickets.Redeem(ctx, ticket)
+    if err != nil {
+        t.Fatalf("failed to redeem ticket: %v", err)
+    }
+    if token != "access-token" {
+        t.Errorf("redeemed token %q, want the token the ticket was issued for", token)
+    }
+
+    if _, err := tickets.Redeem(ctx, ticket); err != ErrInvalidTicket {
+        t.Errorf("second redeem: got %v, want ErrInvalidTicket", err)
+    }
+}
+
+func TestTicketRedeemedConcurrentlyOnce(t *testing.T) {
+    tickets, _ := newTestTickets(t)
+    ctx := context.Background()
+
+    ticket, err := tickets.Issue(ctx, uuid.New(), "access-token")
+    if err != nil {
+        t.Fatalf("failed to issue ticket: %v", err)
+    }
+
+    var wg sync.WaitGroup
+    var mu sync.Mutex
+    redeemed := 0
+    for i := 0; i < 10; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            if _, err := tickets.Redeem(ctx, ticket); err == nil {
+                mu.Lock()
+                redeemed++
+                mu.Unlock()
+            }
+        }()
+    }
+    wg.Wait()
+
+    if redeemed != 1 {
+        t.Errorf("ticket redeemed %d times, want once", redeemed)
+    }
+}
+
+func TestTicketExpires(t *testing.T) {
+    tickets, server := newTestTickets(t)
+    ctx := context.Background()
+
+    ticket, err := tickets.Issue(ctx, uuid.New(), "access-token")
+    if err != nil {
+        t.Fatalf("failed to issue ticket: %v", err)
+    }
+
+    server.FastForward(TicketTTL + time.Second)
+
+    if _, err := tickets.Redeem(ctx, ticket); err != ErrInvalidTicket {
+        t.Errorf("redeem after TTL: got %v, want ErrInvalidTicket", err)
+    }
+}
+
+func TestUnknownTicketIsInvalid(t *testing.T) {
+    tickets, _ := newTestTickets(t)
+
+    if _, err := tickets.Redeem(context.Background(), "not-a-ticket"); err != ErrInvalidTicket {
+        t.Errorf("got %v, want ErrInvalidTicket", err)
+    }
+}
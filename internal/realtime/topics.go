@@ -0,0 +1,225 @@
+package realtime
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/google/uuid"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+)
+
+// DefaultMaxSubscriptions caps the topics one connection can subscribe to
+const DefaultMaxSubscriptions = 50
+
+// Topic kinds a client can subscribe to
+const (
+    TopicKindStory   = "story"
+    TopicKindUser    = "user"
+    TopicKindHashtag = "hashtag"
+)
+
+// Topic subscription errors
+var (
+    ErrInvalidTopic   = errors.New("invalid topic")
+    ErrTopicNotFound  = errors.New("topic not found")
+    ErrTopicForbidden = errors.New("topic forbidden")
+)
+
+// Topic is a stream of events a client can subscribe to, written "<kind>:<id>":
+// story:<id> carries a story's live counters, user:<id> a user's new stories and
+// hashtag:<tag> new public stories with that tag
+type Topic string
+
+// StoryTopic returns the topic for a story's live counters
+func StoryTopic(storyID uuid.UUID) Topic {
+    return Topic(TopicKindStory + ":" + storyID.String())
+}
+
+// UserTopic returns the topic for a user's new stories
+func UserTopic(userID uuid.UUID) Topic {
+    return Topic(TopicKindUser + ":" + userID.String())
+}
+
+// HashtagTopic returns the topic for new stories with a hashtag
+func HashtagTopic(tag string) Topic {
+    return Topic(TopicKindHashtag + ":" + models.NormalizeHashtag(tag))
+}
+
+// ParseTopic validates a topic sent by a client, normalizing hashtags
+func ParseTopic(value string) (Topic, error) {
+    kind, id, ok := strings.Cut(value, ":")
+    if !ok || id == "" {
+        return "", ErrInvalidTopic
+    }
+
+    switch kind {
+    case TopicKindStory, TopicKindUser:
+        parsed, err := uuid.Parse(id)
+        if err != nil {
+            return "", ErrInvalidTopic
+        }
+        return Topic(kind + ":" + parsed.String()), nil
+
+    case TopicKindHashtag:
+        tag := models.NormalizeHashtag(id)
+        if !models.IsValidHashtag(tag) {
+            return "", ErrInvalidTopic
+        }
+        return Topic(kind + ":" + tag), nil
+    }
+
+    return "", ErrInvalidTopic
+}
+
+// Kind returns the kind of the topic
+func (t Topic) Kind() string {
+    kind, _, _ := strings.Cut(string(t), ":")
+    return kind
+}
+
+// ID returns the part of the topic after the kind
+func (t Topic) ID() string {
+    _, id, _ := strings.Cut(string(t), ":")
+    return id
+}
+
+// TopicEvent represents an event for the subscribers of a topic. Excluded
+// users don't get it even if they are subscribed, e.g. users blocked by the
+// author of a story sent to a hashtag topic.
+type TopicEvent struct {
+    Topic          Topic
+    Event          *Event
+    ExcludeUserIDs []uuid.UUID
+}
+
+// Subscription represents a client subscribing to or unsubscribing from a topic
+type Subscription struct {
+    Client    *Client
+    Topic     Topic
+    Subscribe bool
+}
+
+// TopicAuthorizer decides whether a user may subscribe to a topic
+type TopicAuthorizer interface {
+    AuthorizeTopic(ctx context.Context, user *models.User, topic Topic) error
+}
+
+// StoreTopicAuthorizer authorizes subscriptions with the same rules the REST
+// API uses to show stories and profiles
+type StoreTopicAuthorizer struct {
+    storyStore  storage.StoryStore
+    userStore   storage.UserStore
+    followStore storage.FollowStore
+    blockStore  storage.BlockStore
+}
+
+// NewTopicAuthorizer creates a topic authorizer backed by the stores
+func NewTopicAuthorizer(
+    storyStore storage.StoryStore,
+    userStore storage.UserStore,
+    followStore storage.FollowStore,
+    blockStore storage.BlockStore,
+) *StoreTopicAuthorizer {
+    return &StoreTopicAuthorizer{
+        storyStore:  storyStore,
+        userStore:   userStore,
+        followStore: followStore,
+        blockStore:  blockStore,
+    }
+}
+
+// AuthorizeTopic returns nil if the user may subscribe to the topic
+func (a *StoreTopicAuthorizer) AuthorizeTopic(ctx context.Context, user *models.User, topic Topic) error {
+    switch topic.Kind() {
+    case TopicKindStory:
+        storyID, err := uuid.Parse(topic.ID())
+        if err != nil {
+            return ErrInvalidTopic
+        }
+        return a.authorizeStory(ctx, user.ID, storyID)
+
+    case TopicKindUser:
+        userID, err := uuid.Parse(topic.ID())
+        if err != nil {
+            return ErrInvalidTopic
+        }
+        return a.authorizeUser(ctx, user.ID, userID)
+
+    case TopicKindHashtag:
+        // Hashtag topics only carry public stories
+        return nil
+    }
+
+    return ErrInvalidTopic
+}
+
+// authorizeStory allows subscribers who can view the story
+func (a *StoreTopicAuthorizer) authorizeStory(ctx context.Context, viewerID, storyID uuid.UUID) error {
+    story, err := a.storyStore.GetByID(ctx, storyID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            return ErrTopicNotFound
+        }
+        return fmt.Errorf("failed to get story: %w", err)
+    }
+
+    if story.AuthorID == viewerID {
+        return nil
+    }
+    if story.IsDraft() {
+        return ErrTopicNotFound
+    }
+    if !story.CanView(&viewerID) {
+        return ErrTopicForbidden
+    }
+
+    if err := a.checkNotBlocked(ctx, story.AuthorID, viewerID); err != nil {
+        return err
+    }
+
+    // Friends-only stories are for followers, as in the feed
+    if story.Visibility == models.VisibilityFriends {
+        following, err := a.followStore.IsFollowing(ctx, viewerID, story.AuthorID)
+        if err != nil {
+            return fmt.Errorf("failed to check follow status: %w", err)
+        }
+        if !following {
+            return ErrTopicForbidden
+        }
+    }
+
+    return nil
+}
+
+// authorizeUser allows subscribers the user hasn't blocked. Which stories they
+// receive is still filtered by visibility when each story is sent.
+func (a *StoreTopicAuthorizer) authorizeUser(ctx context.Context, viewerID, userID uuid.UUID) error {
+    if _, err := a.userStore.GetByID(ctx, userID); err != nil {
+        if err == storage.ErrNotFound {
+            return ErrTopicNotFound
+        }
+        return fmt.Errorf("failed to get user: %w", err)
+    }
+
+    if userID == viewerID {
+        return nil
+    }
+
+    return a.checkNotBlocked(ctx, userID, viewerID)
+}
+
+// checkNotBlocked returns ErrTopicForbidden if authorID has blocked viewerID
+func (a *StoreTopicAuthorizer) checkNotBlocked(ctx context.Context, authorID, viewerID uuid.UUID) error {
+    blocked, err := a.blockStore.IsBlocked(ctx, authorID, viewerID)
+    if err != nil {
+        return fmt.Errorf("failed to check block status: %w", err)
+    }
+    if blocked {
+        return ErrTopicForbidden
+    }
+    return nil
+}
@@ -0,0 +1,117 @@
+package realtime
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/google/uuid"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+)
+
+const (
+    // typingAuthorizeTimeout bounds the lookups that authorize a typing event
+    typingAuthorizeTimeout = 5 * time.Second
+
+    // typingGrantTTL is how long a client may keep sending typing events to a
+    // conversation before it is checked again, so a new block takes effect
+    typingGrantTTL = time.Minute
+)
+
+// Errors returned when authorizing a typing event
+var (
+    ErrConversationNotFound = errors.New("conversation not found")
+    ErrTypingForbidden      = errors.New("not allowed to send typing events to this conversation")
+)
+
+// TypingAuthorizer decides whether a user may send typing events to a
+// conversation, and returns who receives them
+type TypingAuthorizer interface {
+    AuthorizeTyping(ctx context.Context, user *models.User, conversationID uuid.UUID) (uuid.UUID, error)
+}
+
+// StoreTypingAuthorizer allows typing events in the sender's own conversations
+// when neither participant has blocked the other
+type StoreTypingAuthorizer struct {
+    messageStore storage.MessageStore
+    blockStore   storage.BlockStore
+}
+
+// NewTypingAuthorizer creates a typing authorizer backed by the stores
+func NewTypingAuthorizer(messageStore storage.MessageStore, blockStore storage.BlockStore) *StoreTypingAuthorizer {
+    return &StoreTypingAuthorizer{
+        messageStore: messageStore,
+        blockStore:   blockStore,
+    }
+}
+
+// AuthorizeTyping returns the other participant of the conversation if the
+// user may send them typing events
+func (a *StoreTypingAuthorizer) AuthorizeTyping(ctx context.Context, user *models.User, conversationID uuid.UUID) (uuid.UUID, error) {
+    conversation, err := a.messageStore.GetConversation(ctx, conversationID)
+    if err != nil {
+        if err == storage.ErrNotFound {
+            return uuid.Nil, ErrConversationNotFound
+        }
+        return uuid.Nil, fmt.Errorf("failed to get conversation: %w", err)
+    }
+
+    // Conversations the user isn't part of look the same as missing ones
+    if !conversation.HasParticipant(user.ID) {
+        return uuid.Nil, ErrConversationNotFound
+    }
+    recipientID := conversation.OtherParticipant(user.ID)
+
+    for _, pair := range [][2]uuid.UUID{{recipientID, user.ID}, {user.ID, recipientID}} {
+        blocked, err := a.blockStore.IsBlocked(ctx, pair[0], pair[1])
+        if err != nil {
+            return uuid.Nil, fmt.Errorf("failed to check block status: %w", err)
+        }
+        if blocked {
+            return uuid.Nil, ErrTypingForbidden
+        }
+    }
+
+    return recipientID, nil
+}
+
+// typingGrant is a conversation a client was recently allowed to type in
+type typingGrant struct {
+    recipientID uuid.UUID
+    expiresAt   time.Time
+}
+
+// typingRecipient authorizes a typing event, reusing a recent check for the
+// same conversation. It is only used from the client's read goroutine.
+func (c *Client) typingRecipient(conversationID uuid.UUID) (uuid.UUID, error) {
+    now := time.Now()
+    if grant, ok := c.typingGrants[conversationID]; ok && now.Before(grant.expiresAt) {
+        return grant.recipientID, nil
+    }
+
+    if c.hub.typing == nil {
+        return uuid.Nil, ErrTypingForbidden
+    }
+
+    ctx, cancel := context.WithTimeout(context.Background(), typingAuthorizeTimeout)
+    defer cancel()
+
+    recipientID, err := c.hub.typing.AuthorizeTyping(ctx, c.User, conversationID)
+    if err != nil {
+        delete(c.typingGrants, conversationID)
+        return uuid.Nil, err
+    }
+
+    if c.typingGrants == nil {
+        c.typingGrants = make(map[uuid.UUID]typingGrant)
+    }
+    c.typingGrants[conversationID] = typingGrant{
+        recipientID: recipientID,
+        expiresAt:   now.Add(typingGrantTTL),
+    }
+
+    return recipientID, nil
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/google/uuid"
+    "github.com/jmoiron/sqlx"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+// BlockStoreImpl implements BlockStore interface
+type BlockStoreImpl struct {
+    db          *sqlx.DB
+    redisClient *RedisClient
+    logger      *zap.Logger
+}
+
+// NewBlockStore creates a new block store
+func NewBlockStore(db *sqlx.DB, redisClient *RedisClient, logger *zap.Logger) BlockStore {
+    return &BlockStoreImpl{
+        db:          db,
+        redisClient: redisClient,
+        logger:      logger.With(zap.String("store", "block")),
+    }
+}
+
+// Create blocks a user, returning ErrAlreadyExists if already blocked
+func (s *BlockStoreImpl) Create(ctx context.Context, block *models.Block) error {
+    query := `
+        INSERT INTO blocks (id, blocker_id, blocked_id, created_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (blocker_id, blocked_id) DO NOTHING`
+
+    result, err := s.db.ExecContext(ctx, query, block.ID, block.BlockerID, block.BlockedID, block.CreatedAt)
+    if err != nil {
+        return fmt.Errorf("failed to create block: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return ErrAlreadyExists
+    }
+
+    s.redisClient.Delete(ctx, blockCacheKey(block.BlockerID, block.BlockedID))
+
+    s.logger.Info("User blocked",
+        zap.String("blocker_id", block.BlockerID.String()),
+        zap.String("blocked_id", block.BlockedID.String()),
+    )
+
+    return nil
+}
+
+// Delete unblocks a user
+func (s *BlockStoreImpl) Delete(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+    result, err := s.db.ExecContext(ctx,
+        "DELETE FROM blocks WHERE blocker_id = $1 AND blocked_id = $2",
+        blockerID, blockedID)
+    if err != nil {
+        return fmt.Errorf("failed to delete block: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return ErrNotFound
+    }
+
+    s.redisClient.Delete(ctx, blockCacheKey(blockerID, blockedID))
+
+    return nil
+}
+
+// IsBlocked checks if blockerID has blocked blockedID
+func (s *BlockStoreImpl) IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error) {
+    cacheKey := blockCacheKey(blockerID, blockedID)
+
+    // Check cache first
+    var result bool
+    if err := s.redisClient.Get(ctx, cacheKey, &result); err == nil {
+        return result, nil
+    }
+
+    var count int
+    query := `SELECT COUNT(*) FROM blocks WHERE blocker_id = $1 AND blocked_id = $2`
+    err := s.db.GetContext(ctx, &count, query, blockerID, blockedID)
+    if err != nil {
+        return false, fmt.Errorf("failed to check block status: %w", err)
+    }
+
+    isBlocked := count > 0
+
+    // Cache the result
+    s.redisClient.Set(ctx, cacheKey, isBlocked, 300) // Cache for 5 minutes
+
+    return isBlocked, nil
+}
+
+// GetBlockedIDs gets the IDs of every user blockerID has blocked
+func (s *BlockStoreImpl) GetBlockedIDs(ctx context.Context, blockerID uuid.UUID) ([]uuid.UUID, error) {
+    var ids []uuid.UUID
+    query := `SELECT blocked_id FROM blocks WHERE blocker_id = $1`
+    if err := s.db.SelectContext(ctx, &ids, query, blockerID); err != nil {
+        return nil, fmt.Errorf("failed to get blocked IDs: %w", err)
+    }
+
+    return ids, nil
+}
+
+func blockCacheKey(blockerID, blockedID uuid.UUID) string {
+    return fmt.Sprintf("block:%s:%s", blockerID.String(), blockedID.String())
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/google/uuid"
+    "github.com/jmoiron/sqlx"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+// CounterStoreImpl implements CounterStore interface
+type CounterStoreImpl struct {
+    db          *sqlx.DB
+    redisClient *RedisClient
+    logger      *zap.Logger
+}
+
+// NewCounterStore creates a new counter store
+func NewCounterStore(db *sqlx.DB, redisClient *RedisClient, logger *zap.Logger) CounterStore {
+    return &CounterStoreImpl{
+        db:          db,
+        redisClient: redisClient,
+        logger:      logger.With(zap.String("store", "counter")),
+    }
+}
+
+// ReconcileStoryCounters recomputes the reaction and share counts of up to limit
+// drifted stories, returning what was corrected
+func (s *CounterStoreImpl) ReconcileStoryCounters(ctx context.Context, limit int) ([]*models.CounterCorrection, error) {
+    query := `
+        WITH actual AS (
+            SELECT s.id, s.reaction_count, s.share_count,
+                   (SELECT COUNT(*) FROM reactions r WHERE r.story_id = s.id) as actual_reactions,
+                   (SELECT COUNT(*) FROM stories rs WHERE rs.original_story_id = s.id) as actual_shares
+            FROM stories s
+            WHERE s.deleted_at IS NULL
+        ), drifted AS (
+            SELECT * FROM actual
+            WHERE reaction_count <> actual_reactions OR share_count <> actual_shares
+            LIMIT $1
+        )
+        UPDATE stories s SET
+            reaction_count = d.actual_reactions,
+            share_count = d.actual_shares
+        FROM drifted d
+        WHERE s.id = d.id
+        RETURNING d.id, d.reaction_count, d.share_count, d.actual_reactions, d.actual_shares`
+
+    var rows []struct {
+        ID              uuid.UUID `db:"id"`
+        ReactionCount   int       `db:"reaction_count"`
+        ShareCount      int       `db:"share_count"`
+        ActualReactions int       `db:"actual_reactions"`
+        ActualShares    int       `db:"actual_shares"`
+    }
+    err := s.db.SelectContext(ctx, &rows, query, limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to reconcile story counters: %w", err)
+    }
+
+    var corrections []*models.CounterCorrection
+    cacheKeys := make([]string, 0, len(rows))
+    for _, row := range rows {
+        corrections = appendCorrection(corrections,
+            models.NewCounterCorrection(models.CounterEntityStory, row.ID, "reaction_count", row.ReactionCount, row.ActualReactions))
+        corrections = appendCorrection(corrections,
+            models.NewCounterCorrection(models.CounterEntityStory, row.ID, "share_count", row.ShareCount, row.ActualShares))
+        cacheKeys = append(cacheKeys, fmt.Sprintf("story:%s", row.ID.String()))
+    }
+
+    if len(cacheKeys) > 0 {
+        s.redisClient.DeleteMany(ctx, cacheKeys)
+    }
+
+    return corrections, nil
+}
+
+// ReconcileUserCounters recomputes the follower, following and story counts of up
+// to limit drifted users, returning what was corrected. Story counts only
+// include published stories, as in StoryStore.CountByAuthorID.
+func (s *CounterStoreImpl) ReconcileUserCounters(ctx context.Context, limit int) ([]*models.CounterCorrection, error) {
+    query := `
+        WITH actual AS (
+            SELECT u.id, u.follower_count, u.following_count, u.story_count,
+                   (SELECT COUNT(*) FROM follows f WHERE f.followee_id = u.id) as actual_followers,
+                   (SELECT COUNT(*) FROM follows f WHERE f.follower_id = u.id) as actual_following,
+                   (SELECT COUNT(*) FROM stories st
+                    WHERE st.author_id = u.id AND st.deleted_at IS NULL AND st.status = 'published') as actual_stories
+            FROM users u
+            WHERE u.deleted_at IS NULL
+        ), drifted AS (
+            SELECT * FROM actual
+            WHERE follower_count <> actual_followers
+            OR following_count <> actual_following
+            OR story_count <> actual_stories
+            LIMIT $1
+        )
+        UPDATE users u SET
+            follower_count = d.actual_followers,
+            following_count = d.actual_following,
+            story_count = d.actual_stories
+        FROM drifted d
+        WHERE u.id = d.id
+        RETURNING d.id, d.follower_count, d.following_count, d.story_count,
+                  d.actual_followers, d.actual_following, d.actual_stories`
+
+    var rows []struct {
+        ID              uuid.UUID `db:"id"`
+        FollowerCount   int       `db:"follower_count"`
+        FollowingCount  int       `db:"following_count"`
+        StoryCount      int       `db:"story_count"`
+        ActualFollowers int       `db:"actual_followers"`
+        ActualFollowing int       `db:"actual_following"`
+        ActualStories   int       `db:"actual_stories"`
+    }
+    err := s.db.SelectContext(ctx, &rows, query, limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to reconcile user counters: %w", err)
+    }
+
+    var corrections []*models.CounterCorrection
+    cacheKeys := make([]string, 0, len(rows))
+    for _, row := range rows {
+        corrections = appendCorrection(corrections,
+            models.NewCounterCorrection(models.CounterEntityUser, row.ID, "follower_count", row.FollowerCount, row.ActualFollowers))
+        corrections = appendCorrection(corrections,
+            models.NewCounterCorrection(models.CounterEntityUser, row.ID, "following_count", row.FollowingCount, row.ActualFollowing))
+        corrections = appendCorrection(corrections,
+            models.NewCounterCorrection(models.CounterEntityUser, row.ID, "story_count", row.StoryCount, row.ActualStories))
+        cacheKeys = append(cacheKeys, fmt.Sprintf("user:%s", row.ID.String()))
+    }
+
+    if len(cacheKeys) > 0 {
+        s.redisClient.DeleteMany(ctx, cacheKeys)
+    }
+
+    return corrections, nil
+}
+
+// appendCorrection adds a correction to the list, skipping counters that were right
+func appendCorrection(corrections []*models.CounterCorrection, correction *models.CounterCorrection) []*models.CounterCorrection {
+    if correction == nil {
+        return corrections
+    }
+    return append(corrections, correction)
+}
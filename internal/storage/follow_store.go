@@ -216,6 +216,23 @@ func (s *FollowStoreImpl) GetFollowing(ctx context.Context, userID uuid.UUID, li
     return follows, nil
 }
 
+// GetFollowingIDs gets the IDs of every user a user is following
+func (s *FollowStoreImpl) GetFollowingIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+    query := `
+        SELECT f.followee_id
+        FROM follows f
+        JOIN users u ON f.followee_id = u.id
+        WHERE f.follower_id = $1 AND u.deleted_at IS NULL`
+
+    var ids []uuid.UUID
+    err := s.db.SelectContext(ctx, &ids, query, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get following IDs: %w", err)
+    }
+
+    return ids, nil
+}
+
 // IsFollowing checks if user A follows user B
 func (s *FollowStoreImpl) IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error) {
     cacheKey := fmt.Sprintf("follow:%s:%s", followerID.String(), followeeID.String())
@@ -278,6 +295,24 @@ func (s *FollowStoreImpl) GetMutualFollows(ctx context.Context, userID1, userID2
     return &mutual, nil
 }
 
+// GetMutualFollowIDs gets the IDs of the users who follow a user and are followed back
+func (s *FollowStoreImpl) GetMutualFollowIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+    query := `
+        SELECT f.followee_id
+        FROM follows f
+        JOIN follows b ON b.follower_id = f.followee_id AND b.followee_id = f.follower_id
+        JOIN users u ON f.followee_id = u.id
+        WHERE f.follower_id = $1 AND u.deleted_at IS NULL`
+
+    var ids []uuid.UUID
+    err := s.db.SelectContext(ctx, &ids, query, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get mutual follow IDs: %w", err)
+    }
+
+    return ids, nil
+}
+
 // GetFollowSuggestions gets follow suggestions for a user - ADDED MISSING METHOD
 func (s *FollowStoreImpl) GetFollowSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]*models.FollowSuggestion, error) {
     query := `
@@ -3,6 +3,7 @@ package storage
 import (
     "context"
     "errors"
+    "time"
 
     "github.com/google/uuid"
 
@@ -23,6 +24,7 @@ type UserStore interface {
     GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
     GetByEmail(ctx context.Context, email string) (*models.User, error)
     GetByUsername(ctx context.Context, username string) (*models.User, error)
+    GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.User, error)
     Update(ctx context.Context, user *models.User) error
     Delete(ctx context.Context, id uuid.UUID) error
     Search(ctx context.Context, query string, limit, offset int) ([]*models.User, error)
@@ -36,12 +38,29 @@ type StoryStore interface {
     Create(ctx context.Context, story *models.Story) error
     GetByID(ctx context.Context, id uuid.UUID) (*models.Story, error)
     GetByAuthorID(ctx context.Context, authorID uuid.UUID, limit, offset int) ([]*models.Story, error)
+    CountByAuthorID(ctx context.Context, authorID uuid.UUID) (int, error)
     GetFeed(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Story, error)
     GetPublic(ctx context.Context, limit, offset int) ([]*models.Story, error)
-    Update(ctx context.Context, story *models.Story) error
+    Update(ctx context.Context, story *models.Story, revision *models.StoryRevision) error
+    GetRevisions(ctx context.Context, storyID uuid.UUID) ([]*models.StoryRevision, error)
     Delete(ctx context.Context, id uuid.UUID) error
     GetExpired(ctx context.Context, limit, offset int) ([]*models.Story, error)
-    IncrementViewCount(ctx context.Context, storyID uuid.UUID) error
+    GetDrafts(ctx context.Context, authorID uuid.UUID, limit, offset int) ([]*models.Story, error)
+    UpdateDraft(ctx context.Context, story *models.Story) error
+    Publish(ctx context.Context, story *models.Story) error
+    DeleteStaleDrafts(ctx context.Context, olderThan time.Time) (int64, error)
+    Archive(ctx context.Context, story *models.Story) error
+    HardDelete(ctx context.Context, id uuid.UUID) error
+    GetArchived(ctx context.Context, authorID uuid.UUID, limit, offset int) ([]*models.Story, error)
+    GetSegments(ctx context.Context, storyID uuid.UUID) ([]*models.StorySegment, error)
+    GetStickers(ctx context.Context, storyID uuid.UUID) ([]*models.Sticker, error)
+    GetEntities(ctx context.Context, storyID uuid.UUID) ([]*models.StoryEntity, error)
+    SetEntities(ctx context.Context, storyID uuid.UUID, entities []*models.StoryEntity) error
+    GetByHashtag(ctx context.Context, tag string, limit, offset int) ([]*models.Story, error)
+    GetNearby(ctx context.Context, query models.NearbyStoriesQuery, limit, offset int) ([]*models.Story, error)
+    GetMetrics(ctx context.Context, storyID uuid.UUID) (*StoryMetrics, error)
+    InvalidateCache(ctx context.Context, storyID uuid.UUID)
+    ApplyViewCounts(ctx context.Context, deltas []*models.ViewCountDelta) error
     GetViewCount(ctx context.Context, storyID uuid.UUID) (int, error)
 }
 
@@ -53,9 +72,11 @@ type FollowStore interface {
     GetByID(ctx context.Context, id uuid.UUID) (*models.Follow, error)
     GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.FollowWithUser, error)
     GetFollowing(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.FollowWithUser, error)
+    GetFollowingIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
     IsFollowing(ctx context.Context, followerID, followeeID uuid.UUID) (bool, error)
     GetFollowStats(ctx context.Context, userID uuid.UUID) (*models.FollowStats, error)
     GetMutualFollows(ctx context.Context, userID1, userID2 uuid.UUID) (*models.MutualFollowCheck, error)
+    GetMutualFollowIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
     GetFollowSuggestions(ctx context.Context, userID uuid.UUID, limit int) ([]*models.FollowSuggestion, error)
 }
 
@@ -64,12 +85,35 @@ type ViewStore interface {
     Create(ctx context.Context, view *models.StoryView) error
     GetByID(ctx context.Context, id uuid.UUID) (*models.StoryView, error)
     GetByStoryID(ctx context.Context, storyID uuid.UUID, limit, offset int) ([]*models.StoryViewWithUser, error)
+    GetSeenBy(ctx context.Context, storyID, authorID uuid.UUID, limit int) (*models.SeenBySummary, error)
     GetByViewerID(ctx context.Context, viewerID uuid.UUID, limit, offset int) ([]*models.StoryView, error)
     GetViewStats(ctx context.Context, storyID uuid.UUID) (*models.StoryViewStats, error)
     GetViewerStats(ctx context.Context, viewerID uuid.UUID) (*models.ViewerStats, error)
     HasViewed(ctx context.Context, storyID, viewerID uuid.UUID) (bool, error)
     GetViewAnalytics(ctx context.Context, storyID uuid.UUID, period string) (*models.ViewAnalytics, error)
     GetViewTrends(ctx context.Context, storyID uuid.UUID) ([]*models.ViewTrend, error)
+    CreateSegmentView(ctx context.Context, view *models.SegmentView) error
+    GetSegmentDropOff(ctx context.Context, storyID uuid.UUID) (*models.SegmentDropOffReport, error)
+}
+
+// ViewCounter defines the interface for deduplicated view counting in Redis
+type ViewCounter interface {
+    Record(ctx context.Context, story *models.Story, viewerID uuid.UUID) (bool, error)
+    GetPending(ctx context.Context, storyID uuid.UUID) (*models.ViewCountDelta, error)
+    Drain(ctx context.Context, limit int) ([]*models.ViewCountDelta, error)
+    Restore(ctx context.Context, deltas []*models.ViewCountDelta) error
+}
+
+// PresenceStore defines the interface for users' last active times in Redis
+type PresenceStore interface {
+    Touch(ctx context.Context, userID uuid.UUID, at time.Time) error
+    GetLastActive(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]time.Time, error)
+}
+
+// CounterStore defines the interface for reconciling denormalized counters
+type CounterStore interface {
+    ReconcileStoryCounters(ctx context.Context, limit int) ([]*models.CounterCorrection, error)
+    ReconcileUserCounters(ctx context.Context, limit int) ([]*models.CounterCorrection, error)
 }
 
 // ReactionStore defines the interface for reaction storage operations
@@ -85,6 +129,40 @@ type ReactionStore interface {
     GetReactionStats(ctx context.Context, storyID uuid.UUID) (map[models.ReactionType]int, error)
 }
 
+// ReactionTypeStore defines the interface for reaction catalogue storage operations
+type ReactionTypeStore interface {
+    Create(ctx context.Context, definition *models.ReactionDefinition) error
+    Retire(ctx context.Context, reactionType models.ReactionType) error
+    GetActive(ctx context.Context) ([]*models.ReactionDefinition, error)
+    GetAll(ctx context.Context) ([]*models.ReactionDefinition, error)
+}
+
+// StickerStore defines the interface for sticker response storage operations
+type StickerStore interface {
+    CreateResponse(ctx context.Context, response *models.StickerResponse) error
+    GetResponses(ctx context.Context, stickerID uuid.UUID, limit, offset int) ([]*models.StickerResponseWithUser, error)
+    GetResults(ctx context.Context, sticker *models.Sticker, limit int) (*models.StickerResults, error)
+    DeleteByStoryID(ctx context.Context, storyID uuid.UUID) error
+}
+
+// MessageStore defines the interface for private conversation storage operations
+type MessageStore interface {
+    GetOrCreateConversation(ctx context.Context, userID, otherUserID uuid.UUID) (*models.Conversation, error)
+    GetConversation(ctx context.Context, id uuid.UUID) (*models.Conversation, error)
+    GetConversations(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.ConversationWithUser, error)
+    CreateMessage(ctx context.Context, message *models.Message) error
+    GetMessages(ctx context.Context, conversationID uuid.UUID, limit, offset int) ([]*models.Message, error)
+    MarkRead(ctx context.Context, conversationID, readerID uuid.UUID) (int64, error)
+}
+
+// BlockStore defines the interface for user block storage operations
+type BlockStore interface {
+    Create(ctx context.Context, block *models.Block) error
+    Delete(ctx context.Context, blockerID, blockedID uuid.UUID) error
+    IsBlocked(ctx context.Context, blockerID, blockedID uuid.UUID) (bool, error)
+    GetBlockedIDs(ctx context.Context, blockerID uuid.UUID) ([]uuid.UUID, error)
+}
+
 // CacheStore defines the interface for caching operations
 type CacheStore interface {
     Set(ctx context.Context, key string, value interface{}, expiration int) error
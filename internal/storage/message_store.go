@@ -0,0 +1,183 @@
+package storage
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+
+    "github.com/google/uuid"
+    "github.com/jmoiron/sqlx"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+// MessageStoreImpl implements MessageStore interface
+type MessageStoreImpl struct {
+    db          *sqlx.DB
+    redisClient *RedisClient
+    logger      *zap.Logger
+}
+
+// NewMessageStore creates a new message store
+func NewMessageStore(db *sqlx.DB, redisClient *RedisClient, logger *zap.Logger) MessageStore {
+    return &MessageStoreImpl{
+        db:          db,
+        redisClient: redisClient,
+        logger:      logger.With(zap.String("store", "message")),
+    }
+}
+
+// GetOrCreateConversation gets the conversation between two users, creating it if needed
+func (s *MessageStoreImpl) GetOrCreateConversation(ctx context.Context, userID, otherUserID uuid.UUID) (*models.Conversation, error) {
+    conversation := models.NewConversation(userID, otherUserID)
+
+    // The no-op update makes RETURNING yield the existing row on conflict
+    query := `
+        INSERT INTO conversations (id, user_a_id, user_b_id, last_message_at, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_a_id, user_b_id) DO UPDATE SET user_a_id = EXCLUDED.user_a_id
+        RETURNING id, user_a_id, user_b_id, last_message_at, created_at`
+
+    var result models.Conversation
+    err := s.db.GetContext(ctx, &result, query,
+        conversation.ID, conversation.UserAID, conversation.UserBID,
+        conversation.LastMessageAt, conversation.CreatedAt,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to get or create conversation: %w", err)
+    }
+
+    return &result, nil
+}
+
+// GetConversation gets a conversation by ID
+func (s *MessageStoreImpl) GetConversation(ctx context.Context, id uuid.UUID) (*models.Conversation, error) {
+    var conversation models.Conversation
+    query := `
+        SELECT id, user_a_id, user_b_id, last_message_at, created_at
+        FROM conversations
+        WHERE id = $1`
+
+    err := s.db.GetContext(ctx, &conversation, query, id)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, ErrNotFound
+        }
+        return nil, fmt.Errorf("failed to get conversation: %w", err)
+    }
+
+    return &conversation, nil
+}
+
+// GetConversations gets a user's conversations, most recently active first
+func (s *MessageStoreImpl) GetConversations(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.ConversationWithUser, error) {
+    query := `
+        SELECT c.id, c.user_a_id, c.user_b_id, c.last_message_at, c.created_at,
+               u.id as other_user_id, u.username as other_username,
+               u.full_name as other_full_name, u.profile_picture as other_profile_picture,
+               u.is_verified as other_is_verified,
+               (SELECT m.text FROM messages m 
+                WHERE m.conversation_id = c.id 
+                ORDER BY m.created_at DESC LIMIT 1) as last_message_text,
+               (SELECT COUNT(*) FROM messages m 
+                WHERE m.conversation_id = c.id 
+                AND m.recipient_id = $1 AND m.read_at IS NULL) as unread_count
+        FROM conversations c
+        JOIN users u ON u.id = CASE WHEN c.user_a_id = $1 THEN c.user_b_id ELSE c.user_a_id END
+        WHERE (c.user_a_id = $1 OR c.user_b_id = $1) AND u.deleted_at IS NULL
+        ORDER BY c.last_message_at DESC
+        LIMIT $2 OFFSET $3`
+
+    var conversations []*models.ConversationWithUser
+    err := s.db.SelectContext(ctx, &conversations, query, userID, limit, offset)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get conversations: %w", err)
+    }
+
+    return conversations, nil
+}
+
+// CreateMessage creates a message and bumps the conversation's activity time
+func (s *MessageStoreImpl) CreateMessage(ctx context.Context, message *models.Message) error {
+    tx, err := s.db.BeginTxx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    query := `
+        INSERT INTO messages (
+            id, conversation_id, sender_id, recipient_id, text, 
+            story_id, story_preview, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8
+        )`
+
+    _, err = tx.ExecContext(ctx, query,
+        message.ID, message.ConversationID, message.SenderID, message.RecipientID,
+        message.Text, message.StoryID, message.StoryPreview, message.CreatedAt,
+    )
+    if err != nil {
+        s.logger.Error("Failed to create message", zap.Error(err))
+        return fmt.Errorf("failed to create message: %w", err)
+    }
+
+    _, err = tx.ExecContext(ctx,
+        "UPDATE conversations SET last_message_at = $1 WHERE id = $2",
+        message.CreatedAt, message.ConversationID)
+    if err != nil {
+        return fmt.Errorf("failed to update conversation: %w", err)
+    }
+
+    if err = tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit transaction: %w", err)
+    }
+
+    s.logger.Debug("Message created",
+        zap.String("conversation_id", message.ConversationID.String()),
+        zap.String("sender_id", message.SenderID.String()),
+    )
+
+    return nil
+}
+
+// GetMessages gets messages in a conversation, newest first
+func (s *MessageStoreImpl) GetMessages(ctx context.Context, conversationID uuid.UUID, limit, offset int) ([]*models.Message, error) {
+    query := `
+        SELECT id, conversation_id, sender_id, recipient_id, text,
+               story_id, story_preview, read_at, created_at
+        FROM messages
+        WHERE conversation_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2 OFFSET $3`
+
+    var messages []*models.Message
+    err := s.db.SelectContext(ctx, &messages, query, conversationID, limit, offset)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get messages: %w", err)
+    }
+
+    return messages, nil
+}
+
+// MarkRead marks all messages sent to readerID in a conversation as read
+func (s *MessageStoreImpl) MarkRead(ctx context.Context, conversationID, readerID uuid.UUID) (int64, error) {
+    query := `
+        UPDATE messages 
+        SET read_at = $1
+        WHERE conversation_id = $2 AND recipient_id = $3 AND read_at IS NULL`
+
+    result, err := s.db.ExecContext(ctx, query, time.Now(), conversationID, readerID)
+    if err != nil {
+        return 0, fmt.Errorf("failed to mark messages read: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return 0, fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    return rowsAffected, nil
+}
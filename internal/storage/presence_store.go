@@ -0,0 +1,75 @@
+package storage
+
+import (
+    "context"
+    "fmt"
+    "strconv"
+    "time"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+)
+
+// lastActiveTTL is how long a user's last active time is kept after they go quiet
+const lastActiveTTL = 30 * 24 * time.Hour
+
+// PresenceStoreImpl implements PresenceStore interface using Redis
+type PresenceStoreImpl struct {
+    redisClient *RedisClient
+    logger      *zap.Logger
+}
+
+// NewPresenceStore creates a new presence store
+func NewPresenceStore(redisClient *RedisClient, logger *zap.Logger) PresenceStore {
+    return &PresenceStoreImpl{
+        redisClient: redisClient,
+        logger:      logger.With(zap.String("store", "presence")),
+    }
+}
+
+// Touch records that a user was active at the given time
+func (s *PresenceStoreImpl) Touch(ctx context.Context, userID uuid.UUID, at time.Time) error {
+    err := s.redisClient.GetClient().Set(ctx, lastActiveKey(userID), at.Unix(), lastActiveTTL).Err()
+    if err != nil {
+        return fmt.Errorf("failed to record last active time: %w", err)
+    }
+    return nil
+}
+
+// GetLastActive gets the last active times of the given users. Users with no
+// recorded activity are left out of the result.
+func (s *PresenceStoreImpl) GetLastActive(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+    lastActive := make(map[uuid.UUID]time.Time, len(userIDs))
+    if len(userIDs) == 0 {
+        return lastActive, nil
+    }
+
+    keys := make([]string, len(userIDs))
+    for i, userID := range userIDs {
+        keys[i] = lastActiveKey(userID)
+    }
+
+    values, err := s.redisClient.GetClient().MGet(ctx, keys...).Result()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get last active times: %w", err)
+    }
+
+    for i, value := range values {
+        str, ok := value.(string)
+        if !ok {
+            continue
+        }
+        unix, err := strconv.ParseInt(str, 10, 64)
+        if err != nil {
+            continue
+        }
+        lastActive[userIDs[i]] = time.Unix(unix, 0)
+    }
+
+    return lastActive, nil
+}
+
+// lastActiveKey holds the unix time a user was last active
+func lastActiveKey(userID uuid.UUID) string {
+    return fmt.Sprintf("presence:last_active:%s", userID.String())
+}
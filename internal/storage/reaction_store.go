@@ -28,16 +28,26 @@ func NewReactionStore(db *sqlx.DB, redisClient *RedisClient, logger *zap.Logger)
     }
 }
 
-// Create creates a new reaction
+// Create creates a new reaction, or changes the type of the user's existing
+// reaction. The story's reaction count only goes up for a new reaction.
 func (s *ReactionStoreImpl) Create(ctx context.Context, reaction *models.Reaction) error {
+    tx, err := s.db.BeginTxx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    // xmax is only zero for a freshly inserted row
     query := `
         INSERT INTO reactions (id, story_id, user_id, type, created_at, updated_at)
         VALUES ($1, $2, $3, $4, $5, $6)
         ON CONFLICT (story_id, user_id) DO UPDATE SET
             type = EXCLUDED.type,
-            updated_at = EXCLUDED.updated_at`
+            updated_at = EXCLUDED.updated_at
+        RETURNING (xmax = 0) as inserted`
 
-    _, err := s.db.ExecContext(ctx, query,
+    var inserted bool
+    err = tx.GetContext(ctx, &inserted, query,
         reaction.ID, reaction.StoryID, reaction.UserID,
         reaction.Type, reaction.CreatedAt, reaction.UpdatedAt,
     )
@@ -47,11 +57,29 @@ func (s *ReactionStoreImpl) Create(ctx context.Context, reaction *models.Reactio
         return fmt.Errorf("failed to create reaction: %w", err)
     }
 
+    if inserted {
+        _, err = tx.ExecContext(ctx,
+            "UPDATE stories SET reaction_count = reaction_count + 1 WHERE id = $1",
+            reaction.StoryID)
+        if err != nil {
+            return fmt.Errorf("failed to update reaction count: %w", err)
+        }
+    }
+
+    if err = tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit transaction: %w", err)
+    }
+
+    if inserted {
+        s.invalidateStoryCache(ctx, reaction.StoryID)
+    }
+
     s.logger.Debug("Reaction created",
         zap.String("reaction_id", reaction.ID.String()),
         zap.String("story_id", reaction.StoryID.String()),
         zap.String("user_id", reaction.UserID.String()),
         zap.String("type", string(reaction.Type)),
+        zap.Bool("inserted", inserted),
     )
 
     return nil
@@ -143,24 +171,37 @@ func (s *ReactionStoreImpl) Update(ctx context.Context, reaction *models.Reactio
     return nil
 }
 
-// Delete deletes a reaction
+// Delete deletes a reaction and takes it off the story's reaction count
 func (s *ReactionStoreImpl) Delete(ctx context.Context, id uuid.UUID) error {
-    query := `DELETE FROM reactions WHERE id = $1`
+    tx, err := s.db.BeginTxx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
 
-    result, err := s.db.ExecContext(ctx, query, id)
+    var storyID uuid.UUID
+    err = tx.GetContext(ctx, &storyID,
+        "DELETE FROM reactions WHERE id = $1 RETURNING story_id", id)
     if err != nil {
+        if err == sql.ErrNoRows {
+            return ErrNotFound
+        }
         return fmt.Errorf("failed to delete reaction: %w", err)
     }
 
-    rowsAffected, err := result.RowsAffected()
+    _, err = tx.ExecContext(ctx,
+        "UPDATE stories SET reaction_count = GREATEST(reaction_count - 1, 0) WHERE id = $1",
+        storyID)
     if err != nil {
-        return fmt.Errorf("failed to get rows affected: %w", err)
+        return fmt.Errorf("failed to update reaction count: %w", err)
     }
 
-    if rowsAffected == 0 {
-        return ErrNotFound
+    if err = tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit transaction: %w", err)
     }
 
+    s.invalidateStoryCache(ctx, storyID)
+
     s.logger.Debug("Reaction deleted", zap.String("reaction_id", id.String()))
     return nil
 }
@@ -186,21 +227,21 @@ func (s *ReactionStoreImpl) GetUserReactionForStory(ctx context.Context, storyID
 
 // GetReactionSummary gets a summary of reactions for a story
 func (s *ReactionStoreImpl) GetReactionSummary(ctx context.Context, storyID uuid.UUID) (*models.ReactionSummary, error) {
-    // Get total count and type breakdown
+    // Get total count and type breakdown. Catalogue types carry their emoji and
+    // name; arbitrary emoji reactions are their own emoji.
     statsQuery := `
         SELECT 
-            type,
+            r.type,
+            COALESCE(rt.emoji, r.type) as emoji,
+            rt.display_name,
             COUNT(*) as count
-        FROM reactions
-        WHERE story_id = $1
-        GROUP BY type`
-
-    type reactionStat struct {
-        Type  models.ReactionType `db:"type"`
-        Count int                 `db:"count"`
-    }
+        FROM reactions r
+        LEFT JOIN reaction_types rt ON rt.type = r.type
+        WHERE r.story_id = $1
+        GROUP BY r.type, rt.emoji, rt.display_name, rt.position
+        ORDER BY count DESC, rt.position NULLS LAST, r.type`
 
-    var stats []reactionStat
+    var stats []models.ReactionTypeCount
     err := s.db.SelectContext(ctx, &stats, statsQuery, storyID)
     if err != nil {
         return nil, fmt.Errorf("failed to get reaction stats: %w", err)
@@ -209,6 +250,7 @@ func (s *ReactionStoreImpl) GetReactionSummary(ctx context.Context, storyID uuid
     summary := &models.ReactionSummary{
         StoryID:        storyID,
         ReactionCounts: make(map[models.ReactionType]int),
+        Breakdown:      stats,
     }
 
     totalReactions := 0
@@ -265,3 +307,8 @@ func (s *ReactionStoreImpl) GetReactionStats(ctx context.Context, storyID uuid.U
 
     return result, nil
 }
+
+// invalidateStoryCache drops the cached story after its reaction count changed
+func (s *ReactionStoreImpl) invalidateStoryCache(ctx context.Context, storyID uuid.UUID) {
+    s.redisClient.Delete(ctx, fmt.Sprintf("story:%s", storyID.String()))
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/jmoiron/sqlx"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+// activeReactionTypesCacheKey caches the catalogue used to validate every reaction
+const activeReactionTypesCacheKey = "reaction_types:active"
+
+// ReactionTypeStoreImpl implements ReactionTypeStore interface
+type ReactionTypeStoreImpl struct {
+    db          *sqlx.DB
+    redisClient *RedisClient
+    logger      *zap.Logger
+}
+
+// NewReactionTypeStore creates a new reaction type store
+func NewReactionTypeStore(db *sqlx.DB, redisClient *RedisClient, logger *zap.Logger) ReactionTypeStore {
+    return &ReactionTypeStoreImpl{
+        db:          db,
+        redisClient: redisClient,
+        logger:      logger.With(zap.String("store", "reaction_type")),
+    }
+}
+
+// Create adds a reaction type to the catalogue, returning ErrAlreadyExists
+// if the key or emoji is already taken
+func (s *ReactionTypeStoreImpl) Create(ctx context.Context, definition *models.ReactionDefinition) error {
+    query := `
+        INSERT INTO reaction_types (type, emoji, display_name, is_positive, position, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT DO NOTHING`
+
+    result, err := s.db.ExecContext(ctx, query,
+        definition.Type, definition.Emoji, definition.DisplayName,
+        definition.IsPositive, definition.Position, definition.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create reaction type: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return ErrAlreadyExists
+    }
+
+    s.redisClient.Delete(ctx, activeReactionTypesCacheKey)
+
+    s.logger.Info("Reaction type created", zap.String("type", string(definition.Type)))
+    return nil
+}
+
+// Retire stops a reaction type from being used for new reactions.
+// Existing reactions of that type are kept and still counted.
+func (s *ReactionTypeStoreImpl) Retire(ctx context.Context, reactionType models.ReactionType) error {
+    query := `
+        UPDATE reaction_types SET retired_at = $2
+        WHERE type = $1 AND retired_at IS NULL`
+
+    result, err := s.db.ExecContext(ctx, query, reactionType, time.Now())
+    if err != nil {
+        return fmt.Errorf("failed to retire reaction type: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return ErrNotFound
+    }
+
+    s.redisClient.Delete(ctx, activeReactionTypesCacheKey)
+
+    s.logger.Info("Reaction type retired", zap.String("type", string(reactionType)))
+    return nil
+}
+
+// GetActive gets the reaction types available for new reactions, with caching
+func (s *ReactionTypeStoreImpl) GetActive(ctx context.Context) ([]*models.ReactionDefinition, error) {
+    var definitions []*models.ReactionDefinition
+    if err := s.redisClient.Get(ctx, activeReactionTypesCacheKey, &definitions); err == nil {
+        return definitions, nil
+    }
+
+    query := `
+        SELECT type, emoji, display_name, is_positive, position, retired_at, created_at
+        FROM reaction_types
+        WHERE retired_at IS NULL
+        ORDER BY position, created_at`
+
+    err := s.db.SelectContext(ctx, &definitions, query)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get active reaction types: %w", err)
+    }
+
+    // Cache the result
+    s.redisClient.Set(ctx, activeReactionTypesCacheKey, definitions, 300) // Cache for 5 minutes
+
+    return definitions, nil
+}
+
+// GetAll gets the whole catalogue, including retired reaction types
+func (s *ReactionTypeStoreImpl) GetAll(ctx context.Context) ([]*models.ReactionDefinition, error) {
+    query := `
+        SELECT type, emoji, display_name, is_positive, position, retired_at, created_at
+        FROM reaction_types
+        ORDER BY retired_at IS NOT NULL, position, created_at`
+
+    var definitions []*models.ReactionDefinition
+    err := s.db.SelectContext(ctx, &definitions, query)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get reaction types: %w", err)
+    }
+
+    return definitions, nil
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/google/uuid"
+    "github.com/jmoiron/sqlx"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+// StickerStoreImpl implements StickerStore interface
+type StickerStoreImpl struct {
+    db          *sqlx.DB
+    redisClient *RedisClient
+    logger      *zap.Logger
+}
+
+// NewStickerStore creates a new sticker store
+func NewStickerStore(db *sqlx.DB, redisClient *RedisClient, logger *zap.Logger) StickerStore {
+    return &StickerStoreImpl{
+        db:          db,
+        redisClient: redisClient,
+        logger:      logger.With(zap.String("store", "sticker")),
+    }
+}
+
+// CreateResponse records a viewer's response, returning ErrAlreadyExists if they already responded
+func (s *StickerStoreImpl) CreateResponse(ctx context.Context, response *models.StickerResponse) error {
+    query := `
+        INSERT INTO sticker_responses (
+            id, sticker_id, story_id, user_id, option_index, 
+            slider_value, text, is_correct, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9
+        )
+        ON CONFLICT (sticker_id, user_id) DO NOTHING`
+
+    result, err := s.db.ExecContext(ctx, query,
+        response.ID, response.StickerID, response.StoryID, response.UserID,
+        response.OptionIndex, response.SliderValue, response.Text,
+        response.IsCorrect, response.CreatedAt,
+    )
+    if err != nil {
+        s.logger.Error("Failed to create sticker response", zap.Error(err))
+        return fmt.Errorf("failed to create sticker response: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return ErrAlreadyExists
+    }
+
+    s.logger.Debug("Sticker response created",
+        zap.String("sticker_id", response.StickerID.String()),
+        zap.String("user_id", response.UserID.String()),
+    )
+
+    return nil
+}
+
+// GetResponses gets responses to a sticker with respondent information
+func (s *StickerStoreImpl) GetResponses(ctx context.Context, stickerID uuid.UUID, limit, offset int) ([]*models.StickerResponseWithUser, error) {
+    query := `
+        SELECT r.id, r.sticker_id, r.story_id, r.user_id, r.option_index,
+               r.slider_value, r.text, r.is_correct, r.created_at,
+               u.username, u.full_name, u.profile_picture, u.is_verified
+        FROM sticker_responses r
+        JOIN users u ON r.user_id = u.id
+        WHERE r.sticker_id = $1 AND u.deleted_at IS NULL
+        ORDER BY r.created_at DESC
+        LIMIT $2 OFFSET $3`
+
+    var responses []*models.StickerResponseWithUser
+    err := s.db.SelectContext(ctx, &responses, query, stickerID, limit, offset)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get sticker responses: %w", err)
+    }
+
+    return responses, nil
+}
+
+// GetResults gets aggregate results for a sticker along with its most recent respondents
+func (s *StickerStoreImpl) GetResults(ctx context.Context, sticker *models.Sticker, limit int) (*models.StickerResults, error) {
+    results := &models.StickerResults{
+        StickerID:     sticker.ID,
+        Type:          sticker.Type,
+        Question:      sticker.Question,
+        Options:       sticker.Options,
+        CorrectOption: sticker.CorrectOption,
+    }
+
+    aggregateQuery := `
+        SELECT 
+            COUNT(*) as total,
+            COUNT(*) FILTER (WHERE is_correct) as correct,
+            AVG(slider_value) as average_slider
+        FROM sticker_responses
+        WHERE sticker_id = $1`
+
+    var aggregate struct {
+        Total         int      `db:"total"`
+        Correct       int      `db:"correct"`
+        AverageSlider *float64 `db:"average_slider"`
+    }
+    if err := s.db.GetContext(ctx, &aggregate, aggregateQuery, sticker.ID); err != nil {
+        return nil, fmt.Errorf("failed to get sticker results: %w", err)
+    }
+
+    results.TotalResponses = aggregate.Total
+
+    switch sticker.Type {
+    case models.StickerTypePoll, models.StickerTypeQuiz:
+        optionQuery := `
+            SELECT option_index, COUNT(*) as count
+            FROM sticker_responses
+            WHERE sticker_id = $1 AND option_index IS NOT NULL
+            GROUP BY option_index`
+
+        var optionCounts []struct {
+            OptionIndex int `db:"option_index"`
+            Count       int `db:"count"`
+        }
+        if err := s.db.SelectContext(ctx, &optionCounts, optionQuery, sticker.ID); err != nil {
+            return nil, fmt.Errorf("failed to get sticker option counts: %w", err)
+        }
+
+        results.OptionCounts = make([]int, len(sticker.Options))
+        for _, oc := range optionCounts {
+            if oc.OptionIndex >= 0 && oc.OptionIndex < len(results.OptionCounts) {
+                results.OptionCounts[oc.OptionIndex] = oc.Count
+            }
+        }
+
+        if sticker.Type == models.StickerTypeQuiz {
+            results.CorrectCount = &aggregate.Correct
+        }
+    case models.StickerTypeSlider:
+        results.AverageSlider = aggregate.AverageSlider
+    }
+
+    respondents, err := s.GetResponses(ctx, sticker.ID, limit, 0)
+    if err != nil {
+        return nil, err
+    }
+    results.Respondents = respondents
+
+    return results, nil
+}
+
+// DeleteByStoryID removes all stickers and responses of a story
+func (s *StickerStoreImpl) DeleteByStoryID(ctx context.Context, storyID uuid.UUID) error {
+    // Responses are removed by the ON DELETE CASCADE on sticker_id
+    _, err := s.db.ExecContext(ctx, "DELETE FROM story_stickers WHERE story_id = $1", storyID)
+    if err != nil {
+        return fmt.Errorf("failed to delete story stickers: %w", err)
+    }
+
+    return nil
+}
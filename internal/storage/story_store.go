@@ -8,6 +8,7 @@ import (
 
     "github.com/google/uuid"
     "github.com/jmoiron/sqlx"
+    "github.com/lib/pq"
     "go.uber.org/zap"
 
     "github.com/Abhiro0p/stories-backend/internal/models"
@@ -34,15 +35,29 @@ func (s *StoryStoreImpl) Create(ctx context.Context, story *models.Story) error
     query := `
         INSERT INTO stories (
             id, author_id, type, text, media_url, media_key, 
-            visibility, view_count, expires_at, created_at, updated_at
+            visibility, view_count, status, expires_in, published_at,
+            expires_at, created_at, updated_at, original_story_id, original_author_id,
+            place_name, latitude, longitude, geohash, hide_coordinates,
+            allow_reactions, replies_disabled
         ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+            $17, $18, $19, $20, $21, $22, $23
         )`
 
-    _, err := s.db.ExecContext(ctx, query,
+    tx, err := s.db.BeginTxx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    _, err = tx.ExecContext(ctx, query,
         story.ID, story.AuthorID, story.Type, story.Text,
         story.MediaURL, story.MediaKey, story.Visibility,
-        story.ViewCount, story.ExpiresAt, story.CreatedAt, story.UpdatedAt,
+        story.ViewCount, story.Status, story.ExpiresIn, story.PublishedAt,
+        story.ExpiresAt, story.CreatedAt, story.UpdatedAt,
+        story.OriginalStoryID, story.OriginalAuthorID,
+        story.PlaceName, story.Latitude, story.Longitude, story.Geohash, story.HideCoordinates,
+        story.AllowReactions, story.RepliesDisabled,
     )
 
     if err != nil {
@@ -50,6 +65,66 @@ func (s *StoryStoreImpl) Create(ctx context.Context, story *models.Story) error
         return fmt.Errorf("failed to create story: %w", err)
     }
 
+    // Count the share against the original story
+    if story.OriginalStoryID != nil {
+        _, err = tx.ExecContext(ctx,
+            "UPDATE stories SET share_count = share_count + 1 WHERE id = $1",
+            story.OriginalStoryID)
+        if err != nil {
+            return fmt.Errorf("failed to update share count: %w", err)
+        }
+    }
+
+    // Insert segments for sequence stories
+    segmentQuery := `
+        INSERT INTO story_segments (
+            id, story_id, position, type, text, media_url, media_key,
+            caption, duration, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+        )`
+
+    for _, segment := range story.Segments {
+        _, err = tx.ExecContext(ctx, segmentQuery,
+            segment.ID, segment.StoryID, segment.Position, segment.Type,
+            segment.Text, segment.MediaURL, segment.MediaKey,
+            segment.Caption, segment.Duration, segment.CreatedAt,
+        )
+        if err != nil {
+            s.logger.Error("Failed to create story segment", zap.Error(err))
+            return fmt.Errorf("failed to create story segment: %w", err)
+        }
+    }
+
+    // Insert interactive stickers
+    stickerQuery := `
+        INSERT INTO story_stickers (
+            id, story_id, type, question, options, correct_option, emoji, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8
+        )`
+
+    for _, sticker := range story.Stickers {
+        _, err = tx.ExecContext(ctx, stickerQuery,
+            sticker.ID, sticker.StoryID, sticker.Type, sticker.Question,
+            sticker.Options, sticker.CorrectOption, sticker.Emoji, sticker.CreatedAt,
+        )
+        if err != nil {
+            s.logger.Error("Failed to create story sticker", zap.Error(err))
+            return fmt.Errorf("failed to create story sticker: %w", err)
+        }
+    }
+
+    // Insert parsed mentions and hashtags
+    if err = insertStoryEntities(ctx, tx, story.Entities); err != nil {
+        s.logger.Error("Failed to create story entities", zap.Error(err))
+        return err
+    }
+
+    if err = tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit transaction: %w", err)
+    }
+
     // Invalidate related caches
     s.invalidateStoryCache(story.AuthorID)
     
@@ -68,8 +143,11 @@ func (s *StoryStoreImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Sto
 
     query := `
         SELECT s.id, s.author_id, s.type, s.text, s.media_url, s.media_key,
-               s.visibility, s.view_count, s.expires_at, s.created_at, 
-               s.updated_at, s.deleted_at,
+               s.visibility, s.view_count, s.unique_view_count, s.reaction_count, s.status, s.expires_in, s.published_at,
+               s.share_count, s.original_story_id, s.original_author_id,
+               s.place_name, s.latitude, s.longitude, s.geohash, s.hide_coordinates,
+               s.allow_reactions, s.replies_disabled,
+               s.expires_at, s.created_at, s.updated_at, s.deleted_at, s.archived_at, s.edited_at,
                u.username as author_username, u.full_name as author_full_name,
                u.profile_picture as author_profile_picture, u.is_verified as author_is_verified
         FROM stories s
@@ -89,6 +167,26 @@ func (s *StoryStoreImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Sto
     story = storyWithAuthor.Story
     story.Author = storyWithAuthor.GetAuthorInfo()
 
+    if story.Type == models.StoryTypeSequence {
+        segments, err := s.GetSegments(ctx, id)
+        if err != nil {
+            return nil, err
+        }
+        story.Segments = segments
+    }
+
+    stickers, err := s.GetStickers(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    story.Stickers = stickers
+
+    entities, err := s.GetEntities(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    story.Entities = entities
+
     // Cache the result
     s.redisClient.Set(ctx, cacheKey, &story, 180) // Cache for 3 minutes
 
@@ -99,20 +197,25 @@ func (s *StoryStoreImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Sto
 func (s *StoryStoreImpl) GetFeed(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Story, error) {
     query := `
         SELECT DISTINCT s.id, s.author_id, s.type, s.text, s.media_url, s.media_key,
-               s.visibility, s.view_count, s.expires_at, s.created_at, s.updated_at,
+               s.visibility, s.view_count, s.unique_view_count, s.reaction_count, s.status, s.expires_in, s.published_at,
+               s.share_count, s.original_story_id, s.original_author_id,
+               s.place_name, s.latitude, s.longitude, s.geohash, s.hide_coordinates,
+               s.allow_reactions, s.replies_disabled,
+               s.expires_at, s.created_at, s.updated_at, s.edited_at,
                u.username as author_username, u.full_name as author_full_name,
                u.profile_picture as author_profile_picture, u.is_verified as author_is_verified
         FROM stories s
         JOIN users u ON s.author_id = u.id
         LEFT JOIN follows f ON s.author_id = f.followee_id
         WHERE s.deleted_at IS NULL 
+        AND s.status = 'published'
         AND s.expires_at > NOW()
         AND (
             s.visibility = 'public' OR 
             (s.visibility = 'friends' AND f.follower_id = $1) OR
             s.author_id = $1
         )
-        ORDER BY s.created_at DESC
+        ORDER BY s.published_at DESC
         LIMIT $2 OFFSET $3`
 
     var storiesWithAuthor []models.StoryWithAuthor
@@ -128,6 +231,10 @@ func (s *StoryStoreImpl) GetFeed(ctx context.Context, userID uuid.UUID, limit, o
         stories[i] = &story
     }
 
+    if err := s.loadSegments(ctx, stories); err != nil {
+        return nil, err
+    }
+
     return stories, nil
 }
 
@@ -135,15 +242,20 @@ func (s *StoryStoreImpl) GetFeed(ctx context.Context, userID uuid.UUID, limit, o
 func (s *StoryStoreImpl) GetPublic(ctx context.Context, limit, offset int) ([]*models.Story, error) {
     query := `
         SELECT DISTINCT s.id, s.author_id, s.type, s.text, s.media_url, s.media_key,
-               s.visibility, s.view_count, s.expires_at, s.created_at, s.updated_at,
+               s.visibility, s.view_count, s.unique_view_count, s.reaction_count, s.status, s.expires_in, s.published_at,
+               s.share_count, s.original_story_id, s.original_author_id,
+               s.place_name, s.latitude, s.longitude, s.geohash, s.hide_coordinates,
+               s.allow_reactions, s.replies_disabled,
+               s.expires_at, s.created_at, s.updated_at, s.edited_at,
                u.username as author_username, u.full_name as author_full_name,
                u.profile_picture as author_profile_picture, u.is_verified as author_is_verified
         FROM stories s
         JOIN users u ON s.author_id = u.id
         WHERE s.deleted_at IS NULL 
+        AND s.status = 'published'
         AND s.expires_at > NOW()
         AND s.visibility = 'public'
-        ORDER BY s.created_at DESC
+        ORDER BY s.published_at DESC
         LIMIT $1 OFFSET $2`
 
     var storiesWithAuthor []models.StoryWithAuthor
@@ -159,6 +271,10 @@ func (s *StoryStoreImpl) GetPublic(ctx context.Context, limit, offset int) ([]*m
         stories[i] = &story
     }
 
+    if err := s.loadSegments(ctx, stories); err != nil {
+        return nil, err
+    }
+
     return stories, nil
 }
 
@@ -166,10 +282,14 @@ func (s *StoryStoreImpl) GetPublic(ctx context.Context, limit, offset int) ([]*m
 func (s *StoryStoreImpl) GetByAuthorID(ctx context.Context, authorID uuid.UUID, limit, offset int) ([]*models.Story, error) {
     query := `
         SELECT id, author_id, type, text, media_url, media_key,
-               visibility, view_count, expires_at, created_at, updated_at, deleted_at
+               visibility, view_count, unique_view_count, reaction_count, status, expires_in, published_at,
+               share_count, original_story_id, original_author_id,
+               place_name, latitude, longitude, geohash, hide_coordinates,
+               allow_reactions, replies_disabled,
+               expires_at, created_at, updated_at, deleted_at, edited_at
         FROM stories 
-        WHERE author_id = $1 AND deleted_at IS NULL
-        ORDER BY created_at DESC
+        WHERE author_id = $1 AND deleted_at IS NULL AND status = 'published'
+        ORDER BY published_at DESC
         LIMIT $2 OFFSET $3`
 
     var stories []*models.Story
@@ -178,19 +298,50 @@ func (s *StoryStoreImpl) GetByAuthorID(ctx context.Context, authorID uuid.UUID,
         return nil, fmt.Errorf("failed to get stories by author: %w", err)
     }
 
+    if err := s.loadSegments(ctx, stories); err != nil {
+        return nil, err
+    }
+
     return stories, nil
 }
 
-// Update updates a story
-func (s *StoryStoreImpl) Update(ctx context.Context, story *models.Story) error {
+// CountByAuthorID counts an author's published stories, which is what a user's
+// story_count holds. Drafts and archived stories aren't counted.
+func (s *StoryStoreImpl) CountByAuthorID(ctx context.Context, authorID uuid.UUID) (int, error) {
+    var count int
+    query := `
+        SELECT COUNT(*) FROM stories
+        WHERE author_id = $1 AND deleted_at IS NULL AND status = 'published'`
+
+    err := s.db.GetContext(ctx, &count, query, authorID)
+    if err != nil {
+        return 0, fmt.Errorf("failed to count stories by author: %w", err)
+    }
+
+    return count, nil
+}
+
+// Update updates a story. If the update changed its content, the revision is
+// recorded in the same transaction.
+func (s *StoryStoreImpl) Update(ctx context.Context, story *models.Story, revision *models.StoryRevision) error {
     story.UpdatedAt = time.Now()
 
+    tx, err := s.db.BeginTxx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
     query := `
         UPDATE stories SET 
-            text = $2, visibility = $3, updated_at = $4
+            text = $2, visibility = $3, allow_reactions = $4, replies_disabled = $5, updated_at = $6,
+            edited_at = $7
         WHERE id = $1 AND deleted_at IS NULL`
 
-    result, err := s.db.ExecContext(ctx, query, story.ID, story.Text, story.Visibility, story.UpdatedAt)
+    result, err := tx.ExecContext(ctx, query,
+        story.ID, story.Text, story.Visibility, story.AllowReactions, story.RepliesDisabled, story.UpdatedAt,
+        story.EditedAt,
+    )
     if err != nil {
         return fmt.Errorf("failed to update story: %w", err)
     }
@@ -204,6 +355,33 @@ func (s *StoryStoreImpl) Update(ctx context.Context, story *models.Story) error
         return ErrNotFound
     }
 
+    // The story row is locked by the update, so revision numbers can't race
+    if revision != nil {
+        revisionQuery := `
+            INSERT INTO story_revisions (
+                id, story_id, revision, editor_id, text_before, text_after,
+                visibility_before, visibility_after, created_at
+            ) VALUES (
+                $1, $2,
+                (SELECT COALESCE(MAX(revision), 0) + 1 FROM story_revisions WHERE story_id = $2),
+                $3, $4, $5, $6, $7, $8
+            )
+            RETURNING revision`
+
+        err = tx.GetContext(ctx, &revision.Revision, revisionQuery,
+            revision.ID, revision.StoryID, revision.EditorID,
+            revision.TextBefore, revision.TextAfter,
+            revision.VisibilityBefore, revision.VisibilityAfter, revision.CreatedAt,
+        )
+        if err != nil {
+            return fmt.Errorf("failed to create story revision: %w", err)
+        }
+    }
+
+    if err = tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit transaction: %w", err)
+    }
+
     // Invalidate caches
     s.invalidateStoryCache(story.AuthorID)
     cacheKey := fmt.Sprintf("story:%s", story.ID.String())
@@ -212,6 +390,26 @@ func (s *StoryStoreImpl) Update(ctx context.Context, story *models.Story) error
     return nil
 }
 
+// GetRevisions gets a story's edit history, oldest first. Revisions are kept
+// even after the story itself is gone.
+func (s *StoryStoreImpl) GetRevisions(ctx context.Context, storyID uuid.UUID) ([]*models.StoryRevision, error) {
+    query := `
+        SELECT r.id, r.story_id, r.revision, r.editor_id, u.username as editor_username,
+               r.text_before, r.text_after, r.visibility_before, r.visibility_after, r.created_at
+        FROM story_revisions r
+        JOIN users u ON r.editor_id = u.id
+        WHERE r.story_id = $1
+        ORDER BY r.revision ASC`
+
+    var revisions []*models.StoryRevision
+    err := s.db.SelectContext(ctx, &revisions, query, storyID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get story revisions: %w", err)
+    }
+
+    return revisions, nil
+}
+
 // Delete soft deletes a story
 func (s *StoryStoreImpl) Delete(ctx context.Context, id uuid.UUID) error {
     now := time.Now()
@@ -245,9 +443,13 @@ func (s *StoryStoreImpl) Delete(ctx context.Context, id uuid.UUID) error {
 func (s *StoryStoreImpl) GetExpired(ctx context.Context, limit, offset int) ([]*models.Story, error) {
     query := `
         SELECT id, author_id, type, text, media_url, media_key,
-               visibility, view_count, expires_at, created_at, updated_at, deleted_at
+               visibility, view_count, unique_view_count, reaction_count, status, expires_in, published_at,
+               share_count, original_story_id, original_author_id,
+               place_name, latitude, longitude, geohash, hide_coordinates,
+               allow_reactions, replies_disabled,
+               expires_at, created_at, updated_at, deleted_at, edited_at
         FROM stories 
-        WHERE expires_at <= NOW() AND deleted_at IS NULL
+        WHERE expires_at <= NOW() AND deleted_at IS NULL AND status = 'published'
         ORDER BY expires_at ASC
         LIMIT $1 OFFSET $2`
 
@@ -260,26 +462,538 @@ func (s *StoryStoreImpl) GetExpired(ctx context.Context, limit, offset int) ([]*
     return stories, nil
 }
 
-// IncrementViewCount increments view count for a story
-func (s *StoryStoreImpl) IncrementViewCount(ctx context.Context, storyID uuid.UUID) error {
+// GetDrafts gets unpublished drafts for an author
+func (s *StoryStoreImpl) GetDrafts(ctx context.Context, authorID uuid.UUID, limit, offset int) ([]*models.Story, error) {
+    query := `
+        SELECT id, author_id, type, text, media_url, media_key,
+               visibility, view_count, unique_view_count, reaction_count, status, expires_in, published_at,
+               share_count, original_story_id, original_author_id,
+               place_name, latitude, longitude, geohash, hide_coordinates,
+               allow_reactions, replies_disabled,
+               expires_at, created_at, updated_at, deleted_at, edited_at
+        FROM stories 
+        WHERE author_id = $1 AND deleted_at IS NULL AND status = 'draft'
+        ORDER BY updated_at DESC
+        LIMIT $2 OFFSET $3`
+
+    var stories []*models.Story
+    err := s.db.SelectContext(ctx, &stories, query, authorID, limit, offset)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get drafts: %w", err)
+    }
+
+    if err := s.loadSegments(ctx, stories); err != nil {
+        return nil, err
+    }
+
+    return stories, nil
+}
+
+// UpdateDraft updates the editable fields of a draft
+func (s *StoryStoreImpl) UpdateDraft(ctx context.Context, story *models.Story) error {
+    story.UpdatedAt = time.Now()
+
     query := `
         UPDATE stories SET 
-            view_count = view_count + 1,
-            updated_at = NOW()
-        WHERE id = $1 AND deleted_at IS NULL`
+            text = $2, media_key = $3, visibility = $4, expires_in = $5, updated_at = $6
+        WHERE id = $1 AND deleted_at IS NULL AND status = 'draft'`
+
+    result, err := s.db.ExecContext(ctx, query,
+        story.ID, story.Text, story.MediaKey, story.Visibility, story.ExpiresIn, story.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to update draft: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return ErrNotFound
+    }
+
+    cacheKey := fmt.Sprintf("story:%s", story.ID.String())
+    s.redisClient.Delete(ctx, cacheKey)
+
+    return nil
+}
+
+// Publish publishes a draft, starting its expiry clock
+func (s *StoryStoreImpl) Publish(ctx context.Context, story *models.Story) error {
+    query := `
+        UPDATE stories SET 
+            status = $2, published_at = $3, expires_at = $4, expires_in = $5, updated_at = $6
+        WHERE id = $1 AND deleted_at IS NULL AND status = 'draft'`
+
+    result, err := s.db.ExecContext(ctx, query,
+        story.ID, story.Status, story.PublishedAt, story.ExpiresAt, story.ExpiresIn, story.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to publish draft: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return ErrNotFound
+    }
+
+    // Invalidate caches
+    s.invalidateStoryCache(story.AuthorID)
+    cacheKey := fmt.Sprintf("story:%s", story.ID.String())
+    s.redisClient.Delete(ctx, cacheKey)
 
-    _, err := s.db.ExecContext(ctx, query, storyID)
+    s.logger.Info("Draft published", zap.String("story_id", story.ID.String()))
+    return nil
+}
+
+// DeleteStaleDrafts permanently removes drafts that haven't been touched since olderThan
+func (s *StoryStoreImpl) DeleteStaleDrafts(ctx context.Context, olderThan time.Time) (int64, error) {
+    query := `DELETE FROM stories WHERE status = 'draft' AND updated_at < $1`
+
+    result, err := s.db.ExecContext(ctx, query, olderThan)
     if err != nil {
-        return fmt.Errorf("failed to increment view count: %w", err)
+        return 0, fmt.Errorf("failed to delete stale drafts: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return 0, fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    return rowsAffected, nil
+}
+
+// Archive moves an expired story into its author's archive
+func (s *StoryStoreImpl) Archive(ctx context.Context, story *models.Story) error {
+    query := `
+        UPDATE stories SET 
+            status = $2, archived_at = $3, updated_at = $4
+        WHERE id = $1 AND deleted_at IS NULL AND status = 'published'`
+
+    result, err := s.db.ExecContext(ctx, query, story.ID, story.Status, story.ArchivedAt, story.UpdatedAt)
+    if err != nil {
+        return fmt.Errorf("failed to archive story: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return ErrNotFound
+    }
+
+    // Invalidate caches
+    s.invalidateStoryCache(story.AuthorID)
+    cacheKey := fmt.Sprintf("story:%s", story.ID.String())
+    s.redisClient.Delete(ctx, cacheKey)
+
+    return nil
+}
+
+// HardDelete permanently removes a story along with its segments, stickers and
+// entities. Reshares of it are soft deleted in the same transaction, since the
+// foreign key would otherwise leave them without an original or attribution.
+func (s *StoryStoreImpl) HardDelete(ctx context.Context, id uuid.UUID) error {
+    tx, err := s.db.BeginTxx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    // Reshares always point at the root story, so one level covers them all
+    var reshareIDs []uuid.UUID
+    err = tx.SelectContext(ctx, &reshareIDs, `
+        UPDATE stories SET 
+            deleted_at = NOW(), updated_at = NOW()
+        WHERE original_story_id = $1 AND deleted_at IS NULL
+        RETURNING id`, id)
+    if err != nil {
+        return fmt.Errorf("failed to delete reshares: %w", err)
+    }
+
+    result, err := tx.ExecContext(ctx, `DELETE FROM stories WHERE id = $1`, id)
+    if err != nil {
+        return fmt.Errorf("failed to hard delete story: %w", err)
+    }
+
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get rows affected: %w", err)
+    }
+
+    if rowsAffected == 0 {
+        return ErrNotFound
+    }
+
+    if err = tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit transaction: %w", err)
     }
 
     // Invalidate cache
+    cacheKeys := []string{fmt.Sprintf("story:%s", id.String())}
+    for _, reshareID := range reshareIDs {
+        cacheKeys = append(cacheKeys, fmt.Sprintf("story:%s", reshareID.String()))
+    }
+    s.redisClient.DeleteMany(ctx, cacheKeys)
+
+    return nil
+}
+
+// GetArchived gets an author's archived stories, most recently archived first
+func (s *StoryStoreImpl) GetArchived(ctx context.Context, authorID uuid.UUID, limit, offset int) ([]*models.Story, error) {
+    query := `
+        SELECT id, author_id, type, text, media_url, media_key,
+               visibility, view_count, unique_view_count, reaction_count, status, expires_in, published_at,
+               share_count, original_story_id, original_author_id,
+               place_name, latitude, longitude, geohash, hide_coordinates,
+               allow_reactions, replies_disabled,
+               expires_at, created_at, updated_at, deleted_at, archived_at, edited_at
+        FROM stories 
+        WHERE author_id = $1 AND deleted_at IS NULL AND status = 'archived'
+        ORDER BY archived_at DESC
+        LIMIT $2 OFFSET $3`
+
+    var stories []*models.Story
+    err := s.db.SelectContext(ctx, &stories, query, authorID, limit, offset)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get archived stories: %w", err)
+    }
+
+    if err := s.loadSegments(ctx, stories); err != nil {
+        return nil, err
+    }
+
+    return stories, nil
+}
+
+// GetSegments gets the ordered segments of a sequence story
+func (s *StoryStoreImpl) GetSegments(ctx context.Context, storyID uuid.UUID) ([]*models.StorySegment, error) {
+    query := `
+        SELECT id, story_id, position, type, text, media_url, media_key,
+               caption, duration, created_at
+        FROM story_segments
+        WHERE story_id = $1
+        ORDER BY position ASC`
+
+    var segments []*models.StorySegment
+    err := s.db.SelectContext(ctx, &segments, query, storyID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get story segments: %w", err)
+    }
+
+    return segments, nil
+}
+
+// loadSegments fills in the segments of the sequence stories in a list with
+// one query
+func (s *StoryStoreImpl) loadSegments(ctx context.Context, stories []*models.Story) error {
+    var ids []uuid.UUID
+    byID := make(map[uuid.UUID]*models.Story)
+    for _, story := range stories {
+        if story.Type == models.StoryTypeSequence {
+            ids = append(ids, story.ID)
+            byID[story.ID] = story
+        }
+    }
+    if len(ids) == 0 {
+        return nil
+    }
+
+    query := `
+        SELECT id, story_id, position, type, text, media_url, media_key,
+               caption, duration, created_at
+        FROM story_segments
+        WHERE story_id = ANY($1)
+        ORDER BY story_id, position ASC`
+
+    var segments []*models.StorySegment
+    err := s.db.SelectContext(ctx, &segments, query, pq.Array(ids))
+    if err != nil {
+        return fmt.Errorf("failed to get story segments: %w", err)
+    }
+
+    for _, segment := range segments {
+        story := byID[segment.StoryID]
+        story.Segments = append(story.Segments, segment)
+    }
+
+    return nil
+}
+
+// GetStickers gets the interactive stickers attached to a story
+func (s *StoryStoreImpl) GetStickers(ctx context.Context, storyID uuid.UUID) ([]*models.Sticker, error) {
+    query := `
+        SELECT id, story_id, type, question, options, correct_option, emoji, created_at
+        FROM story_stickers
+        WHERE story_id = $1
+        ORDER BY created_at ASC`
+
+    var stickers []*models.Sticker
+    err := s.db.SelectContext(ctx, &stickers, query, storyID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get story stickers: %w", err)
+    }
+
+    return stickers, nil
+}
+
+// GetEntities gets the mentions and hashtags parsed from a story's text
+func (s *StoryStoreImpl) GetEntities(ctx context.Context, storyID uuid.UUID) ([]*models.StoryEntity, error) {
+    query := `
+        SELECT id, story_id, type, value, user_id, "offset", length, created_at
+        FROM story_entities
+        WHERE story_id = $1
+        ORDER BY "offset" ASC`
+
+    var entities []*models.StoryEntity
+    err := s.db.SelectContext(ctx, &entities, query, storyID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get story entities: %w", err)
+    }
+
+    return entities, nil
+}
+
+// SetEntities replaces the mentions and hashtags of a story
+func (s *StoryStoreImpl) SetEntities(ctx context.Context, storyID uuid.UUID, entities []*models.StoryEntity) error {
+    tx, err := s.db.BeginTxx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    _, err = tx.ExecContext(ctx, "DELETE FROM story_entities WHERE story_id = $1", storyID)
+    if err != nil {
+        return fmt.Errorf("failed to delete story entities: %w", err)
+    }
+
+    if err = insertStoryEntities(ctx, tx, entities); err != nil {
+        return err
+    }
+
+    if err = tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit transaction: %w", err)
+    }
+
     cacheKey := fmt.Sprintf("story:%s", storyID.String())
     s.redisClient.Delete(ctx, cacheKey)
 
     return nil
 }
 
+// GetByHashtag gets active public stories tagged with a hashtag
+func (s *StoryStoreImpl) GetByHashtag(ctx context.Context, tag string, limit, offset int) ([]*models.Story, error) {
+    query := `
+        SELECT DISTINCT s.id, s.author_id, s.type, s.text, s.media_url, s.media_key,
+               s.visibility, s.view_count, s.unique_view_count, s.reaction_count, s.status, s.expires_in, s.published_at,
+               s.share_count, s.original_story_id, s.original_author_id,
+               s.place_name, s.latitude, s.longitude, s.geohash, s.hide_coordinates,
+               s.allow_reactions, s.replies_disabled,
+               s.expires_at, s.created_at, s.updated_at, s.edited_at,
+               u.username as author_username, u.full_name as author_full_name,
+               u.profile_picture as author_profile_picture, u.is_verified as author_is_verified
+        FROM stories s
+        JOIN users u ON s.author_id = u.id
+        JOIN story_entities e ON e.story_id = s.id
+        WHERE e.type = 'hashtag' AND e.value = $1
+        AND s.deleted_at IS NULL 
+        AND s.status = 'published'
+        AND s.expires_at > NOW()
+        AND s.visibility = 'public'
+        ORDER BY s.published_at DESC
+        LIMIT $2 OFFSET $3`
+
+    var storiesWithAuthor []models.StoryWithAuthor
+    err := s.db.SelectContext(ctx, &storiesWithAuthor, query, tag, limit, offset)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get hashtag stories: %w", err)
+    }
+
+    stories := make([]*models.Story, len(storiesWithAuthor))
+    for i, storyWithAuthor := range storiesWithAuthor {
+        story := storyWithAuthor.Story
+        story.Author = storyWithAuthor.GetAuthorInfo()
+        stories[i] = &story
+    }
+
+    if err := s.loadSegments(ctx, stories); err != nil {
+        return nil, err
+    }
+
+    return stories, nil
+}
+
+// insertStoryEntities inserts story entities inside a transaction
+func insertStoryEntities(ctx context.Context, tx *sqlx.Tx, entities []*models.StoryEntity) error {
+    query := `
+        INSERT INTO story_entities (
+            id, story_id, type, value, user_id, "offset", length, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8
+        )`
+
+    for _, entity := range entities {
+        _, err := tx.ExecContext(ctx, query,
+            entity.ID, entity.StoryID, entity.Type, entity.Value,
+            entity.UserID, entity.Offset, entity.Length, entity.CreatedAt,
+        )
+        if err != nil {
+            return fmt.Errorf("failed to create story entity: %w", err)
+        }
+    }
+
+    return nil
+}
+
+// GetNearby gets active public stories within a radius of a point, nearest first.
+// Stories with hidden coordinates are left out, since repeated searches with
+// different centers and radii would reveal where they were posted.
+func (s *StoryStoreImpl) GetNearby(ctx context.Context, query models.NearbyStoriesQuery, limit, offset int) ([]*models.Story, error) {
+    // Candidates come from the geohash cells covering the radius; the exact
+    // distance filter runs on those rows only
+    sqlQuery := `
+        SELECT * FROM (
+            SELECT s.id, s.author_id, s.type, s.text, s.media_url, s.media_key,
+                   s.visibility, s.view_count, s.unique_view_count, s.reaction_count, s.status, s.expires_in, s.published_at,
+                   s.share_count, s.original_story_id, s.original_author_id,
+                   s.place_name, s.latitude, s.longitude, s.geohash, s.hide_coordinates,
+                   s.allow_reactions, s.replies_disabled,
+                   s.expires_at, s.created_at, s.updated_at, s.edited_at,
+                   u.username as author_username, u.full_name as author_full_name,
+                   u.profile_picture as author_profile_picture, u.is_verified as author_is_verified,
+                   6371000 * 2 * ASIN(SQRT(
+                       POWER(SIN(RADIANS(s.latitude - $1) / 2), 2) +
+                       COS(RADIANS($1)) * COS(RADIANS(s.latitude)) *
+                       POWER(SIN(RADIANS(s.longitude - $2) / 2), 2)
+                   )) as distance
+            FROM stories s
+            JOIN users u ON s.author_id = u.id
+            WHERE s.geohash LIKE ANY($3)
+            AND s.hide_coordinates = FALSE
+            AND s.deleted_at IS NULL 
+            AND s.status = 'published'
+            AND s.expires_at > NOW()
+            AND s.visibility = 'public'
+        ) nearby
+        WHERE distance <= $4
+        ORDER BY distance ASC, published_at DESC
+        LIMIT $5 OFFSET $6`
+
+    type nearbyStory struct {
+        models.StoryWithAuthor
+        Distance float64 `db:"distance"`
+    }
+
+    patterns := make([]string, len(query.Cells))
+    for i, cell := range query.Cells {
+        patterns[i] = cell + "%"
+    }
+
+    var results []nearbyStory
+    err := s.db.SelectContext(ctx, &results, sqlQuery,
+        query.Latitude, query.Longitude, pq.Array(patterns),
+        query.Radius, limit, offset,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to get nearby stories: %w", err)
+    }
+
+    stories := make([]*models.Story, len(results))
+    for i, result := range results {
+        story := result.Story
+        story.Author = result.GetAuthorInfo()
+        distance := result.Distance
+        story.Distance = &distance
+        stories[i] = &story
+    }
+
+    if err := s.loadSegments(ctx, stories); err != nil {
+        return nil, err
+    }
+
+    return stories, nil
+}
+
+// GetMetrics gets engagement metrics for a story
+func (s *StoryStoreImpl) GetMetrics(ctx context.Context, storyID uuid.UUID) (*StoryMetrics, error) {
+    query := `
+        SELECT 
+            s.view_count as views,
+            s.unique_view_count as unique_views,
+            (SELECT COUNT(*) FROM reactions WHERE story_id = s.id) as reactions,
+            s.share_count as shares
+        FROM stories s
+        WHERE s.id = $1 AND s.deleted_at IS NULL`
+
+    var counts struct {
+        Views       int `db:"views"`
+        UniqueViews int `db:"unique_views"`
+        Reactions   int `db:"reactions"`
+        Shares      int `db:"shares"`
+    }
+    err := s.db.GetContext(ctx, &counts, query, storyID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, ErrNotFound
+        }
+        return nil, fmt.Errorf("failed to get story metrics: %w", err)
+    }
+
+    return &StoryMetrics{
+        StoryID:     storyID,
+        Views:       counts.Views,
+        UniqueViews: counts.UniqueViews,
+        Reactions:   counts.Reactions,
+        Shares:      counts.Shares,
+    }, nil
+}
+
+// ApplyViewCounts adds batches of views to the stories' counters in a single update
+func (s *StoryStoreImpl) ApplyViewCounts(ctx context.Context, deltas []*models.ViewCountDelta) error {
+    if len(deltas) == 0 {
+        return nil
+    }
+
+    storyIDs := make([]string, len(deltas))
+    totals := make([]int64, len(deltas))
+    uniques := make([]int64, len(deltas))
+    for i, delta := range deltas {
+        storyIDs[i] = delta.StoryID.String()
+        totals[i] = delta.TotalViews
+        uniques[i] = delta.UniqueViews
+    }
+
+    // Counters aren't part of the story's content, so updated_at is left alone
+    query := `
+        UPDATE stories s SET 
+            view_count = s.view_count + v.total,
+            unique_view_count = s.unique_view_count + v.uniq
+        FROM unnest($1::uuid[], $2::bigint[], $3::bigint[]) AS v(id, total, uniq)
+        WHERE s.id = v.id`
+
+    _, err := s.db.ExecContext(ctx, query, pq.Array(storyIDs), pq.Array(totals), pq.Array(uniques))
+    if err != nil {
+        return fmt.Errorf("failed to apply view counts: %w", err)
+    }
+
+    // Invalidate cache
+    keys := make([]string, len(deltas))
+    for i, delta := range deltas {
+        keys[i] = fmt.Sprintf("story:%s", delta.StoryID.String())
+    }
+    s.redisClient.DeleteMany(ctx, keys)
+
+    return nil
+}
+
 // GetViewCount gets view count for a story
 func (s *StoryStoreImpl) GetViewCount(ctx context.Context, storyID uuid.UUID) (int, error) {
     var count int
@@ -296,6 +1010,12 @@ func (s *StoryStoreImpl) GetViewCount(ctx context.Context, storyID uuid.UUID) (i
     return count, nil
 }
 
+// InvalidateCache drops the cached copy of a story
+func (s *StoryStoreImpl) InvalidateCache(ctx context.Context, storyID uuid.UUID) {
+    cacheKey := fmt.Sprintf("story:%s", storyID.String())
+    s.redisClient.Delete(ctx, cacheKey)
+}
+
 // Helper function to invalidate story-related caches
 func (s *StoryStoreImpl) invalidateStoryCache(authorID uuid.UUID) {
     // This would invalidate feed caches, author story caches, etc.
@@ -8,6 +8,7 @@ import (
 
     "github.com/google/uuid"
     "github.com/jmoiron/sqlx"
+    "github.com/lib/pq"
     "go.uber.org/zap"
 
     "github.com/Abhiro0p/stories-backend/internal/models"
@@ -66,8 +67,9 @@ func (s *UserStoreImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.User
 
     query := `
         SELECT id, email, username, password_hash, full_name, bio,
-               profile_picture, is_active, is_verified, is_admin,
-               follower_count, following_count, story_count,
+               profile_picture, is_active, is_verified, is_admin, is_moderator,
+               follower_count, following_count, story_count, archive_disabled,
+               default_allow_reactions, default_replies_disabled, anonymous_public_views, hide_activity_status,
                created_at, updated_at, deleted_at
         FROM users 
         WHERE id = $1 AND deleted_at IS NULL`
@@ -91,8 +93,9 @@ func (s *UserStoreImpl) GetByEmail(ctx context.Context, email string) (*models.U
     var user models.User
     query := `
         SELECT id, email, username, password_hash, full_name, bio,
-               profile_picture, is_active, is_verified, is_admin,
-               follower_count, following_count, story_count,
+               profile_picture, is_active, is_verified, is_admin, is_moderator,
+               follower_count, following_count, story_count, archive_disabled,
+               default_allow_reactions, default_replies_disabled, anonymous_public_views, hide_activity_status,
                created_at, updated_at, deleted_at
         FROM users 
         WHERE email = $1 AND deleted_at IS NULL`
@@ -108,16 +111,17 @@ func (s *UserStoreImpl) GetByEmail(ctx context.Context, email string) (*models.U
     return &user, nil
 }
 
-// GetByUsername gets a user by username
+// GetByUsername gets a user by username, ignoring case
 func (s *UserStoreImpl) GetByUsername(ctx context.Context, username string) (*models.User, error) {
     var user models.User
     query := `
         SELECT id, email, username, password_hash, full_name, bio,
-               profile_picture, is_active, is_verified, is_admin,
-               follower_count, following_count, story_count,
+               profile_picture, is_active, is_verified, is_admin, is_moderator,
+               follower_count, following_count, story_count, archive_disabled,
+               default_allow_reactions, default_replies_disabled, anonymous_public_views, hide_activity_status,
                created_at, updated_at, deleted_at
         FROM users 
-        WHERE username = $1 AND deleted_at IS NULL`
+        WHERE LOWER(username) = LOWER($1) AND deleted_at IS NULL`
 
     err := s.db.GetContext(ctx, &user, query, username)
     if err != nil {
@@ -130,6 +134,30 @@ func (s *UserStoreImpl) GetByUsername(ctx context.Context, username string) (*mo
     return &user, nil
 }
 
+// GetByIDs gets the users with the given IDs, skipping any that don't exist
+func (s *UserStoreImpl) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.User, error) {
+    if len(ids) == 0 {
+        return []*models.User{}, nil
+    }
+
+    query := `
+        SELECT id, email, username, password_hash, full_name, bio,
+               profile_picture, is_active, is_verified, is_admin, is_moderator,
+               follower_count, following_count, story_count, archive_disabled,
+               default_allow_reactions, default_replies_disabled, anonymous_public_views, hide_activity_status,
+               created_at, updated_at, deleted_at
+        FROM users 
+        WHERE id = ANY($1) AND deleted_at IS NULL`
+
+    var users []*models.User
+    err := s.db.SelectContext(ctx, &users, query, pq.Array(ids))
+    if err != nil {
+        return nil, fmt.Errorf("failed to get users by IDs: %w", err)
+    }
+
+    return users, nil
+}
+
 // Update updates a user
 func (s *UserStoreImpl) Update(ctx context.Context, user *models.User) error {
     user.UpdatedAt = time.Now()
@@ -138,13 +166,15 @@ func (s *UserStoreImpl) Update(ctx context.Context, user *models.User) error {
         UPDATE users SET 
             email = $2, username = $3, password_hash = $4, full_name = $5,
             bio = $6, profile_picture = $7, is_active = $8, is_verified = $9,
-            updated_at = $10
+            archive_disabled = $10, default_allow_reactions = $11, default_replies_disabled = $12,
+            anonymous_public_views = $13, hide_activity_status = $14, updated_at = $15
         WHERE id = $1 AND deleted_at IS NULL`
 
     result, err := s.db.ExecContext(ctx, query,
         user.ID, user.Email, user.Username, user.PasswordHash,
         user.FullName, user.Bio, user.ProfilePicture, user.IsActive,
-        user.IsVerified, user.UpdatedAt,
+        user.IsVerified, user.ArchiveDisabled, user.DefaultAllowReactions, user.DefaultRepliesDisabled,
+        user.AnonymousPublicViews, user.HideActivityStatus, user.UpdatedAt,
     )
 
     if err != nil {
@@ -200,8 +230,9 @@ func (s *UserStoreImpl) Delete(ctx context.Context, id uuid.UUID) error {
 func (s *UserStoreImpl) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
     query := `
         SELECT id, email, username, password_hash, full_name, bio,
-               profile_picture, is_active, is_verified, is_admin,
-               follower_count, following_count, story_count,
+               profile_picture, is_active, is_verified, is_admin, is_moderator,
+               follower_count, following_count, story_count, archive_disabled,
+               default_allow_reactions, default_replies_disabled, anonymous_public_views, hide_activity_status,
                created_at, updated_at, deleted_at
         FROM users 
         WHERE deleted_at IS NULL
@@ -221,8 +252,9 @@ func (s *UserStoreImpl) List(ctx context.Context, limit, offset int) ([]*models.
 func (s *UserStoreImpl) Search(ctx context.Context, query string, limit, offset int) ([]*models.User, error) {
     searchQuery := `
         SELECT id, email, username, password_hash, full_name, bio,
-               profile_picture, is_active, is_verified, is_admin,
-               follower_count, following_count, story_count,
+               profile_picture, is_active, is_verified, is_admin, is_moderator,
+               follower_count, following_count, story_count, archive_disabled,
+               default_allow_reactions, default_replies_disabled, anonymous_public_views, hide_activity_status,
                created_at, updated_at, deleted_at
         FROM users 
         WHERE deleted_at IS NULL 
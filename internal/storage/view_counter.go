@@ -0,0 +1,174 @@
+package storage
+
+import (
+    "context"
+    "fmt"
+    "strconv"
+    "time"
+
+    "github.com/google/uuid"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+)
+
+const (
+    // viewDirtyKey holds the IDs of stories with counts waiting to be flushed
+    viewDirtyKey = "story_views:dirty"
+
+    // viewerSetGrace keeps a story's viewer set around a little after it expires
+    viewerSetGrace = time.Hour
+)
+
+// recordViewScript adds the viewer to the story's viewer set and bumps the
+// pending counters in one round trip. Returns 1 for a first view.
+const recordViewScript = `
+    local added = redis.call('sadd', KEYS[1], ARGV[1])
+    redis.call('expire', KEYS[1], tonumber(ARGV[2]))
+    redis.call('hincrby', KEYS[2], 'total', 1)
+    if added == 1 then
+        redis.call('hincrby', KEYS[2], 'unique', 1)
+    end
+    redis.call('sadd', KEYS[3], ARGV[3])
+    return added
+`
+
+// drainViewsScript reads and clears a story's pending counters atomically
+const drainViewsScript = `
+    local counts = redis.call('hmget', KEYS[1], 'total', 'unique')
+    redis.call('del', KEYS[1])
+    return counts
+`
+
+// ViewCounterImpl implements ViewCounter interface using Redis
+type ViewCounterImpl struct {
+    redisClient *RedisClient
+    logger      *zap.Logger
+}
+
+// NewViewCounter creates a new view counter
+func NewViewCounter(redisClient *RedisClient, logger *zap.Logger) ViewCounter {
+    return &ViewCounterImpl{
+        redisClient: redisClient,
+        logger:      logger.With(zap.String("store", "view_counter")),
+    }
+}
+
+// Record counts a view of a story, reporting whether it was the viewer's first
+func (v *ViewCounterImpl) Record(ctx context.Context, story *models.Story, viewerID uuid.UUID) (bool, error) {
+    // The viewer set has to outlive the story so repeat views stay deduplicated
+    ttl := time.Until(story.ExpiresAt) + viewerSetGrace
+    if ttl < viewerSetGrace {
+        ttl = viewerSetGrace
+    }
+
+    keys := []string{viewerSetKey(story.ID), pendingViewsKey(story.ID), viewDirtyKey}
+    added, err := v.redisClient.Eval(ctx, recordViewScript, keys,
+        viewerID.String(), int(ttl.Seconds()), story.ID.String(),
+    ).Int64()
+    if err != nil {
+        return false, fmt.Errorf("failed to record view: %w", err)
+    }
+
+    return added == 1, nil
+}
+
+// GetPending gets the counts recorded for a story since the last flush
+func (v *ViewCounterImpl) GetPending(ctx context.Context, storyID uuid.UUID) (*models.ViewCountDelta, error) {
+    values, err := v.redisClient.GetClient().HMGet(ctx, pendingViewsKey(storyID), "total", "unique").Result()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get pending views: %w", err)
+    }
+
+    return &models.ViewCountDelta{
+        StoryID:     storyID,
+        TotalViews:  parseCount(values[0]),
+        UniqueViews: parseCount(values[1]),
+    }, nil
+}
+
+// Drain takes up to limit stories' pending counts out of Redis for flushing
+func (v *ViewCounterImpl) Drain(ctx context.Context, limit int) ([]*models.ViewCountDelta, error) {
+    ids, err := v.redisClient.GetClient().SPopN(ctx, viewDirtyKey, int64(limit)).Result()
+    if err != nil {
+        return nil, fmt.Errorf("failed to get stories with pending views: %w", err)
+    }
+
+    deltas := make([]*models.ViewCountDelta, 0, len(ids))
+    for i, id := range ids {
+        storyID, err := uuid.Parse(id)
+        if err != nil {
+            v.logger.Warn("Skipping invalid story ID in view counter", zap.String("story_id", id))
+            continue
+        }
+
+        values, err := v.redisClient.Eval(ctx, drainViewsScript, []string{pendingViewsKey(storyID)}).Slice()
+        if err != nil {
+            // Put this and every other story we haven't drained back for the next run
+            remaining := make([]interface{}, 0, len(ids)-i)
+            for _, rest := range ids[i:] {
+                remaining = append(remaining, rest)
+            }
+            if restoreErr := v.redisClient.GetClient().SAdd(ctx, viewDirtyKey, remaining...).Err(); restoreErr != nil {
+                v.logger.Error("Failed to restore stories with pending views",
+                    zap.Int("count", len(remaining)),
+                    zap.Error(restoreErr),
+                )
+            }
+            return deltas, fmt.Errorf("failed to drain pending views: %w", err)
+        }
+
+        delta := &models.ViewCountDelta{
+            StoryID:     storyID,
+            TotalViews:  parseCount(values[0]),
+            UniqueViews: parseCount(values[1]),
+        }
+        if delta.IsEmpty() {
+            continue
+        }
+        deltas = append(deltas, delta)
+    }
+
+    return deltas, nil
+}
+
+// Restore puts drained counts back, for when they couldn't be flushed
+func (v *ViewCounterImpl) Restore(ctx context.Context, deltas []*models.ViewCountDelta) error {
+    pipe := v.redisClient.Pipeline()
+    for _, delta := range deltas {
+        key := pendingViewsKey(delta.StoryID)
+        pipe.HIncrBy(ctx, key, "total", delta.TotalViews)
+        pipe.HIncrBy(ctx, key, "unique", delta.UniqueViews)
+        pipe.SAdd(ctx, viewDirtyKey, delta.StoryID.String())
+    }
+
+    if _, err := pipe.Exec(ctx); err != nil {
+        return fmt.Errorf("failed to restore pending views: %w", err)
+    }
+
+    return nil
+}
+
+// viewerSetKey is the set of viewer IDs used to deduplicate a story's views
+func viewerSetKey(storyID uuid.UUID) string {
+    return fmt.Sprintf("story_views:viewers:%s", storyID.String())
+}
+
+// pendingViewsKey is the hash of counts not yet flushed to Postgres
+func pendingViewsKey(storyID uuid.UUID) string {
+    return fmt.Sprintf("story_views:pending:%s", storyID.String())
+}
+
+// parseCount reads a counter returned by Redis, treating missing values as zero
+func parseCount(value interface{}) int64 {
+    s, ok := value.(string)
+    if !ok {
+        return 0
+    }
+
+    count, err := strconv.ParseInt(s, 10, 64)
+    if err != nil {
+        return 0
+    }
+    return count
+}
@@ -31,16 +31,17 @@ func NewViewStore(db *sqlx.DB, redisClient *RedisClient, logger *zap.Logger) Vie
 // Create creates a new story view
 func (s *ViewStoreImpl) Create(ctx context.Context, view *models.StoryView) error {
     query := `
-        INSERT INTO story_views (id, story_id, viewer_id, viewed_at, ip_address, user_agent)
-        VALUES ($1, $2, $3, $4, $5, $6)
+        INSERT INTO story_views (id, story_id, viewer_id, viewed_at, ip_address, user_agent, is_anonymous)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
         ON CONFLICT (story_id, viewer_id) DO UPDATE SET
             viewed_at = EXCLUDED.viewed_at,
             ip_address = EXCLUDED.ip_address,
-            user_agent = EXCLUDED.user_agent`
+            user_agent = EXCLUDED.user_agent,
+            is_anonymous = story_views.is_anonymous OR EXCLUDED.is_anonymous`
 
     _, err := s.db.ExecContext(ctx, query,
         view.ID, view.StoryID, view.ViewerID, view.ViewedAt,
-        view.IPAddress, view.UserAgent,
+        view.IPAddress, view.UserAgent, view.IsAnonymous,
     )
 
     if err != nil {
@@ -60,7 +61,7 @@ func (s *ViewStoreImpl) Create(ctx context.Context, view *models.StoryView) erro
 func (s *ViewStoreImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.StoryView, error) {
     var view models.StoryView
     query := `
-        SELECT id, story_id, viewer_id, viewed_at, ip_address, user_agent
+        SELECT id, story_id, viewer_id, viewed_at, ip_address, user_agent, is_anonymous
         FROM story_views 
         WHERE id = $1`
 
@@ -75,15 +76,16 @@ func (s *ViewStoreImpl) GetByID(ctx context.Context, id uuid.UUID) (*models.Stor
     return &view, nil
 }
 
-// GetByStoryID gets views for a specific story
+// GetByStoryID gets the named viewers of a story. Views recorded anonymously
+// are left out.
 func (s *ViewStoreImpl) GetByStoryID(ctx context.Context, storyID uuid.UUID, limit, offset int) ([]*models.StoryViewWithUser, error) {
     query := `
-        SELECT sv.id, sv.story_id, sv.viewer_id, sv.viewed_at, sv.ip_address, sv.user_agent,
+        SELECT sv.id, sv.story_id, sv.viewer_id, sv.viewed_at,
                u.username as viewer_username, u.full_name as viewer_full_name,
                u.profile_picture as viewer_profile_picture, u.is_verified as viewer_is_verified
         FROM story_views sv
         JOIN users u ON sv.viewer_id = u.id
-        WHERE sv.story_id = $1 AND u.deleted_at IS NULL
+        WHERE sv.story_id = $1 AND u.deleted_at IS NULL AND NOT sv.is_anonymous
         ORDER BY sv.viewed_at DESC
         LIMIT $2 OFFSET $3`
 
@@ -96,10 +98,45 @@ func (s *ViewStoreImpl) GetByStoryID(ctx context.Context, storyID uuid.UUID, lim
     return views, nil
 }
 
+// GetSeenBy gets a "seen by" summary for a story: the total number of viewers and the
+// first limit named viewers, mutual follows of the author first
+func (s *ViewStoreImpl) GetSeenBy(ctx context.Context, storyID, authorID uuid.UUID, limit int) (*models.SeenBySummary, error) {
+    var totalViews int
+    err := s.db.GetContext(ctx, &totalViews,
+        "SELECT COUNT(*) FROM story_views WHERE story_id = $1", storyID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to count story viewers: %w", err)
+    }
+
+    query := `
+        SELECT sv.viewer_id, sv.viewed_at,
+               u.username, u.full_name, u.profile_picture, u.is_verified,
+               (EXISTS (
+                   SELECT 1 FROM follows f1
+                   WHERE f1.follower_id = $2 AND f1.followee_id = sv.viewer_id
+               ) AND EXISTS (
+                   SELECT 1 FROM follows f2
+                   WHERE f2.follower_id = sv.viewer_id AND f2.followee_id = $2
+               )) as is_mutual
+        FROM story_views sv
+        JOIN users u ON sv.viewer_id = u.id
+        WHERE sv.story_id = $1 AND u.deleted_at IS NULL AND NOT sv.is_anonymous
+        ORDER BY is_mutual DESC, sv.viewed_at DESC
+        LIMIT $3`
+
+    var viewers []*models.SeenByViewer
+    err = s.db.SelectContext(ctx, &viewers, query, storyID, authorID, limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get seen by viewers: %w", err)
+    }
+
+    return models.NewSeenBySummary(storyID, totalViews, viewers), nil
+}
+
 // GetByViewerID gets views by a specific viewer
 func (s *ViewStoreImpl) GetByViewerID(ctx context.Context, viewerID uuid.UUID, limit, offset int) ([]*models.StoryView, error) {
     query := `
-        SELECT id, story_id, viewer_id, viewed_at, ip_address, user_agent
+        SELECT id, story_id, viewer_id, viewed_at, ip_address, user_agent, is_anonymous
         FROM story_views
         WHERE viewer_id = $1
         ORDER BY viewed_at DESC
@@ -235,3 +272,51 @@ func (s *ViewStoreImpl) GetViewTrends(ctx context.Context, storyID uuid.UUID) ([
 
     return trends, nil
 }
+
+// CreateSegmentView records a view of a single segment of a sequence story
+func (s *ViewStoreImpl) CreateSegmentView(ctx context.Context, view *models.SegmentView) error {
+    query := `
+        INSERT INTO segment_views (id, story_id, segment_id, viewer_id, viewed_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (segment_id, viewer_id) DO UPDATE SET
+            viewed_at = EXCLUDED.viewed_at`
+
+    _, err := s.db.ExecContext(ctx, query,
+        view.ID, view.StoryID, view.SegmentID, view.ViewerID, view.ViewedAt,
+    )
+
+    if err != nil {
+        s.logger.Error("Failed to create segment view", zap.Error(err))
+        return fmt.Errorf("failed to create segment view: %w", err)
+    }
+
+    s.logger.Debug("Segment view created",
+        zap.String("story_id", view.StoryID.String()),
+        zap.String("segment_id", view.SegmentID.String()),
+        zap.String("viewer_id", view.ViewerID.String()),
+    )
+
+    return nil
+}
+
+// GetSegmentDropOff gets how many viewers reached each segment of a story
+func (s *ViewStoreImpl) GetSegmentDropOff(ctx context.Context, storyID uuid.UUID) (*models.SegmentDropOffReport, error) {
+    query := `
+        SELECT 
+            seg.id as segment_id,
+            seg.position,
+            COUNT(DISTINCT sv.viewer_id) as viewers
+        FROM story_segments seg
+        LEFT JOIN segment_views sv ON sv.segment_id = seg.id
+        WHERE seg.story_id = $1
+        GROUP BY seg.id, seg.position
+        ORDER BY seg.position ASC`
+
+    var segments []*models.SegmentDropOff
+    err := s.db.SelectContext(ctx, &segments, query, storyID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get segment drop-off: %w", err)
+    }
+
+    return models.NewSegmentDropOffReport(storyID, segments), nil
+}
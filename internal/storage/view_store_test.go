@@ -0,0 +1,127 @@
+package storage
+
+import (
+    "context"
+    "os"
+    "testing"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/google/uuid"
+    "github.com/jmoiron/sqlx"
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// newTestDB connects to the migrated database at POSTGRES_TEST_URL, skipping
+// the test when it isn't set
+func newTestDB(t *testing.T) (*sqlx.DB, *RedisClient) {
+    t.Helper()
+
+    url := os.Getenv("POSTGRES_TEST_URL")
+    if url == "" {
+        t.Skip("POSTGRES_TEST_URL not set")
+    }
+
+    db, err := sqlx.Connect("postgres", url)
+    if err != nil {
+        t.Fatalf("failed to connect to test database: %v", err)
+    }
+    t.Cleanup(func() { db.Close() })
+
+    server := miniredis.RunT(t)
+    redisClient, err := NewRedisClient(&config.Config{RedisURL: "redis://" + server.Addr()}, zap.NewNop())
+    if err != nil {
+        t.Fatalf("failed to connect to test Redis: %v", err)
+    }
+    t.Cleanup(func() { redisClient.Close() })
+
+    return db, redisClient
+}
+
+// createTestUser saves a user and removes it with its stories and views after the test
+func createTestUser(t *testing.T, db *sqlx.DB, users UserStore, name string) *models.User {
+    t.Helper()
+
+    suffix := uuid.NewString()[:8]
+    user, err := models.NewUser(name+suffix+"@example.com", name+"_"+suffix, "Password1!", name)
+    if err != nil {
+        t.Fatalf("failed to build user: %v", err)
+    }
+    if err := users.Create(context.Background(), user); err != nil {
+        t.Fatalf("failed to create user: %v", err)
+    }
+
+    t.Cleanup(func() {
+        db.Exec("DELETE FROM story_views WHERE viewer_id = $1 OR story_id IN (SELECT id FROM stories WHERE author_id = $1)", user.ID)
+        db.Exec("DELETE FROM stories WHERE author_id = $1", user.ID)
+        db.Exec("DELETE FROM users WHERE id = $1", user.ID)
+    })
+    return user
+}
+
+func TestAnonymousViewStaysHidden(t *testing.T) {
+    db, redisClient := newTestDB(t)
+    ctx := context.Background()
+    users := NewUserStore(db, redisClient, zap.NewNop())
+    stories := NewStoryStore(db, redisClient, zap.NewNop())
+    views := NewViewStore(db, redisClient, zap.NewNop())
+
+    author := createTestUser(t, db, users, "author")
+    anonymous := createTestUser(t, db, users, "anonymous")
+    named := createTestUser(t, db, users, "named")
+
+    anonymous.AnonymousPublicViews = true
+    if err := users.Update(ctx, anonymous); err != nil {
+        t.Fatalf("failed to update user: %v", err)
+    }
+
+    story := models.NewStory(author.ID, models.StoryCreateRequest{
+        Type:       models.StoryTypeText,
+        Visibility: models.VisibilityPublic,
+    })
+    if err := stories.Create(ctx, story); err != nil {
+        t.Fatalf("failed to create story: %v", err)
+    }
+    for _, viewer := range []*models.User{anonymous, named} {
+        if err := views.Create(ctx, models.NewStoryView(story, viewer, nil, nil)); err != nil {
+            t.Fatalf("failed to create view: %v", err)
+        }
+    }
+
+    checkViewers := func(when string) {
+        t.Helper()
+
+        viewers, err := views.GetByStoryID(ctx, story.ID, 10, 0)
+        if err != nil {
+            t.Fatalf("failed to get viewers: %v", err)
+        }
+        if len(viewers) != 1 || viewers[0].ViewerID != named.ID {
+            t.Errorf("%s: got %d viewers, want only the named viewer", when, len(viewers))
+        }
+
+        seenBy, err := views.GetSeenBy(ctx, story.ID, author.ID, 10)
+        if err != nil {
+            t.Fatalf("failed to get seen by: %v", err)
+        }
+        if len(seenBy.Viewers) != 1 || seenBy.Viewers[0].ViewerID != named.ID {
+            t.Errorf("%s: seen by %d viewers, want only the named viewer", when, len(seenBy.Viewers))
+        }
+    }
+
+    checkViewers("after viewing")
+
+    // Neither the story becoming friends-only nor the viewer turning the
+    // setting off reveals a view that was anonymous when it was made
+    story.Visibility = models.VisibilityFriends
+    if err := stories.Update(ctx, story, nil); err != nil {
+        t.Fatalf("failed to update story: %v", err)
+    }
+    anonymous.AnonymousPublicViews = false
+    if err := users.Update(ctx, anonymous); err != nil {
+        t.Fatalf("failed to update user: %v", err)
+    }
+
+    checkViewers("after visibility change")
+}
@@ -5,19 +5,22 @@ import (
     "fmt"
     "time"
 
-    "github.com/google/uuid"
     "go.uber.org/zap"
 
+    "github.com/Abhiro0p/stories-backend/internal/models"
     "github.com/Abhiro0p/stories-backend/internal/storage"
     "github.com/Abhiro0p/stories-backend/pkg/config"
 )
 
 // ExpirationWorker handles cleanup of expired stories
 type ExpirationWorker struct {
-    storyStore  storage.StoryStore
-    redisClient *storage.RedisClient
-    logger      *zap.Logger
-    config      config.WorkerConfig
+    storyStore   storage.StoryStore
+    userStore    storage.UserStore
+    stickerStore storage.StickerStore
+    redisClient  *storage.RedisClient
+    logger       *zap.Logger
+    config       config.WorkerConfig
+    draftMaxAge  time.Duration
     
     isRunning bool
     stopCh    chan struct{}
@@ -26,16 +29,22 @@ type ExpirationWorker struct {
 // NewExpirationWorker creates a new story expiration worker
 func NewExpirationWorker(
     storyStore storage.StoryStore,
+    userStore storage.UserStore,
+    stickerStore storage.StickerStore,
     redisClient *storage.RedisClient,
     logger *zap.Logger,
     config config.WorkerConfig,
+    draftMaxAge time.Duration,
 ) *ExpirationWorker {
     return &ExpirationWorker{
-        storyStore:  storyStore,
-        redisClient: redisClient,
-        logger:      logger.With(zap.String("worker", "expiration")),
-        config:      config,
-        stopCh:      make(chan struct{}),
+        storyStore:   storyStore,
+        userStore:    userStore,
+        stickerStore: stickerStore,
+        redisClient:  redisClient,
+        logger:       logger.With(zap.String("worker", "expiration")),
+        config:       config,
+        draftMaxAge:  draftMaxAge,
+        stopCh:       make(chan struct{}),
     }
 }
 
@@ -75,6 +84,9 @@ func (w *ExpirationWorker) Start(ctx context.Context) error {
             if err := w.processExpiredStories(ctx); err != nil {
                 w.logger.Error("Failed to process expired stories", zap.Error(err))
             }
+            if err := w.processStaleDrafts(ctx); err != nil {
+                w.logger.Error("Failed to process stale drafts", zap.Error(err))
+            }
         }
     }
 }
@@ -98,7 +110,7 @@ func (w *ExpirationWorker) Stop(ctx context.Context) error {
     return nil
 }
 
-// processExpiredStories processes and deletes expired stories
+// processExpiredStories archives or deletes expired stories
 func (w *ExpirationWorker) processExpiredStories(ctx context.Context) error {
     startTime := time.Now()
     
@@ -118,7 +130,7 @@ func (w *ExpirationWorker) processExpiredStories(ctx context.Context) error {
         
         // Process each expired story
         for _, story := range stories {
-            if err := w.processExpiredStory(ctx, story.ID); err != nil {
+            if err := w.processExpiredStory(ctx, story); err != nil {
                 w.logger.Error("Failed to process expired story", 
                     zap.String("story_id", story.ID.String()),
                     zap.Error(err),
@@ -166,11 +178,40 @@ func (w *ExpirationWorker) processExpiredStories(ctx context.Context) error {
     return nil
 }
 
-// processExpiredStory processes a single expired story
-func (w *ExpirationWorker) processExpiredStory(ctx context.Context, storyID uuid.UUID) error {
-    // Soft delete the story
-    if err := w.storyStore.Delete(ctx, storyID); err != nil {
-        return fmt.Errorf("failed to delete expired story: %w", err)
+// processExpiredStory archives a single expired story, or deletes it
+// permanently if its author turned archiving off
+func (w *ExpirationWorker) processExpiredStory(ctx context.Context, story *models.Story) error {
+    storyID := story.ID
+    
+    archive := true
+    author, err := w.userStore.GetByID(ctx, story.AuthorID)
+    if err != nil {
+        if err != storage.ErrNotFound {
+            return fmt.Errorf("failed to get story author: %w", err)
+        }
+        // Nobody is left to see the archive
+        archive = false
+    } else if author.ArchiveDisabled {
+        archive = false
+    }
+    
+    if archive {
+        story.Archive()
+        if err := w.storyStore.Archive(ctx, story); err != nil {
+            return fmt.Errorf("failed to archive expired story: %w", err)
+        }
+    } else {
+        if err := w.storyStore.HardDelete(ctx, storyID); err != nil {
+            return fmt.Errorf("failed to delete expired story: %w", err)
+        }
+    }
+    
+    // Sticker data expires with the story
+    if err := w.stickerStore.DeleteByStoryID(ctx, storyID); err != nil {
+        w.logger.Warn("Failed to delete stickers for expired story", 
+            zap.String("story_id", storyID.String()),
+            zap.Error(err),
+        )
     }
     
     // Clear related cache entries
@@ -190,11 +231,33 @@ func (w *ExpirationWorker) processExpiredStory(ctx context.Context, storyID uuid
     
     w.logger.Debug("Processed expired story", 
         zap.String("story_id", storyID.String()),
+        zap.Bool("archived", archive),
     )
     
     return nil
 }
 
+// processStaleDrafts removes drafts that were never published within draftMaxAge
+func (w *ExpirationWorker) processStaleDrafts(ctx context.Context) error {
+    if w.draftMaxAge <= 0 {
+        return nil
+    }
+    
+    deleted, err := w.storyStore.DeleteStaleDrafts(ctx, time.Now().Add(-w.draftMaxAge))
+    if err != nil {
+        return err
+    }
+    
+    if deleted > 0 {
+        w.logger.Info("Deleted stale drafts",
+            zap.Int64("count", deleted),
+            zap.Duration("max_age", w.draftMaxAge),
+        )
+    }
+    
+    return nil
+}
+
 // recordMetrics records expiration worker metrics
 func (w *ExpirationWorker) recordMetrics(processed int, duration time.Duration) {
     // Store metrics in Redis for monitoring
@@ -217,11 +280,12 @@ func (w *ExpirationWorker) recordMetrics(processed int, duration time.Duration)
 // GetStats returns worker statistics
 func (w *ExpirationWorker) GetStats() map[string]interface{} {
     return map[string]interface{}{
-        "name":         "expiration",
-        "running":      w.isRunning,
-        "interval":     w.config.Interval.String(),
-        "batch_size":   w.config.BatchSize,
-        "last_run":     time.Now().Format(time.RFC3339),
+        "name":          "expiration",
+        "running":       w.isRunning,
+        "interval":      w.config.Interval.String(),
+        "batch_size":    w.config.BatchSize,
+        "draft_max_age": w.draftMaxAge.String(),
+        "last_run":      time.Now().Format(time.RFC3339),
     }
 }
 
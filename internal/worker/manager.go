@@ -25,9 +25,13 @@ type Manager struct {
     followStore   storage.FollowStore
     viewStore     storage.ViewStore
     reactionStore storage.ReactionStore
+    stickerStore  storage.StickerStore
+    counterStore  storage.CounterStore
+    viewCounter   storage.ViewCounter
     
     // Workers
     expirationWorker *ExpirationWorker
+    viewFlushWorker  *ViewFlushWorker
     queue           *Queue
     
     // Control
@@ -38,6 +42,10 @@ type Manager struct {
     // Status
     isRunning bool
     mu        sync.RWMutex
+    
+    // Last counter reconciliation, written from the job queue
+    lastReconciliation *models.ReconciliationReport
+    reconcileMu        sync.Mutex
 }
 
 // NewManager creates a new worker manager
@@ -60,6 +68,9 @@ func NewManager(cfg *config.Config, logger *zap.Logger) (*Manager, error) {
     followStore := storage.NewFollowStore(db.DB(), redisClient, logger)
     viewStore := storage.NewViewStore(db.DB(), redisClient, logger)
     reactionStore := storage.NewReactionStore(db.DB(), redisClient, logger)
+    stickerStore := storage.NewStickerStore(db.DB(), redisClient, logger)
+    counterStore := storage.NewCounterStore(db.DB(), redisClient, logger)
+    viewCounter := storage.NewViewCounter(redisClient, logger)
 
     ctx, cancel := context.WithCancel(context.Background())
 
@@ -73,6 +84,9 @@ func NewManager(cfg *config.Config, logger *zap.Logger) (*Manager, error) {
         followStore:   followStore,
         viewStore:     viewStore,
         reactionStore: reactionStore,
+        stickerStore:  stickerStore,
+        counterStore:  counterStore,
+        viewCounter:   viewCounter,
         ctx:           ctx,
         cancel:        cancel,
     }
@@ -90,9 +104,20 @@ func (m *Manager) initializeWorkers() error {
     // Create expiration worker
     m.expirationWorker = NewExpirationWorker(
         m.storyStore,
+        m.userStore,
+        m.stickerStore,
         m.redisClient,
         m.logger,
         m.config.Workers.StoryExpiration,
+        m.config.Stories.DraftMaxAge,
+    )
+
+    // Create view count flush worker
+    m.viewFlushWorker = NewViewFlushWorker(
+        m.storyStore,
+        m.viewCounter,
+        m.logger,
+        m.config.Workers.ViewFlush,
     )
 
     // Create job queue
@@ -126,7 +151,10 @@ func (m *Manager) registerJobHandlers() {
     // Cleanup expired sessions job
     m.queue.RegisterHandler("cleanup_sessions", m.handleCleanupSessions)
     
-    m.logger.Info("Registered job handlers", zap.Int("handler_count", 5))
+    // Counter reconciliation job
+    m.queue.RegisterHandler("reconcile_counters", m.handleReconcileCounters)
+    
+    m.logger.Info("Registered job handlers", zap.Int("handler_count", 6))
 }
 
 // Start starts all workers
@@ -149,6 +177,15 @@ func (m *Manager) Start(ctx context.Context) error {
         }
     }()
 
+    // Start view flush worker
+    m.wg.Add(1)
+    go func() {
+        defer m.wg.Done()
+        if err := m.viewFlushWorker.Start(m.ctx); err != nil {
+            m.logger.Error("View flush worker failed", zap.Error(err))
+        }
+    }()
+
     // Start job queue
     m.wg.Add(1)
     go func() {
@@ -158,6 +195,13 @@ func (m *Manager) Start(ctx context.Context) error {
         }
     }()
 
+    // Schedule counter reconciliation
+    m.wg.Add(1)
+    go func() {
+        defer m.wg.Done()
+        m.scheduleReconciliation(m.ctx)
+    }()
+
     m.isRunning = true
     m.logger.Info("Worker manager started successfully")
     
@@ -197,6 +241,10 @@ func (m *Manager) Shutdown(ctx context.Context) error {
         m.logger.Error("Failed to stop expiration worker", zap.Error(err))
     }
 
+    if err := m.viewFlushWorker.Stop(ctx); err != nil {
+        m.logger.Error("Failed to stop view flush worker", zap.Error(err))
+    }
+
     if err := m.queue.Stop(ctx); err != nil {
         m.logger.Error("Failed to stop job queue", zap.Error(err))
     }
@@ -228,9 +276,15 @@ func (m *Manager) GetStatus() map[string]interface{} {
     m.mu.RLock()
     defer m.mu.RUnlock()
 
+    m.reconcileMu.Lock()
+    lastReconciliation := m.lastReconciliation
+    m.reconcileMu.Unlock()
+
     return map[string]interface{}{
-        "manager_running":    m.isRunning,
+        "manager_running":     m.isRunning,
+        "last_reconciliation": lastReconciliation,
         "expiration_running": m.expirationWorker.IsRunning(),
+        "view_flush_running": m.viewFlushWorker.IsRunning(),
         "queue_running":      m.queue.IsRunning(),
         "queue_stats":        m.queue.GetStats(),
     }
@@ -263,17 +317,17 @@ func (m *Manager) handleUpdateUserStats(job *Job) error {
         return fmt.Errorf("failed to get follow stats: %w", err)
     }
 
-    // Count user's stories
-    stories, err := m.storyStore.GetByAuthorID(ctx, userID, 1000, 0)
+    // Count user's published stories
+    storyCount, err := m.storyStore.CountByAuthorID(ctx, userID)
     if err != nil {
-        return fmt.Errorf("failed to get user stories: %w", err)
+        return fmt.Errorf("failed to count user stories: %w", err)
     }
 
     // Update user stats
     stats := models.UserStats{
         FollowerCount:  followStats.FollowerCount,
         FollowingCount: followStats.FollowingCount,
-        StoryCount:     len(stories),
+        StoryCount:     storyCount,
     }
 
     if err := m.userStore.UpdateStats(ctx, userID, stats); err != nil {
@@ -385,6 +439,70 @@ func (m *Manager) handleProcessAnalytics(job *Job) error {
     return nil
 }
 
+// scheduleReconciliation enqueues a counter reconciliation job on every interval
+func (m *Manager) scheduleReconciliation(ctx context.Context) {
+    ticker := time.NewTicker(m.config.Workers.CounterReconcile.Interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            if err := m.ScheduleJob("reconcile_counters", map[string]interface{}{}, 0); err != nil {
+                m.logger.Error("Failed to schedule counter reconciliation", zap.Error(err))
+            }
+        }
+    }
+}
+
+// handleReconcileCounters recomputes denormalized counters that have drifted
+// from the rows they count, and reports every correction
+func (m *Manager) handleReconcileCounters(job *Job) error {
+    _ = job // Mark as used to avoid compiler warning
+    
+    ctx := context.Background()
+    limit := m.config.Workers.CounterReconcile.BatchSize
+    
+    report := &models.ReconciliationReport{StartedAt: time.Now()}
+    
+    storyCorrections, err := m.counterStore.ReconcileStoryCounters(ctx, limit)
+    if err != nil {
+        return fmt.Errorf("failed to reconcile story counters: %w", err)
+    }
+    report.Corrections = append(report.Corrections, storyCorrections...)
+    
+    userCorrections, err := m.counterStore.ReconcileUserCounters(ctx, limit)
+    if err != nil {
+        return fmt.Errorf("failed to reconcile user counters: %w", err)
+    }
+    report.Corrections = append(report.Corrections, userCorrections...)
+    
+    report.Duration = time.Since(report.StartedAt)
+    
+    for _, correction := range report.Corrections {
+        m.logger.Info("Corrected drifted counter",
+            zap.String("entity", string(correction.Entity)),
+            zap.String("entity_id", correction.EntityID.String()),
+            zap.String("counter", correction.Counter),
+            zap.Int("old_value", correction.OldValue),
+            zap.Int("new_value", correction.NewValue),
+        )
+    }
+    
+    m.logger.Info("Counter reconciliation completed",
+        zap.Int("corrections", len(report.Corrections)),
+        zap.Any("by_counter", report.CountByCounter()),
+        zap.Duration("duration", report.Duration),
+    )
+    
+    m.reconcileMu.Lock()
+    m.lastReconciliation = report
+    m.reconcileMu.Unlock()
+    
+    return nil
+}
+
 // handleCleanupSessions cleans up expired sessions - FIXED
 func (m *Manager) handleCleanupSessions(job *Job) error {
     _ = job // Mark as used to avoid compiler warning
@@ -429,6 +547,20 @@ func (m *Manager) RestartWorker(workerName string) error {
             }
         }()
         
+    case "view_flush":
+        m.logger.Info("Restarting view flush worker")
+        if err := m.viewFlushWorker.Stop(context.Background()); err != nil {
+            m.logger.Error("Failed to stop view flush worker", zap.Error(err))
+        }
+        
+        m.wg.Add(1)
+        go func() {
+            defer m.wg.Done()
+            if err := m.viewFlushWorker.Start(m.ctx); err != nil {
+                m.logger.Error("Failed to restart view flush worker", zap.Error(err))
+            }
+        }()
+        
     case "queue":
         m.logger.Info("Restarting job queue")
         if err := m.queue.Stop(context.Background()); err != nil {
@@ -452,7 +584,7 @@ func (m *Manager) RestartWorker(workerName string) error {
 
 // GetWorkerList returns a list of available workers
 func (m *Manager) GetWorkerList() []string {
-    return []string{"expiration", "queue"}
+    return []string{"expiration", "view_flush", "queue"}
 }
 
 // ForceRunExpiration forces immediate expiration process
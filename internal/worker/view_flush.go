@@ -0,0 +1,163 @@
+package worker
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "go.uber.org/zap"
+
+    "github.com/Abhiro0p/stories-backend/internal/models"
+    "github.com/Abhiro0p/stories-backend/internal/storage"
+    "github.com/Abhiro0p/stories-backend/pkg/config"
+)
+
+// ViewFlushWorker moves view counts from Redis to Postgres in batches, so hot
+// stories don't take a row lock on every view. It can't be turned off: views
+// are only counted in Redis, so without it they would never reach Postgres.
+type ViewFlushWorker struct {
+    storyStore  storage.StoryStore
+    viewCounter storage.ViewCounter
+    logger      *zap.Logger
+    config      config.WorkerConfig
+
+    isRunning bool
+    stopCh    chan struct{}
+}
+
+// NewViewFlushWorker creates a new view count flush worker
+func NewViewFlushWorker(
+    storyStore storage.StoryStore,
+    viewCounter storage.ViewCounter,
+    logger *zap.Logger,
+    config config.WorkerConfig,
+) *ViewFlushWorker {
+    return &ViewFlushWorker{
+        storyStore:  storyStore,
+        viewCounter: viewCounter,
+        logger:      logger.With(zap.String("worker", "view_flush")),
+        config:      config,
+        stopCh:      make(chan struct{}),
+    }
+}
+
+// IsRunning returns whether the worker is running
+func (w *ViewFlushWorker) IsRunning() bool {
+    return w.isRunning
+}
+
+// Start starts the view flush worker
+func (w *ViewFlushWorker) Start(ctx context.Context) error {
+    if w.isRunning {
+        return fmt.Errorf("view flush worker is already running")
+    }
+
+    w.isRunning = true
+    w.logger.Info("Starting view flush worker",
+        zap.Duration("interval", w.config.Interval),
+        zap.Int("batch_size", w.config.BatchSize),
+    )
+
+    ticker := time.NewTicker(w.config.Interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            w.logger.Info("View flush worker stopping due to context cancellation")
+            w.flushOnStop()
+            w.isRunning = false
+            return nil
+
+        case <-w.stopCh:
+            w.logger.Info("View flush worker stopping")
+            w.flushOnStop()
+            w.isRunning = false
+            return nil
+
+        case <-ticker.C:
+            if err := w.flush(ctx); err != nil {
+                w.logger.Error("Failed to flush view counts", zap.Error(err))
+            }
+        }
+    }
+}
+
+// Stop stops the view flush worker
+func (w *ViewFlushWorker) Stop(ctx context.Context) error {
+    if !w.isRunning {
+        return nil
+    }
+
+    w.logger.Info("Stopping view flush worker")
+    close(w.stopCh)
+
+    // Wait a bit for graceful shutdown
+    select {
+    case <-time.After(5 * time.Second):
+        w.logger.Warn("View flush worker stop timeout")
+    case <-ctx.Done():
+    }
+
+    return nil
+}
+
+// flushOnStop writes out whatever is pending before the worker exits
+func (w *ViewFlushWorker) flushOnStop() {
+    ctx, cancel := context.WithTimeout(context.Background(), w.config.Timeout)
+    defer cancel()
+
+    if err := w.flush(ctx); err != nil {
+        w.logger.Error("Failed to flush view counts on stop", zap.Error(err))
+    }
+}
+
+// flush drains pending counts from Redis and applies them a batch at a time
+func (w *ViewFlushWorker) flush(ctx context.Context) error {
+    startTime := time.Now()
+    totalFlushed := 0
+
+    for {
+        deltas, err := w.viewCounter.Drain(ctx, w.config.BatchSize)
+        if err != nil {
+            if len(deltas) > 0 {
+                w.restore(ctx, deltas)
+            }
+            return fmt.Errorf("failed to drain view counts: %w", err)
+        }
+
+        if len(deltas) == 0 {
+            break
+        }
+
+        if err := w.storyStore.ApplyViewCounts(ctx, deltas); err != nil {
+            w.restore(ctx, deltas)
+            return fmt.Errorf("failed to apply view counts: %w", err)
+        }
+        totalFlushed += len(deltas)
+
+        // A short batch means the backlog is empty
+        if len(deltas) < w.config.BatchSize {
+            break
+        }
+    }
+
+    if totalFlushed > 0 {
+        w.logger.Debug("Flushed view counts",
+            zap.Int("stories", totalFlushed),
+            zap.Duration("duration", time.Since(startTime)),
+        )
+    }
+
+    return nil
+}
+
+// restore hands drained counts back to Redis so the next run retries them
+func (w *ViewFlushWorker) restore(ctx context.Context, deltas []*models.ViewCountDelta) {
+    if err := w.viewCounter.Restore(ctx, deltas); err != nil {
+        w.logger.Error("Failed to restore view counts, views were lost",
+            zap.Int("stories", len(deltas)),
+            zap.Error(err),
+        )
+    }
+}
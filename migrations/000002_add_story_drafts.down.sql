@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_stories_drafts_updated_at;
+DROP INDEX IF EXISTS idx_stories_author_status;
+
+ALTER TABLE stories DROP CONSTRAINT IF EXISTS stories_status_check;
+
+ALTER TABLE stories
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS expires_in,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,12 @@
+ALTER TABLE stories
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published',
+    ADD COLUMN IF NOT EXISTS expires_in INTEGER NOT NULL DEFAULT 86400,
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE stories SET published_at = created_at WHERE published_at IS NULL AND status = 'published';
+
+ALTER TABLE stories
+    ADD CONSTRAINT stories_status_check CHECK (status IN ('draft', 'published'));
+
+CREATE INDEX IF NOT EXISTS idx_stories_author_status ON stories (author_id, status) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_stories_drafts_updated_at ON stories (updated_at) WHERE status = 'draft';
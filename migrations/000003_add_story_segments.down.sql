@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_segment_views_story_id;
+
+DROP TABLE IF EXISTS segment_views;
+DROP TABLE IF EXISTS story_segments;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS story_segments (
+    id UUID PRIMARY KEY,
+    story_id UUID NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'image', 'video')),
+    text TEXT,
+    media_url TEXT,
+    media_key UUID,
+    caption VARCHAR(200),
+    duration INTEGER NOT NULL DEFAULT 5,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (story_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS segment_views (
+    id UUID PRIMARY KEY,
+    story_id UUID NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    segment_id UUID NOT NULL REFERENCES story_segments(id) ON DELETE CASCADE,
+    viewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    viewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (segment_id, viewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_segment_views_story_id ON segment_views (story_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS sticker_responses;
+
+DROP INDEX IF EXISTS idx_story_stickers_story_id;
+DROP TABLE IF EXISTS story_stickers;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS story_stickers (
+    id UUID PRIMARY KEY,
+    story_id UUID NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('poll', 'quiz', 'slider', 'question')),
+    question VARCHAR(200) NOT NULL,
+    options JSONB,
+    correct_option INTEGER,
+    emoji VARCHAR(16),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_story_stickers_story_id ON story_stickers (story_id);
+
+CREATE TABLE IF NOT EXISTS sticker_responses (
+    id UUID PRIMARY KEY,
+    sticker_id UUID NOT NULL REFERENCES story_stickers(id) ON DELETE CASCADE,
+    story_id UUID NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    option_index INTEGER,
+    slider_value INTEGER CHECK (slider_value BETWEEN 0 AND 100),
+    text VARCHAR(500),
+    is_correct BOOLEAN,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (sticker_id, user_id)
+);
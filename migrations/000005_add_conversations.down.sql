@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_messages_unread;
+DROP INDEX IF EXISTS idx_messages_conversation_created;
+DROP TABLE IF EXISTS messages;
+
+DROP INDEX IF EXISTS idx_conversations_user_b;
+DROP INDEX IF EXISTS idx_conversations_user_a;
+DROP TABLE IF EXISTS conversations;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS conversations (
+    id UUID PRIMARY KEY,
+    user_a_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_b_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_message_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_a_id, user_b_id),
+    CHECK (user_a_id <> user_b_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversations_user_a ON conversations (user_a_id, last_message_at DESC);
+CREATE INDEX IF NOT EXISTS idx_conversations_user_b ON conversations (user_b_id, last_message_at DESC);
+
+-- story_id is cleared if the story row is removed; story_preview keeps the snapshot
+CREATE TABLE IF NOT EXISTS messages (
+    id UUID PRIMARY KEY,
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipient_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    text VARCHAR(1000) NOT NULL,
+    story_id UUID REFERENCES stories(id) ON DELETE SET NULL,
+    story_preview JSONB,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_conversation_created ON messages (conversation_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_messages_unread ON messages (recipient_id, conversation_id) WHERE read_at IS NULL;
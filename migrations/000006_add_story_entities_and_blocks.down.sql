@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_story_entities_mention;
+DROP INDEX IF EXISTS idx_story_entities_hashtag;
+DROP INDEX IF EXISTS idx_story_entities_story_id;
+DROP TABLE IF EXISTS story_entities;
+
+DROP TABLE IF EXISTS blocks;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS blocks (
+    id UUID PRIMARY KEY,
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE TABLE IF NOT EXISTS story_entities (
+    id UUID PRIMARY KEY,
+    story_id UUID NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('mention', 'hashtag')),
+    value VARCHAR(100) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    "offset" INTEGER NOT NULL,
+    length INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_story_entities_story_id ON story_entities (story_id);
+CREATE INDEX IF NOT EXISTS idx_story_entities_hashtag ON story_entities (value) WHERE type = 'hashtag';
+CREATE INDEX IF NOT EXISTS idx_story_entities_mention ON story_entities (user_id) WHERE type = 'mention';
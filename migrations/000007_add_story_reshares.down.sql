@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_stories_original_story_id;
+
+ALTER TABLE stories
+    DROP COLUMN IF EXISTS original_author_id,
+    DROP COLUMN IF EXISTS original_story_id,
+    DROP COLUMN IF EXISTS share_count;
@@ -0,0 +1,6 @@
+ALTER TABLE stories
+    ADD COLUMN IF NOT EXISTS share_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS original_story_id UUID REFERENCES stories(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS original_author_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_stories_original_story_id ON stories (original_story_id) WHERE original_story_id IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_stories_geohash;
+
+ALTER TABLE stories
+    DROP COLUMN IF EXISTS hide_coordinates,
+    DROP COLUMN IF EXISTS geohash,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS place_name;
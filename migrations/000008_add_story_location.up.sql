@@ -0,0 +1,10 @@
+ALTER TABLE stories
+    ADD COLUMN IF NOT EXISTS place_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    ADD COLUMN IF NOT EXISTS geohash VARCHAR(12),
+    ADD COLUMN IF NOT EXISTS hide_coordinates BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- text_pattern_ops lets geohash prefix (LIKE) lookups use the index
+CREATE INDEX IF NOT EXISTS idx_stories_geohash ON stories (geohash text_pattern_ops)
+    WHERE geohash IS NOT NULL AND deleted_at IS NULL;
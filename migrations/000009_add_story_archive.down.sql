@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_stories_author_archived_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS archive_disabled;
+
+-- Archived stories go back to being soft deleted
+UPDATE stories SET deleted_at = archived_at, status = 'published' WHERE status = 'archived';
+
+ALTER TABLE stories DROP CONSTRAINT IF EXISTS stories_status_check;
+ALTER TABLE stories
+    ADD CONSTRAINT stories_status_check CHECK (status IN ('draft', 'published'));
+
+ALTER TABLE stories DROP COLUMN IF EXISTS archived_at;
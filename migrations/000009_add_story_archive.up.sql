@@ -0,0 +1,12 @@
+ALTER TABLE stories
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE stories DROP CONSTRAINT IF EXISTS stories_status_check;
+ALTER TABLE stories
+    ADD CONSTRAINT stories_status_check CHECK (status IN ('draft', 'published', 'archived'));
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS archive_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_stories_author_archived_at ON stories (author_id, archived_at DESC)
+    WHERE status = 'archived' AND deleted_at IS NULL;
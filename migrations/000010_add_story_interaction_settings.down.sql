@@ -0,0 +1,7 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS default_replies_disabled,
+    DROP COLUMN IF EXISTS default_allow_reactions;
+
+ALTER TABLE stories
+    DROP COLUMN IF EXISTS replies_disabled,
+    DROP COLUMN IF EXISTS allow_reactions;
@@ -0,0 +1,9 @@
+ALTER TABLE stories
+    ADD COLUMN IF NOT EXISTS allow_reactions VARCHAR(20) NOT NULL DEFAULT 'everyone'
+        CHECK (allow_reactions IN ('everyone', 'followers', 'nobody')),
+    ADD COLUMN IF NOT EXISTS replies_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS default_allow_reactions VARCHAR(20) NOT NULL DEFAULT 'everyone'
+        CHECK (default_allow_reactions IN ('everyone', 'followers', 'nobody')),
+    ADD COLUMN IF NOT EXISTS default_replies_disabled BOOLEAN NOT NULL DEFAULT FALSE;
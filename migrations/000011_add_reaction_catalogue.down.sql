@@ -0,0 +1,9 @@
+DELETE FROM reactions
+WHERE type NOT IN ('like', 'love', 'laugh', 'wow', 'sad', 'angry', 'fire', 'hundred');
+
+ALTER TABLE reactions ALTER COLUMN type TYPE VARCHAR(20);
+ALTER TABLE reactions
+    ADD CONSTRAINT reactions_type_check
+    CHECK (type IN ('like', 'love', 'laugh', 'wow', 'sad', 'angry', 'fire', 'hundred'));
+
+DROP TABLE IF EXISTS reaction_types;
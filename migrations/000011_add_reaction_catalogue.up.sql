@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS reaction_types (
+    type VARCHAR(32) PRIMARY KEY,
+    emoji VARCHAR(64) NOT NULL UNIQUE,
+    display_name VARCHAR(50) NOT NULL,
+    is_positive BOOLEAN NOT NULL DEFAULT TRUE,
+    position INTEGER NOT NULL DEFAULT 0,
+    retired_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO reaction_types (type, emoji, display_name, is_positive, position) VALUES
+    ('like', '👍', 'Like', TRUE, 0),
+    ('love', '❤️', 'Love', TRUE, 1),
+    ('laugh', '😂', 'Laugh', TRUE, 2),
+    ('wow', '😮', 'Wow', TRUE, 3),
+    ('sad', '😢', 'Sad', FALSE, 4),
+    ('angry', '😡', 'Angry', FALSE, 5),
+    ('fire', '🔥', 'Fire', TRUE, 6),
+    ('hundred', '💯', '100', TRUE, 7)
+ON CONFLICT (type) DO NOTHING;
+
+-- Reaction types are validated against the catalogue, and arbitrary
+-- emoji reactions store the emoji itself as the type
+ALTER TABLE reactions DROP CONSTRAINT IF EXISTS reactions_type_check;
+ALTER TABLE reactions ALTER COLUMN type TYPE VARCHAR(64) USING type::text;
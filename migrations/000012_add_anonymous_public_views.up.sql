@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS anonymous_public_views BOOLEAN NOT NULL DEFAULT FALSE;
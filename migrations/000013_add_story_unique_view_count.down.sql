@@ -0,0 +1,2 @@
+ALTER TABLE stories
+    DROP COLUMN IF EXISTS unique_view_count;
@@ -0,0 +1,10 @@
+ALTER TABLE stories
+    ADD COLUMN IF NOT EXISTS unique_view_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE stories s SET
+    unique_view_count = v.viewers,
+    view_count = GREATEST(s.view_count, v.viewers)
+FROM (
+    SELECT story_id, COUNT(*) AS viewers FROM story_views GROUP BY story_id
+) v
+WHERE s.id = v.story_id;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS story_revisions;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS is_moderator;
+
+ALTER TABLE stories
+    DROP COLUMN IF EXISTS edited_at;
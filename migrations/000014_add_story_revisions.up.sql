@@ -0,0 +1,19 @@
+ALTER TABLE stories
+    ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS is_moderator BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- story_id has no foreign key so revisions outlive the story for moderation
+CREATE TABLE IF NOT EXISTS story_revisions (
+    id UUID PRIMARY KEY,
+    story_id UUID NOT NULL,
+    revision INTEGER NOT NULL,
+    editor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    text_before TEXT,
+    text_after TEXT,
+    visibility_before VARCHAR(20) NOT NULL,
+    visibility_after VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (story_id, revision)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS hide_activity_status BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1 @@
+ALTER TABLE story_views DROP COLUMN IF EXISTS is_anonymous;
@@ -0,0 +1,8 @@
+ALTER TABLE story_views
+    ADD COLUMN IF NOT EXISTS is_anonymous BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Existing views keep the anonymity they are shown with today
+UPDATE story_views sv SET is_anonymous = TRUE
+FROM stories s, users u
+WHERE sv.story_id = s.id AND sv.viewer_id = u.id
+AND s.visibility = 'public' AND u.anonymous_public_views;
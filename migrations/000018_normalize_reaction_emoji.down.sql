@@ -0,0 +1,2 @@
+-- Normalized reactions keep their normalized types
+DROP INDEX IF EXISTS idx_reaction_types_emoji_normalized;
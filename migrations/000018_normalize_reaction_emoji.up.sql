@@ -0,0 +1,15 @@
+-- Emoji are matched without the emoji presentation selector (U+FE0F), so the
+-- catalogue can't hold the same emoji with and without it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reaction_types_emoji_normalized
+    ON reaction_types (REPLACE(emoji, U&'\FE0F', ''));
+
+-- Arbitrary emoji reactions that are a catalogue emoji typed differently count
+-- as that type, and the rest are stored without the selector
+UPDATE reactions r SET type = rt.type
+FROM reaction_types rt
+WHERE rt.retired_at IS NULL
+AND r.type <> rt.type
+AND REPLACE(r.type, U&'\FE0F', '') = REPLACE(rt.emoji, U&'\FE0F', '');
+
+UPDATE reactions SET type = REPLACE(type, U&'\FE0F', '')
+WHERE POSITION(U&'\FE0F' IN type) > 0;
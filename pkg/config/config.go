@@ -2,6 +2,7 @@ package config
 
 import (
     "fmt"
+    "strings"
     "time"
 
     "github.com/spf13/viper"
@@ -41,12 +42,18 @@ type Config struct {
     // Media configuration
     PresignedURLExpiryMinutes int `mapstructure:"PRESIGNED_URL_EXPIRY_MINUTES"`
     
+    // Story configuration
+    Stories StoryConfig `mapstructure:",squash"`
+    
     // Rate limiting
     RateLimit RateLimitConfig `mapstructure:",squash"`
     
     // CORS configuration
     CORS CORSConfig `mapstructure:",squash"`
     
+    // Realtime configuration
+    Realtime RealtimeConfig `mapstructure:",squash"`
+    
     // Logging configuration
     LogLevel  string `mapstructure:"LOG_LEVEL"`
     LogFormat string `mapstructure:"LOG_FORMAT"`
@@ -75,6 +82,14 @@ type RedisConfig struct {
     IdleTimeout  time.Duration `mapstructure:"REDIS_IDLE_TIMEOUT"`
 }
 
+// StoryConfig holds story lifecycle configuration
+type StoryConfig struct {
+    DraftMaxAge time.Duration `mapstructure:"STORY_DRAFT_MAX_AGE"`
+    
+    // ReactionsAllowAnyEmoji accepts any single emoji as a reaction, not just catalogue types
+    ReactionsAllowAnyEmoji bool `mapstructure:"STORY_REACTIONS_ALLOW_ANY_EMOJI"`
+}
+
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
     Enabled           bool `mapstructure:"RATE_LIMIT_ENABLED"`
@@ -93,6 +108,59 @@ type CORSConfig struct {
     MaxAge          int      `mapstructure:"CORS_MAX_AGE"`
 }
 
+// AllowsOrigin checks if an origin is in the allowed origins, which may
+// contain "*" or wildcard subdomains such as "*.example.com"
+func (c CORSConfig) AllowsOrigin(origin string) bool {
+    for _, allowed := range c.AllowedOrigins {
+        if allowed == "*" || allowed == origin {
+            return true
+        }
+        
+        // Support for wildcard subdomains
+        if strings.HasPrefix(allowed, "*.") {
+            domain := strings.TrimPrefix(allowed, "*.")
+            if strings.HasSuffix(origin, "."+domain) || origin == domain {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// RealtimeConfig holds WebSocket hub configuration
+type RealtimeConfig struct {
+    // ClusterEnabled relays hub events between API instances over Redis pub/sub
+    ClusterEnabled bool          `mapstructure:"REALTIME_CLUSTER_ENABLED"`
+    ClusterChannel string        `mapstructure:"REALTIME_CLUSTER_CHANNEL"`
+    PresenceTTL    time.Duration `mapstructure:"REALTIME_PRESENCE_TTL"`
+    
+    // MaxSubscriptions caps the topics a single connection can subscribe to
+    MaxSubscriptions int `mapstructure:"REALTIME_MAX_SUBSCRIPTIONS"`
+    
+    // Events kept per user for replay to reconnecting clients
+    ReplaySize int           `mapstructure:"REALTIME_REPLAY_SIZE"`
+    ReplayTTL  time.Duration `mapstructure:"REALTIME_REPLAY_TTL"`
+    
+    // ActivityInterval is the least time between last-active writes for a user
+    ActivityInterval time.Duration `mapstructure:"REALTIME_ACTIVITY_INTERVAL"`
+    
+    // Flow control for each connection
+    SendBuffer        int     `mapstructure:"REALTIME_SEND_BUFFER"`
+    LowPriorityBuffer int     `mapstructure:"REALTIME_LOW_PRIORITY_BUFFER"`
+    InboundRate       float64 `mapstructure:"REALTIME_INBOUND_RATE"`
+    InboundBurst      int     `mapstructure:"REALTIME_INBOUND_BURST"`
+    
+    // Compression negotiates permessage-deflate with clients that support it
+    Compression bool `mapstructure:"REALTIME_COMPRESSION"`
+    
+    // TokenRecheckInterval is how often connected clients' tokens are validated again
+    TokenRecheckInterval time.Duration `mapstructure:"REALTIME_TOKEN_RECHECK_INTERVAL"`
+    
+    // AllowQueryToken accepts access tokens in the token query parameter, for
+    // clients that don't use tickets yet. Tokens in URLs end up in proxy logs.
+    AllowQueryToken bool `mapstructure:"REALTIME_ALLOW_QUERY_TOKEN"`
+}
+
 // WorkerConfig represents configuration for a single worker
 type WorkerConfig struct {
     Enabled     bool          `mapstructure:"enabled" json:"enabled"`
@@ -112,6 +180,12 @@ type WorkersConfig struct {
     // Queue worker (matches QUEUE_WORKER_* env vars)
     Queue WorkerConfig `mapstructure:"queue_worker" json:"queue_worker"`
     
+    // View count flush worker (matches VIEW_FLUSH_WORKER_* env vars)
+    ViewFlush WorkerConfig `mapstructure:"view_flush_worker" json:"view_flush_worker"`
+    
+    // Counter reconciliation job (matches COUNTER_RECONCILE_* env vars)
+    CounterReconcile WorkerConfig `mapstructure:"counter_reconcile" json:"counter_reconcile"`
+    
     // Additional workers with default names
     CacheCleanup  WorkerConfig `mapstructure:"cache_cleanup" json:"cache_cleanup"`
     UserStats     WorkerConfig `mapstructure:"user_stats" json:"user_stats"`
@@ -166,6 +240,21 @@ func normalizeWorkerConfigs(config *Config) {
     if config.Workers.Queue.Interval == 0 {
         config.Workers.Queue.Interval = 5 * time.Second
     }
+    if config.Workers.ViewFlush.Interval == 0 {
+        config.Workers.ViewFlush.Interval = 10 * time.Second
+    }
+    if config.Workers.ViewFlush.BatchSize == 0 {
+        config.Workers.ViewFlush.BatchSize = 500
+    }
+    if config.Workers.ViewFlush.Timeout == 0 {
+        config.Workers.ViewFlush.Timeout = 30 * time.Second
+    }
+    if config.Workers.CounterReconcile.Interval == 0 {
+        config.Workers.CounterReconcile.Interval = time.Hour
+    }
+    if config.Workers.CounterReconcile.BatchSize == 0 {
+        config.Workers.CounterReconcile.BatchSize = 1000
+    }
 }
 
 // setDefaults sets default configuration values
@@ -198,6 +287,10 @@ func setDefaults() {
     viper.SetDefault("MINIO_REGION", "us-east-1")
     viper.SetDefault("PRESIGNED_URL_EXPIRY_MINUTES", 15)
     
+    // Story defaults
+    viper.SetDefault("STORY_DRAFT_MAX_AGE", "720h") // 30 days
+    viper.SetDefault("STORY_REACTIONS_ALLOW_ANY_EMOJI", false)
+    
     // Rate limiting defaults
     viper.SetDefault("RATE_LIMIT_ENABLED", true)
     viper.SetDefault("RATE_LIMIT_REQUESTS_PER_MINUTE", 60)
@@ -207,10 +300,26 @@ func setDefaults() {
     viper.SetDefault("CORS_ENABLED", true)
     viper.SetDefault("CORS_ALLOWED_ORIGINS", []string{"*"})
     viper.SetDefault("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
-    viper.SetDefault("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization"})
+    viper.SetDefault("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization", "Idempotency-Key"})
     viper.SetDefault("CORS_ALLOW_CREDENTIALS", true)
     viper.SetDefault("CORS_MAX_AGE", 86400)
     
+    // Realtime defaults
+    viper.SetDefault("REALTIME_CLUSTER_ENABLED", true)
+    viper.SetDefault("REALTIME_CLUSTER_CHANNEL", "realtime:events")
+    viper.SetDefault("REALTIME_PRESENCE_TTL", "60s")
+    viper.SetDefault("REALTIME_MAX_SUBSCRIPTIONS", 50)
+    viper.SetDefault("REALTIME_REPLAY_SIZE", 100)
+    viper.SetDefault("REALTIME_REPLAY_TTL", "10m")
+    viper.SetDefault("REALTIME_ACTIVITY_INTERVAL", "1m")
+    viper.SetDefault("REALTIME_SEND_BUFFER", 256)
+    viper.SetDefault("REALTIME_LOW_PRIORITY_BUFFER", 64)
+    viper.SetDefault("REALTIME_INBOUND_RATE", 10)
+    viper.SetDefault("REALTIME_INBOUND_BURST", 20)
+    viper.SetDefault("REALTIME_COMPRESSION", true)
+    viper.SetDefault("REALTIME_TOKEN_RECHECK_INTERVAL", "5m")
+    viper.SetDefault("REALTIME_ALLOW_QUERY_TOKEN", false)
+    
     // Logging defaults
     viper.SetDefault("LOG_LEVEL", "info")
     viper.SetDefault("LOG_FORMAT", "json")
@@ -239,6 +348,11 @@ func setWorkerDefaults() {
     viper.SetDefault("QUEUE_WORKER_TIMEOUT", "5m")
     viper.SetDefault("QUEUE_WORKER_MAX_RETRIES", 3)
     
+    // View count flush worker
+    viper.SetDefault("VIEW_FLUSH_WORKER_INTERVAL", "10s")
+    viper.SetDefault("VIEW_FLUSH_WORKER_BATCH_SIZE", 500)
+    viper.SetDefault("VIEW_FLUSH_WORKER_TIMEOUT", "30s")
+    
     // Additional workers with default values
     viper.SetDefault("CACHE_CLEANUP_ENABLED", true)
     viper.SetDefault("CACHE_CLEANUP_INTERVAL", "1h")
@@ -261,6 +375,9 @@ func setWorkerDefaults() {
     viper.SetDefault("ANALYTICS_BATCH_SIZE", 200)
     viper.SetDefault("ANALYTICS_TIMEOUT", "10m")
     
+    viper.SetDefault("COUNTER_RECONCILE_INTERVAL", "1h")
+    viper.SetDefault("COUNTER_RECONCILE_BATCH_SIZE", 1000)
+    
     viper.SetDefault("HEALTH_CHECK_ENABLED", true)
     viper.SetDefault("HEALTH_CHECK_INTERVAL", "30s")
     viper.SetDefault("HEALTH_CHECK_TIMEOUT", "10s")
@@ -0,0 +1,176 @@
+// Package emoji validates emoji following the sequence rules of Unicode
+// Technical Standard #51.
+package emoji
+
+import (
+    "strings"
+    "unicode"
+)
+
+const (
+    zwj               = '\u200D'
+    variationSelector = '\uFE0F'
+    combiningKeycap   = '\u20E3'
+    blackFlag         = '\U0001F3F4'
+    tagCancel         = '\U000E007F'
+)
+
+// MaxSequenceRunes bounds the length of a single emoji sequence.
+// The longest recommended ZWJ sequences are 10 code points.
+const MaxSequenceRunes = 16
+
+// pictographic approximates the Extended_Pictographic property for the
+// blocks that contain emoji
+var pictographic = &unicode.RangeTable{
+    R16: []unicode.Range16{
+        {Lo: 0x00A9, Hi: 0x00A9, Stride: 1},
+        {Lo: 0x00AE, Hi: 0x00AE, Stride: 1},
+        {Lo: 0x203C, Hi: 0x203C, Stride: 1},
+        {Lo: 0x2049, Hi: 0x2049, Stride: 1},
+        {Lo: 0x2122, Hi: 0x2122, Stride: 1},
+        {Lo: 0x2139, Hi: 0x2139, Stride: 1},
+        {Lo: 0x2194, Hi: 0x2199, Stride: 1},
+        {Lo: 0x21A9, Hi: 0x21AA, Stride: 1},
+        {Lo: 0x231A, Hi: 0x231B, Stride: 1},
+        {Lo: 0x2328, Hi: 0x2328, Stride: 1},
+        {Lo: 0x23CF, Hi: 0x23CF, Stride: 1},
+        {Lo: 0x23E9, Hi: 0x23F3, Stride: 1},
+        {Lo: 0x23F8, Hi: 0x23FA, Stride: 1},
+        {Lo: 0x24C2, Hi: 0x24C2, Stride: 1},
+        {Lo: 0x25AA, Hi: 0x25AB, Stride: 1},
+        {Lo: 0x25B6, Hi: 0x25B6, Stride: 1},
+        {Lo: 0x25C0, Hi: 0x25C0, Stride: 1},
+        {Lo: 0x25FB, Hi: 0x25FE, Stride: 1},
+        {Lo: 0x2600, Hi: 0x27BF, Stride: 1},
+        {Lo: 0x2934, Hi: 0x2935, Stride: 1},
+        {Lo: 0x2B05, Hi: 0x2B07, Stride: 1},
+        {Lo: 0x2B1B, Hi: 0x2B1C, Stride: 1},
+        {Lo: 0x2B50, Hi: 0x2B50, Stride: 1},
+        {Lo: 0x2B55, Hi: 0x2B55, Stride: 1},
+        {Lo: 0x3030, Hi: 0x3030, Stride: 1},
+        {Lo: 0x303D, Hi: 0x303D, Stride: 1},
+        {Lo: 0x3297, Hi: 0x3297, Stride: 1},
+        {Lo: 0x3299, Hi: 0x3299, Stride: 1},
+    },
+    R32: []unicode.Range32{
+        {Lo: 0x1F000, Hi: 0x1F0FF, Stride: 1},
+        {Lo: 0x1F10D, Hi: 0x1F10F, Stride: 1},
+        {Lo: 0x1F12F, Hi: 0x1F12F, Stride: 1},
+        {Lo: 0x1F16C, Hi: 0x1F171, Stride: 1},
+        {Lo: 0x1F17E, Hi: 0x1F17F, Stride: 1},
+        {Lo: 0x1F18E, Hi: 0x1F18E, Stride: 1},
+        {Lo: 0x1F191, Hi: 0x1F19A, Stride: 1},
+        {Lo: 0x1F201, Hi: 0x1F2FF, Stride: 1},
+        {Lo: 0x1F300, Hi: 0x1F3FA, Stride: 1},
+        {Lo: 0x1F400, Hi: 0x1F6FF, Stride: 1},
+        {Lo: 0x1F774, Hi: 0x1F77F, Stride: 1},
+        {Lo: 0x1F7D5, Hi: 0x1F7FF, Stride: 1},
+        {Lo: 0x1F80C, Hi: 0x1F80F, Stride: 1},
+        {Lo: 0x1F848, Hi: 0x1F84F, Stride: 1},
+        {Lo: 0x1F85A, Hi: 0x1F85F, Stride: 1},
+        {Lo: 0x1F888, Hi: 0x1F88F, Stride: 1},
+        {Lo: 0x1F8AE, Hi: 0x1F8FF, Stride: 1},
+        {Lo: 0x1F90C, Hi: 0x1F93A, Stride: 1},
+        {Lo: 0x1F93C, Hi: 0x1F945, Stride: 1},
+        {Lo: 0x1F947, Hi: 0x1FAFF, Stride: 1},
+        {Lo: 0x1FC00, Hi: 0x1FFFD, Stride: 1},
+    },
+}
+
+// IsEmoji reports whether s is exactly one emoji: a single pictograph,
+// keycap, flag, tag sequence or ZWJ sequence, with optional presentation
+// selectors and skin tone modifiers
+func IsEmoji(s string) bool {
+    runes := []rune(s)
+    if len(runes) == 0 || len(runes) > MaxSequenceRunes {
+        return false
+    }
+
+    if isFlag(runes) || isKeycap(runes) {
+        return true
+    }
+
+    // Split into ZWJ elements; each must be a valid emoji element
+    start := 0
+    for i := 0; i <= len(runes); i++ {
+        if i < len(runes) && runes[i] != zwj {
+            continue
+        }
+        if !isElement(runes[start:i]) {
+            return false
+        }
+        start = i + 1
+    }
+    return true
+}
+
+// Normalize removes emoji presentation selectors, so the same emoji typed with
+// and without them compares equal. Keycaps and sequences stay valid without them.
+func Normalize(s string) string {
+    return strings.ReplaceAll(s, string(variationSelector), "")
+}
+
+// isElement checks a single ZWJ element: a pictograph followed by an
+// optional emoji presentation selector, skin tone modifier or tag spec
+func isElement(runes []rune) bool {
+    if len(runes) == 0 || !IsPictographic(runes[0]) {
+        return false
+    }
+
+    rest := runes[1:]
+    if len(rest) > 0 && rest[0] == variationSelector {
+        rest = rest[1:]
+    }
+    if len(rest) > 0 && isModifier(rest[0]) {
+        rest = rest[1:]
+    }
+    if len(rest) == 0 {
+        return true
+    }
+
+    // Subdivision flags: black flag, tag characters, cancel tag
+    if runes[0] == blackFlag && rest[len(rest)-1] == tagCancel && len(rest) > 1 {
+        for _, r := range rest[:len(rest)-1] {
+            if r < 0xE0020 || r > 0xE007E {
+                return false
+            }
+        }
+        return true
+    }
+    return false
+}
+
+// IsPictographic checks if r is an emoji pictograph
+func IsPictographic(r rune) bool {
+    return unicode.Is(pictographic, r)
+}
+
+// isModifier checks for the Fitzpatrick skin tone modifiers
+func isModifier(r rune) bool {
+    return r >= 0x1F3FB && r <= 0x1F3FF
+}
+
+// isRegionalIndicator checks for the letters used to build flags
+func isRegionalIndicator(r rune) bool {
+    return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isFlag checks for a pair of regional indicators
+func isFlag(runes []rune) bool {
+    return len(runes) == 2 && isRegionalIndicator(runes[0]) && isRegionalIndicator(runes[1])
+}
+
+// isKeycap checks for a digit, # or * followed by the combining keycap
+func isKeycap(runes []rune) bool {
+    if len(runes) < 2 || len(runes) > 3 {
+        return false
+    }
+    base := runes[0]
+    if !(base >= '0' && base <= '9') && base != '#' && base != '*' {
+        return false
+    }
+    if len(runes) == 3 && runes[1] != variationSelector {
+        return false
+    }
+    return runes[len(runes)-1] == combiningKeycap
+}
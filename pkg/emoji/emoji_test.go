@@ -0,0 +1,76 @@
+package emoji
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestIsEmoji(t *testing.T) {
+    tests := []struct {
+        name  string
+        input string
+        want  bool
+    }{
+        {name: "pictograph", input: "\U0001F44D", want: true},
+        {name: "text default with selector", input: "❤️", want: true},
+        {name: "text default without selector", input: "❤", want: true},
+        {name: "copyright sign", input: "©️", want: true},
+        {name: "skin tone modifier", input: "\U0001F44D\U0001F3FD", want: true},
+        {name: "family", input: "\U0001F468‍\U0001F469‍\U0001F467‍\U0001F466", want: true},
+        {name: "modifier inside sequence", input: "\U0001F469\U0001F3FD‍\U0001F4BB", want: true},
+        {name: "rainbow flag", input: "\U0001F3F3️‍\U0001F308", want: true},
+        {name: "country flag", input: "\U0001F1FA\U0001F1F8", want: true},
+        {name: "subdivision flag", input: "\U0001F3F4\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F", want: true},
+        {name: "keycap", input: "1️⃣", want: true},
+        {name: "keycap without selector", input: "#⃣", want: true},
+
+        {name: "empty", input: "", want: false},
+        {name: "letter", input: "a", want: false},
+        {name: "digit", input: "1", want: false},
+        {name: "word", input: "like", want: false},
+        {name: "letter with selector", input: "a️", want: false},
+        {name: "two emoji", input: "\U0001F44D\U0001F44D", want: false},
+        {name: "emoji and text", input: "\U0001F44Dok", want: false},
+        {name: "lone modifier", input: "\U0001F3FD", want: false},
+        {name: "lone regional indicator", input: "\U0001F1FA", want: false},
+        {name: "two flags", input: "\U0001F1FA\U0001F1F8\U0001F1EC\U0001F1E7", want: false},
+        {name: "leading joiner", input: "‍\U0001F44D", want: false},
+        {name: "trailing joiner", input: "\U0001F44D‍", want: false},
+        {name: "keycap on letter", input: "a⃣", want: false},
+        {name: "unterminated tag sequence", input: "\U0001F3F4\U000E0067\U000E0062", want: false},
+        {name: "too long", input: strings.Repeat("\U0001F44D‍", MaxSequenceRunes/2) + "\U0001F44D", want: false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := IsEmoji(tt.input); got != tt.want {
+                t.Errorf("IsEmoji(%+q) = %v, want %v", tt.input, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestNormalize(t *testing.T) {
+    tests := []struct {
+        name  string
+        input string
+        want  string
+    }{
+        {name: "selector removed", input: "❤️", want: "❤"},
+        {name: "no selector", input: "❤", want: "❤"},
+        {name: "keycap", input: "1️⃣", want: "1⃣"},
+        {name: "sequence", input: "\U0001F3F3️‍\U0001F308", want: "\U0001F3F3‍\U0001F308"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := Normalize(tt.input)
+            if got != tt.want {
+                t.Errorf("Normalize(%+q) = %+q, want %+q", tt.input, got, tt.want)
+            }
+            if !IsEmoji(got) {
+                t.Errorf("Normalize(%+q) = %+q is no longer an emoji", tt.input, got)
+            }
+        })
+    }
+}
@@ -0,0 +1,112 @@
+package geohash
+
+import (
+    "math"
+    "strings"
+)
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Encode encodes a coordinate as a geohash of the given length
+func Encode(latitude, longitude float64, precision int) string {
+    latRange := [2]float64{-90, 90}
+    lonRange := [2]float64{-180, 180}
+
+    var hash strings.Builder
+    bit, ch := 0, 0
+    even := true
+
+    for hash.Len() < precision {
+        if even {
+            mid := (lonRange[0] + lonRange[1]) / 2
+            if longitude >= mid {
+                ch |= 1 << (4 - bit)
+                lonRange[0] = mid
+            } else {
+                lonRange[1] = mid
+            }
+        } else {
+            mid := (latRange[0] + latRange[1]) / 2
+            if latitude >= mid {
+                ch |= 1 << (4 - bit)
+                latRange[0] = mid
+            } else {
+                latRange[1] = mid
+            }
+        }
+        even = !even
+
+        if bit < 4 {
+            bit++
+        } else {
+            hash.WriteByte(base32[ch])
+            bit, ch = 0, 0
+        }
+    }
+
+    return hash.String()
+}
+
+// CellSize returns the height and width in degrees of a geohash cell of the given length
+func CellSize(precision int) (latDegrees, lonDegrees float64) {
+    bits := precision * 5
+    lonBits := (bits + 1) / 2
+    latBits := bits / 2
+    return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lonBits))
+}
+
+// PrecisionForRadius returns the longest geohash length whose cells at the given latitude are at least
+// radiusMeters across, so that a cell and its neighbours always cover a circle of that radius
+func PrecisionForRadius(radiusMeters, latitude float64) int {
+    // Longitude degrees shrink towards the poles
+    lonScale := math.Max(math.Cos(toRadians(latitude)), 0.01)
+
+    for precision := 9; precision > 1; precision-- {
+        latDegrees, lonDegrees := CellSize(precision)
+        minMeters := math.Min(latDegrees, lonDegrees*lonScale) * metersPerDegree
+        if minMeters >= radiusMeters {
+            return precision
+        }
+    }
+    return 1
+}
+
+// CoveringCells returns the cell containing the coordinate plus its eight neighbours
+func CoveringCells(latitude, longitude float64, precision int) []string {
+    latDegrees, lonDegrees := CellSize(precision)
+
+    seen := make(map[string]bool)
+    var cells []string
+    for _, dLat := range []float64{-latDegrees, 0, latDegrees} {
+        for _, dLon := range []float64{-lonDegrees, 0, lonDegrees} {
+            lat := math.Max(-90, math.Min(90, latitude+dLat))
+            lon := wrapLongitude(longitude + dLon)
+            cell := Encode(lat, lon, precision)
+            if !seen[cell] {
+                seen[cell] = true
+                cells = append(cells, cell)
+            }
+        }
+    }
+
+    return cells
+}
+
+const (
+    earthRadiusMeters = 6371000.0
+    metersPerDegree   = earthRadiusMeters * math.Pi / 180
+)
+
+func toRadians(degrees float64) float64 {
+    return degrees * math.Pi / 180
+}
+
+func wrapLongitude(longitude float64) float64 {
+    for longitude > 180 {
+        longitude -= 360
+    }
+    for longitude < -180 {
+        longitude += 360
+    }
+    return longitude
+}
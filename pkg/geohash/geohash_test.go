@@ -0,0 +1,151 @@
+package geohash
+
+import (
+    "math"
+    "testing"
+)
+
+func TestEncode(t *testing.T) {
+    tests := []struct {
+        name      string
+        latitude  float64
+        longitude float64
+        precision int
+        want      string
+    }{
+        {name: "reference point", latitude: 57.64911, longitude: 10.40744, precision: 11, want: "u4pruydqqvj"},
+        {name: "short hash", latitude: 42.6, longitude: -5.6, precision: 5, want: "ezs42"},
+        {name: "southern hemisphere", latitude: -25.382708, longitude: -49.265506, precision: 12, want: "6gkzwgjzn820"},
+        {name: "origin", latitude: 0, longitude: 0, precision: 1, want: "s"},
+        {name: "north pole", latitude: 90, longitude: 0, precision: 1, want: "u"},
+        {name: "south pole", latitude: -90, longitude: 0, precision: 1, want: "h"},
+        {name: "north east corner", latitude: 90, longitude: 180, precision: 5, want: "zzzzz"},
+        {name: "south west corner", latitude: -90, longitude: -180, precision: 5, want: "00000"},
+        {name: "east of the antimeridian", latitude: 0, longitude: 180, precision: 3, want: "xbp"},
+        {name: "west of the antimeridian", latitude: 0, longitude: -180, precision: 3, want: "800"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Encode(tt.latitude, tt.longitude, tt.precision); got != tt.want {
+                t.Errorf("Encode(%v, %v, %d) = %s, want %s", tt.latitude, tt.longitude, tt.precision, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestCellSize(t *testing.T) {
+    tests := []struct {
+        precision  int
+        latDegrees float64
+        lonDegrees float64
+    }{
+        {precision: 1, latDegrees: 45, lonDegrees: 45},
+        {precision: 2, latDegrees: 5.625, lonDegrees: 11.25},
+        {precision: 5, latDegrees: 0.0439453125, lonDegrees: 0.0439453125},
+        {precision: 6, latDegrees: 0.0054931640625, lonDegrees: 0.010986328125},
+    }
+
+    for _, tt := range tests {
+        latDegrees, lonDegrees := CellSize(tt.precision)
+        if latDegrees != tt.latDegrees || lonDegrees != tt.lonDegrees {
+            t.Errorf("CellSize(%d) = %v, %v, want %v, %v", tt.precision, latDegrees, lonDegrees, tt.latDegrees, tt.lonDegrees)
+        }
+    }
+}
+
+func TestPrecisionForRadius(t *testing.T) {
+    tests := []struct {
+        name         string
+        radiusMeters float64
+        latitude     float64
+        want         int
+    }{
+        {name: "street", radiusMeters: 20, latitude: 0, want: 7},
+        {name: "neighbourhood", radiusMeters: 1000, latitude: 0, want: 5},
+        {name: "city", radiusMeters: 5000, latitude: 0, want: 4},
+        {name: "region", radiusMeters: 50000, latitude: 0, want: 3},
+        {name: "tiny radius", radiusMeters: 1, latitude: 0, want: 9},
+        {name: "high latitude", radiusMeters: 1000, latitude: 60, want: 5},
+        {name: "near the pole", radiusMeters: 5000, latitude: 85, want: 3},
+        {name: "pole", radiusMeters: 1000, latitude: 90, want: 3},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := PrecisionForRadius(tt.radiusMeters, tt.latitude); got != tt.want {
+                t.Errorf("PrecisionForRadius(%v, %v) = %d, want %d", tt.radiusMeters, tt.latitude, got, tt.want)
+            }
+        })
+    }
+}
+
+// destination returns the coordinate distanceMeters from a point along a bearing
+func destination(latitude, longitude, distanceMeters, bearingDegrees float64) (float64, float64) {
+    lat1 := toRadians(latitude)
+    lon1 := toRadians(longitude)
+    bearing := toRadians(bearingDegrees)
+    angle := distanceMeters / earthRadiusMeters
+
+    lat2 := math.Asin(math.Sin(lat1)*math.Cos(angle) + math.Cos(lat1)*math.Sin(angle)*math.Cos(bearing))
+    lon2 := lon1 + math.Atan2(math.Sin(bearing)*math.Sin(angle)*math.Cos(lat1), math.Cos(angle)-math.Sin(lat1)*math.Sin(lat2))
+
+    return lat2 * 180 / math.Pi, wrapLongitude(lon2 * 180 / math.Pi)
+}
+
+func TestCoveringCellsCoverRadius(t *testing.T) {
+    tests := []struct {
+        name         string
+        latitude     float64
+        longitude    float64
+        radiusMeters float64
+    }{
+        {name: "equator", latitude: 0, longitude: 0, radiusMeters: 1000},
+        {name: "cell corner", latitude: 45, longitude: 45, radiusMeters: 5000},
+        {name: "east of the antimeridian", latitude: 10, longitude: 179.999, radiusMeters: 5000},
+        {name: "west of the antimeridian", latitude: -10, longitude: -179.999, radiusMeters: 5000},
+        {name: "far north", latitude: 85, longitude: 30, radiusMeters: 5000},
+        {name: "far south", latitude: -85, longitude: -120, radiusMeters: 20000},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            precision := PrecisionForRadius(tt.radiusMeters, tt.latitude)
+            cells := make(map[string]bool)
+            for _, cell := range CoveringCells(tt.latitude, tt.longitude, precision) {
+                cells[cell] = true
+            }
+
+            // Every point just inside the circle must fall in one of the cells
+            for bearing := 0.0; bearing < 360; bearing += 15 {
+                lat, lon := destination(tt.latitude, tt.longitude, tt.radiusMeters*0.99, bearing)
+                if cell := Encode(lat, lon, precision); !cells[cell] {
+                    t.Errorf("point at bearing %v (%v, %v) is in uncovered cell %s", bearing, lat, lon, cell)
+                }
+            }
+        })
+    }
+}
+
+func TestCoveringCellsWrapAntimeridian(t *testing.T) {
+    cells := CoveringCells(0, 179.99, 3)
+
+    found := false
+    for _, cell := range cells {
+        if cell == Encode(0, -179.99, 3) {
+            found = true
+        }
+    }
+    if !found {
+        t.Errorf("cells %v don't include the neighbour across the antimeridian", cells)
+    }
+}
+
+func TestCoveringCellsAtPole(t *testing.T) {
+    cells := CoveringCells(89.9, 0, 2)
+
+    // The cells above the pole clamp onto the top row without repeating it
+    if len(cells) != 6 {
+        t.Errorf("got %d cells %v, want the pole's row and the one below", len(cells), cells)
+    }
+}
@@ -27,6 +27,12 @@ type Collector struct {
     ActiveWebSocketConnections prometheus.Gauge
     WebSocketMessages          *prometheus.CounterVec
     
+    RealtimeEventsDropped   *prometheus.CounterVec
+    RealtimeEventsCoalesced *prometheus.CounterVec
+    RealtimeClientsEvicted  *prometheus.CounterVec
+    RealtimeInboundLimited  prometheus.Counter
+    RealtimeRelayDropped    *prometheus.CounterVec
+    
     WorkerJobsProcessed *prometheus.CounterVec
     WorkerJobDuration   *prometheus.HistogramVec
     WorkerQueueSize     *prometheus.GaugeVec
@@ -152,6 +158,45 @@ func NewCollector() *Collector {
             []string{"type", "direction"},
         ),
         
+        RealtimeEventsDropped: promauto.NewCounterVec(
+            prometheus.CounterOpts{
+                Name: "realtime_events_dropped_total",
+                Help: "Total number of realtime events dropped for slow clients",
+            },
+            []string{"priority", "reason"},
+        ),
+        
+        RealtimeEventsCoalesced: promauto.NewCounterVec(
+            prometheus.CounterOpts{
+                Name: "realtime_events_coalesced_total",
+                Help: "Total number of queued realtime events replaced by newer ones",
+            },
+            []string{"type"},
+        ),
+        
+        RealtimeClientsEvicted: promauto.NewCounterVec(
+            prometheus.CounterOpts{
+                Name: "realtime_clients_evicted_total",
+                Help: "Total number of realtime clients disconnected by flow control",
+            },
+            []string{"reason"},
+        ),
+        
+        RealtimeInboundLimited: promauto.NewCounter(
+            prometheus.CounterOpts{
+                Name: "realtime_inbound_messages_limited_total",
+                Help: "Total number of client messages rejected by the inbound rate limit",
+            },
+        ),
+        
+        RealtimeRelayDropped: promauto.NewCounterVec(
+            prometheus.CounterOpts{
+                Name: "realtime_relay_messages_dropped_total",
+                Help: "Total number of hub messages not relayed to other instances",
+            },
+            []string{"kind", "reason"},
+        ),
+        
         WorkerJobsProcessed: promauto.NewCounterVec(
             prometheus.CounterOpts{
                 Name: "worker_jobs_processed_total",
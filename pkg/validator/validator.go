@@ -5,8 +5,12 @@ import (
     "unicode"
 
     "github.com/go-playground/validator/v10"
+
+    "github.com/Abhiro0p/stories-backend/pkg/emoji"
 )
 
+var reactionKeyPattern = regexp.MustCompile("^[a-z0-9_]{1,32}$")
+
 var validate *validator.Validate
 
 func init() {
@@ -18,6 +22,8 @@ func init() {
     validate.RegisterValidation("story_text", validateStoryText)
     validate.RegisterValidation("visibility", validateVisibility)
     validate.RegisterValidation("reaction_type", validateReactionType)
+    validate.RegisterValidation("reaction_key", validateReactionKey)
+    validate.RegisterValidation("emoji", validateEmoji)
 }
 
 // ValidateStruct validates a struct using validator tags
@@ -91,16 +97,19 @@ func validateVisibility(fl validator.FieldLevel) bool {
     return false
 }
 
-// validateReactionType validates reaction type values
+// validateReactionType validates the format of a reaction: a catalogue key or a single emoji.
+// Whether the reaction is currently allowed is checked against the catalogue.
 func validateReactionType(fl validator.FieldLevel) bool {
     reactionType := fl.Field().String()
-    validTypes := []string{"like", "love", "laugh", "wow", "sad", "angry", "fire", "hundred"}
-    
-    for _, valid := range validTypes {
-        if reactionType == valid {
-            return true
-        }
-    }
-    
-    return false
+    return reactionKeyPattern.MatchString(reactionType) || emoji.IsEmoji(reactionType)
+}
+
+// validateReactionKey validates reaction catalogue keys
+func validateReactionKey(fl validator.FieldLevel) bool {
+    return reactionKeyPattern.MatchString(fl.Field().String())
+}
+
+// validateEmoji validates that a field holds exactly one emoji
+func validateEmoji(fl validator.FieldLevel) bool {
+    return emoji.IsEmoji(fl.Field().String())
 }